@@ -6,49 +6,79 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
-	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/migrations"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
+// InitDB connects to the database and refuses to continue when the schema
+// is behind the migrations shipped with this build.
 func InitDB() {
+	ConnectDB()
+
+	pending, err := migrations.NewMigrator(DB).Pending()
+	if err != nil {
+		log.Fatal("Failed to read schema version:", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Database schema is behind by %d migration(s), run `go run main.go migrate up` first", len(pending))
+	}
+	fmt.Fprintln(os.Stderr, "Database schema is up to date!")
+}
+
+// ConnectDB loads .env and connects to the database without checking its schema.
+func ConnectDB() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
 
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_SSLMODE"),
-		os.Getenv("DB_TIMEZONE"),
-	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := Open(os.Getenv("DB_NAME"))
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
 	DB = db
-	fmt.Println("Connected to PostgreSQL!")
+	// Status goes to stderr, the export command writes its data to stdout.
+	fmt.Fprintf(os.Stderr, "Connected to %s!\n", db.Dialector.Name())
+}
 
-	migErr := DB.AutoMigrate(&dbModels.PolishWord{}, &dbModels.EnglishWord{}, &dbModels.Translation{}, &dbModels.Example{})
-	if migErr != nil {
-		log.Fatal("Failed to migrate database:", migErr)
+// Open connects to the database selected by DB_DRIVER ("postgres" when
+// unset, or "sqlite"). For SQLite dbName is the path of the database file.
+func Open(dbName string) (*gorm.DB, error) {
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "postgres":
+		dsn := fmt.Sprintf(
+			"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+			os.Getenv("DB_HOST"),
+			os.Getenv("DB_USER"),
+			os.Getenv("DB_PASSWORD"),
+			dbName,
+			os.Getenv("DB_PORT"),
+			os.Getenv("DB_SSLMODE"),
+			os.Getenv("DB_TIMEZONE"),
+		)
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	case "sqlite":
+		// Foreign keys are off by default in SQLite and cascades depend on them.
+		dsn := fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000", dbName)
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			return nil, err
+		}
+		// SQLite allows a single writer, so concurrent transactions would fail
+		// with "database is locked" instead of waiting for each other.
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
 	}
-	// GORM didn't apply on delete cascade to foreign key
-	db.Exec(`ALTER TABLE examples 
-         DROP CONSTRAINT IF EXISTS fk_translations_examples;
-         ALTER TABLE examples
-         ADD CONSTRAINT fk_translations_examples 
-         FOREIGN KEY (translation_id) 
-         REFERENCES translations(id) 
-         ON DELETE CASCADE;`)
-	fmt.Println("Database migration complete!")
 }
@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/auth"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// hasRole enforces @hasRole before the resolver of a field runs. Without
+// authentication every field is open, as there is nobody to tell apart.
+func hasRole(authEnabled bool, allowAnonymousReads bool) func(ctx context.Context, obj any, next graphql.Resolver, role model.Role) (any, error) {
+	return func(ctx context.Context, obj any, next graphql.Resolver, role model.Role) (any, error) {
+		if !authEnabled {
+			return next(ctx)
+		}
+		required := auth.Role(role)
+		principal := auth.PrincipalFrom(ctx)
+		if principal == nil {
+			if allowAnonymousReads && required == auth.RoleReader {
+				return next(ctx)
+			}
+			return nil, customErrors.ErrUnauthenticated
+		}
+		if !principal.Role.Includes(required) {
+			return nil, &gqlerror.Error{
+				Err:        customErrors.ErrForbidden,
+				Message:    customErrors.ErrForbidden.Error(),
+				Extensions: map[string]any{"requiredRole": role},
+			}
+		}
+		return next(ctx)
+	}
+}
@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// errorCodes are put into the extensions of errors clients need to tell
+// apart without reading their messages.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{customErrors.ErrUnauthenticated, "UNAUTHENTICATED"},
+	{customErrors.ErrForbidden, "FORBIDDEN"},
+	{customErrors.ErrVersionConflict, "CONFLICT"},
+}
+
+// presentError adds the code of err to the extensions of the error sent.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	for _, errorCode := range errorCodes {
+		if !errors.Is(err, errorCode.err) {
+			continue
+		}
+		if presented.Extensions == nil {
+			presented.Extensions = map[string]any{}
+		}
+		presented.Extensions["code"] = errorCode.code
+		break
+	}
+	return presented
+}
@@ -0,0 +1,130 @@
+package loaders
+
+import (
+	"context"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/vikstrous/dataloadgen"
+)
+
+type ctxKey struct{}
+
+// Loaders batch the association lookups of a single GraphQL operation, so
+// resolving a field on every element of a list costs one store call.
+type Loaders struct {
+	PolishWords  *dataloadgen.Loader[uint, *dbModels.PolishWord]
+	EnglishWords *dataloadgen.Loader[uint, *dbModels.EnglishWord]
+	Examples     *dataloadgen.Loader[uint, []dbModels.Example]
+	WordForms    *dataloadgen.Loader[uint, []*dbModels.WordForm]
+	Tags         *dataloadgen.Loader[uint, []string]
+}
+
+const (
+	batchCapacity = 1000
+	// DefaultBatchWait is how long loaders collect lookups into one batch.
+	DefaultBatchWait = time.Millisecond
+)
+
+// NewLoaders returns loaders collecting lookups for batchWait, or
+// DefaultBatchWait when it is zero.
+func NewLoaders(store database.DictionaryStore, batchWait time.Duration) *Loaders {
+	if batchWait == 0 {
+		batchWait = DefaultBatchWait
+	}
+	options := []dataloadgen.Option{dataloadgen.WithBatchCapacity(batchCapacity), dataloadgen.WithWait(batchWait)}
+	return &Loaders{
+		PolishWords: dataloadgen.NewLoader(func(ctx context.Context, ids []uint) ([]*dbModels.PolishWord, []error) {
+			words, err := store.GetPolishWordsByIds(ids)
+			if err != nil {
+				return nil, []error{err}
+			}
+			return byKey(ids, words, func(word *dbModels.PolishWord) uint { return word.ID }, customErrors.ErrPolishWordNotFound)
+		}, options...),
+		EnglishWords: dataloadgen.NewLoader(func(ctx context.Context, ids []uint) ([]*dbModels.EnglishWord, []error) {
+			words, err := store.GetEnglishWordsByIds(ids)
+			if err != nil {
+				return nil, []error{err}
+			}
+			return byKey(ids, words, func(word *dbModels.EnglishWord) uint { return word.ID }, customErrors.ErrEnglishWordNotFound)
+		}, options...),
+		Examples: dataloadgen.NewLoader(func(ctx context.Context, translationIDs []uint) ([][]dbModels.Example, []error) {
+			examples, err := store.GetExamplesByTranslationIds(translationIDs)
+			if err != nil {
+				return nil, []error{err}
+			}
+			grouped := make(map[uint][]dbModels.Example, len(translationIDs))
+			for _, example := range examples {
+				grouped[example.TranslationID] = append(grouped[example.TranslationID], *example)
+			}
+			result := make([][]dbModels.Example, len(translationIDs))
+			for i, id := range translationIDs {
+				result[i] = grouped[id]
+			}
+			return result, nil
+		}, options...),
+		WordForms: dataloadgen.NewLoader(func(ctx context.Context, polishWordIDs []uint) ([][]*dbModels.WordForm, []error) {
+			forms, err := store.GetWordFormsByPolishWordIds(polishWordIDs)
+			if err != nil {
+				return nil, []error{err}
+			}
+			grouped := make(map[uint][]*dbModels.WordForm, len(polishWordIDs))
+			for _, form := range forms {
+				grouped[form.PolishWordID] = append(grouped[form.PolishWordID], form)
+			}
+			result := make([][]*dbModels.WordForm, len(polishWordIDs))
+			for i, id := range polishWordIDs {
+				result[i] = grouped[id]
+			}
+			return result, nil
+		}, options...),
+		Tags: dataloadgen.NewLoader(func(ctx context.Context, translationIDs []uint) ([][]string, []error) {
+			tags, err := store.GetTagsByTranslationIds(translationIDs)
+			if err != nil {
+				return nil, []error{err}
+			}
+			grouped := make(map[uint][]string, len(translationIDs))
+			for _, tag := range tags {
+				grouped[tag.TranslationID] = append(grouped[tag.TranslationID], tag.Name)
+			}
+			result := make([][]string, len(translationIDs))
+			for i, id := range translationIDs {
+				result[i] = grouped[id]
+				if result[i] == nil {
+					result[i] = []string{}
+				}
+			}
+			return result, nil
+		}, options...),
+	}
+}
+
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loaders)
+}
+
+// For returns the loaders of the operation ctx belongs to.
+func For(ctx context.Context) *Loaders {
+	return ctx.Value(ctxKey{}).(*Loaders)
+}
+
+// byKey orders records to match keys, reporting notFound for missing ones.
+func byKey[T any](keys []uint, records []*T, id func(*T) uint, notFound error) ([]*T, []error) {
+	byID := make(map[uint]*T, len(records))
+	for _, record := range records {
+		byID[id(record)] = record
+	}
+	result := make([]*T, len(keys))
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		record, ok := byID[key]
+		if !ok {
+			errs[i] = notFound
+			continue
+		}
+		result[i] = record
+	}
+	return result, errs
+}
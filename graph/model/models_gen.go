@@ -2,23 +2,180 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Revision interface {
+	IsRevision()
+}
+
+type RevisionedEntity interface {
+	IsRevisionedEntity()
+}
+
+// prefix is the start of the key, to tell keys apart.
+type APIKey struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// A change made by a mutation. before and after hold the entity as JSON, in the
+// shape queries return it. before is null for creations and reviews, after for
+// deletions. actor is null when the server runs without authentication.
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Actor      *string         `json:"actor,omitempty"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Operation  string          `json:"operation"`
+	EntityType AuditEntityType `json:"entityType"`
+	EntityID   int             `json:"entityID"`
+	Before     *string         `json:"before,omitempty"`
+	After      *string         `json:"after,omitempty"`
+}
+
+type AuditEntryConnection struct {
+	Edges      []*AuditEntryEdge `json:"edges"`
+	PageInfo   *PageInfo         `json:"pageInfo"`
+	TotalCount int32             `json:"totalCount"`
+}
+
+type AuditEntryEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *AuditEntry `json:"node"`
+}
+
+// Send token as "Authorization: Bearer <token>" until it expires.
+type AuthPayload struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	User      *User     `json:"user"`
+}
+
+// A translation studied in one direction, scheduled with SM-2. A card that was
+// never reviewed is new, its counters are zero and it is due right away.
+type Card struct {
+	TranslationID  int            `json:"translationID"`
+	Translation    *Translation   `json:"translation"`
+	Direction      StudyDirection `json:"direction"`
+	DueAt          time.Time      `json:"dueAt"`
+	IntervalDays   int32          `json:"intervalDays"`
+	Repetitions    int32          `json:"repetitions"`
+	Lapses         int32          `json:"lapses"`
+	EaseFactor     float64        `json:"easeFactor"`
+	LastReviewedAt *time.Time     `json:"lastReviewedAt,omitempty"`
+	New            bool           `json:"new"`
+}
+
 type EnglishWord struct {
-	ID   int    `json:"id"`
-	Text string `json:"text"`
+	ID           int           `json:"id"`
+	Text         string        `json:"text"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	// Grows with every change of the word, see updateEnglishWordText.
+	Version int32 `json:"version"`
+	// Versions of the word, oldest first.
+	History []*EnglishWordRevision `json:"-"`
 }
 
+func (EnglishWord) IsRevisionedEntity() {}
+
+type EnglishWordConnection struct {
+	Edges      []*EnglishWordEdge `json:"edges"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int32              `json:"totalCount"`
+}
+
+type EnglishWordEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *EnglishWord `json:"node"`
+}
+
+// A version of an English word, like PolishWordRevision.
+type EnglishWordRevision struct {
+	Version      int32         `json:"version"`
+	Text         string        `json:"text"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Deleted      bool          `json:"deleted"`
+	Actor        *string       `json:"actor,omitempty"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+}
+
+func (EnglishWordRevision) IsRevision() {}
+
 type Example struct {
 	ID            int    `json:"id"`
 	Text          string `json:"text"`
 	InPolish      bool   `json:"inPolish"`
 	TranslationID int    `json:"translationID"`
+	// Grows with every change of the example, see updateExampleText.
+	Version int32 `json:"version"`
+	// Versions of the example, oldest first.
+	History []*ExampleRevision `json:"-"`
 }
 
+func (Example) IsRevisionedEntity() {}
+
 type ExampleInput struct {
 	Text     string `json:"text"`
 	InPolish bool   `json:"inPolish"`
 }
 
+// A version of an example, like PolishWordRevision.
+type ExampleRevision struct {
+	Version       int32     `json:"version"`
+	TranslationID int       `json:"translationID"`
+	Text          string    `json:"text"`
+	InPolish      bool      `json:"inPolish"`
+	Deleted       bool      `json:"deleted"`
+	Actor         *string   `json:"actor,omitempty"`
+	RecordedAt    time.Time `json:"recordedAt"`
+}
+
+func (ExampleRevision) IsRevision() {}
+
+type ExampleSearchConnection struct {
+	Edges      []*ExampleSearchEdge `json:"edges"`
+	PageInfo   *PageInfo            `json:"pageInfo"`
+	TotalCount int32                `json:"totalCount"`
+}
+
+type ExampleSearchEdge struct {
+	Cursor string               `json:"cursor"`
+	Node   *ExampleSearchResult `json:"node"`
+}
+
+// An example found by searchExamples. snippet is its text with the matched
+// words wrapped in <mark></mark>, a higher rank means a more relevant match.
+type ExampleSearchResult struct {
+	Example *Example `json:"example"`
+	Snippet string   `json:"snippet"`
+	Rank    float64  `json:"rank"`
+}
+
+type ImportReport struct {
+	DryRun         bool               `json:"dryRun"`
+	Created        int32              `json:"created"`
+	Updated        int32              `json:"updated"`
+	AlreadyExisted int32              `json:"alreadyExisted"`
+	Failed         int32              `json:"failed"`
+	Rows           []*ImportRowResult `json:"rows"`
+}
+
+// Outcome of an imported row, line is its line in the file.
+type ImportRowResult struct {
+	Line          int32           `json:"line"`
+	PolishWord    string          `json:"polishWord"`
+	EnglishWord   string          `json:"englishWord"`
+	Status        ImportRowStatus `json:"status"`
+	TranslationID *int            `json:"translationID,omitempty"`
+	Reason        *string         `json:"reason,omitempty"`
+}
+
 type IndividualExampleInput struct {
 	TranslationID int           `json:"translationID"`
 	Example       *ExampleInput `json:"example"`
@@ -27,23 +184,832 @@ type IndividualExampleInput struct {
 type Mutation struct {
 }
 
+// key is shown only once, it cannot be read again.
+type NewAPIKey struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"apiKey"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+// Words are unique by their text and part of speech, so "run" the noun and
+// "run" the verb are separate entries. Grammatical fields are null when
+// unspecified.
 type PolishWord struct {
-	ID   int    `json:"id"`
-	Text string `json:"text"`
+	ID           int           `json:"id"`
+	Text         string        `json:"text"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Gender       *Gender       `json:"gender,omitempty"`
+	Aspect       *Aspect       `json:"aspect,omitempty"`
+	Forms        []*WordForm   `json:"forms"`
+	// Grows with every change of the word, see updatePolishWordText.
+	Version int32 `json:"version"`
+	// Versions of the word, oldest first.
+	History []*PolishWordRevision `json:"-"`
+}
+
+func (PolishWord) IsRevisionedEntity() {}
+
+type PolishWordConnection struct {
+	Edges      []*PolishWordEdge `json:"edges"`
+	PageInfo   *PageInfo         `json:"pageInfo"`
+	TotalCount int32             `json:"totalCount"`
+}
+
+type PolishWordEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *PolishWord `json:"node"`
+}
+
+// A version of a Polish word. Versions count from 1, every change adds one,
+// restores included. A deleted revision holds the word as it was deleted.
+// actor is null when the server runs without authentication. Entries get their
+// first versions on their first change.
+type PolishWordRevision struct {
+	Version      int32         `json:"version"`
+	Text         string        `json:"text"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Gender       *Gender       `json:"gender,omitempty"`
+	Aspect       *Aspect       `json:"aspect,omitempty"`
+	Deleted      bool          `json:"deleted"`
+	Actor        *string       `json:"actor,omitempty"`
+	RecordedAt   time.Time     `json:"recordedAt"`
 }
 
+func (PolishWordRevision) IsRevision() {}
+
 type Query struct {
 }
 
-type Translation struct {
-	ID          int          `json:"id"`
-	PolishWord  *PolishWord  `json:"polishWord"`
-	EnglishWord *EnglishWord `json:"englishWord"`
-	Examples    []*Example   `json:"examples"`
+// Generating a quiz with the same seed from the same data gives the same quiz.
+type Quiz struct {
+	Seed      int32           `json:"seed"`
+	Questions []*QuizQuestion `json:"questions"`
+}
+
+type QuizAnswerInput struct {
+	TranslationID int            `json:"translationID"`
+	Direction     StudyDirection `json:"direction"`
+	Answer        string         `json:"answer"`
+}
+
+type QuizAnswerResult struct {
+	TranslationID int            `json:"translationID"`
+	Direction     StudyDirection `json:"direction"`
+	Answer        string         `json:"answer"`
+	Correct       bool           `json:"correct"`
+	CorrectAnswer string         `json:"correctAnswer"`
+}
+
+// Asks for the translation of prompt, answer is one of choices. cloze is an
+// example of the translation with the answer replaced by "____".
+type QuizQuestion struct {
+	TranslationID int            `json:"translationID"`
+	Direction     StudyDirection `json:"direction"`
+	Prompt        string         `json:"prompt"`
+	Choices       []string       `json:"choices"`
+	Answer        string         `json:"answer"`
+	Cloze         *string        `json:"cloze,omitempty"`
+}
+
+// score is the share of correct answers.
+type QuizResult struct {
+	Correct int32               `json:"correct"`
+	Total   int32               `json:"total"`
+	Score   float64             `json:"score"`
+	Answers []*QuizAnswerResult `json:"answers"`
+}
+
+// An existing word close to the looked up one. distance counts the edits
+// between them ignoring case and diacritics, score scales it to a value between
+// 0 and 1 where 1 is the closest match.
+type Suggestion struct {
+	ID       int      `json:"id"`
+	Text     string   `json:"text"`
+	Language Language `json:"language"`
+	Distance int32    `json:"distance"`
+	Score    float64  `json:"score"`
+}
+
+type TranslationConnection struct {
+	Edges      []*TranslationEdge `json:"edges"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int32              `json:"totalCount"`
+}
+
+type TranslationEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *Translation `json:"node"`
 }
 
+// partOfSpeech applies to both words, polishGender and polishAspect to the Polish
+// one. Only nouns have a gender and only verbs an aspect. A Polish word that
+// exists with another gender or aspect fails the mutation, leaving them unset
+// keeps the stored ones.
 type TranslationInput struct {
-	PolishWord  string          `json:"polishWord"`
-	EnglishWord string          `json:"englishWord"`
-	Examples    []*ExampleInput `json:"examples,omitempty"`
+	PolishWord   string          `json:"polishWord"`
+	EnglishWord  string          `json:"englishWord"`
+	Examples     []*ExampleInput `json:"examples,omitempty"`
+	PartOfSpeech *PartOfSpeech   `json:"partOfSpeech,omitempty"`
+	PolishGender *Gender         `json:"polishGender,omitempty"`
+	PolishAspect *Aspect         `json:"polishAspect,omitempty"`
+}
+
+// A version of a translation, like PolishWordRevision.
+type TranslationRevision struct {
+	Version       int32     `json:"version"`
+	PolishWordID  int       `json:"polishWordID"`
+	EnglishWordID int       `json:"englishWordID"`
+	Deleted       bool      `json:"deleted"`
+	Actor         *string   `json:"actor,omitempty"`
+	RecordedAt    time.Time `json:"recordedAt"`
+}
+
+func (TranslationRevision) IsRevision() {}
+
+// A deleted entry waiting in the trash. text is the text of a word or example,
+// for a translation its words as "polish - english".
+type TrashItem struct {
+	Entity    RevisionEntity `json:"entity"`
+	ID        int            `json:"id"`
+	Text      string         `json:"text"`
+	DeletedAt time.Time      `json:"deletedAt"`
+}
+
+type User struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WordEntry struct {
+	ID               int      `json:"id"`
+	Text             string   `json:"text"`
+	Language         Language `json:"language"`
+	TranslationCount int32    `json:"translationCount"`
+	LookupCount      int32    `json:"lookupCount"`
+}
+
+// An inflected form of a Polish word, tags are null when unspecified.
+type WordForm struct {
+	ID           int                `json:"id"`
+	PolishWordID int                `json:"polishWordID"`
+	Text         string             `json:"text"`
+	Case         *GrammaticalCase   `json:"case,omitempty"`
+	Number       *GrammaticalNumber `json:"number,omitempty"`
+	Person       *Person            `json:"person,omitempty"`
+}
+
+type WordFormInput struct {
+	Text   string             `json:"text"`
+	Case   *GrammaticalCase   `json:"case,omitempty"`
+	Number *GrammaticalNumber `json:"number,omitempty"`
+	Person *Person            `json:"person,omitempty"`
+}
+
+// Aspect of Polish verbs.
+type Aspect string
+
+const (
+	AspectImperfective Aspect = "IMPERFECTIVE"
+	AspectPerfective   Aspect = "PERFECTIVE"
+)
+
+var AllAspect = []Aspect{
+	AspectImperfective,
+	AspectPerfective,
+}
+
+func (e Aspect) IsValid() bool {
+	switch e {
+	case AspectImperfective, AspectPerfective:
+		return true
+	}
+	return false
+}
+
+func (e Aspect) String() string {
+	return string(e)
+}
+
+func (e *Aspect) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Aspect(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Aspect", str)
+	}
+	return nil
+}
+
+func (e Aspect) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AuditEntityType string
+
+const (
+	AuditEntityTypePolishWord  AuditEntityType = "POLISH_WORD"
+	AuditEntityTypeEnglishWord AuditEntityType = "ENGLISH_WORD"
+	AuditEntityTypeTranslation AuditEntityType = "TRANSLATION"
+	AuditEntityTypeExample     AuditEntityType = "EXAMPLE"
+	AuditEntityTypeUser        AuditEntityType = "USER"
+	AuditEntityTypeAPIKey      AuditEntityType = "API_KEY"
+	AuditEntityTypeCard        AuditEntityType = "CARD"
+)
+
+var AllAuditEntityType = []AuditEntityType{
+	AuditEntityTypePolishWord,
+	AuditEntityTypeEnglishWord,
+	AuditEntityTypeTranslation,
+	AuditEntityTypeExample,
+	AuditEntityTypeUser,
+	AuditEntityTypeAPIKey,
+	AuditEntityTypeCard,
+}
+
+func (e AuditEntityType) IsValid() bool {
+	switch e {
+	case AuditEntityTypePolishWord, AuditEntityTypeEnglishWord, AuditEntityTypeTranslation, AuditEntityTypeExample, AuditEntityTypeUser, AuditEntityTypeAPIKey, AuditEntityTypeCard:
+		return true
+	}
+	return false
+}
+
+func (e AuditEntityType) String() string {
+	return string(e)
+}
+
+func (e *AuditEntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditEntityType", str)
+	}
+	return nil
+}
+
+func (e AuditEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AutocompleteRanking string
+
+const (
+	AutocompleteRankingAlphabetical     AutocompleteRanking = "ALPHABETICAL"
+	AutocompleteRankingTranslationCount AutocompleteRanking = "TRANSLATION_COUNT"
+	// How often the word was found by translationToEnglish or translationToPolish.
+	AutocompleteRankingPopularity AutocompleteRanking = "POPULARITY"
+)
+
+var AllAutocompleteRanking = []AutocompleteRanking{
+	AutocompleteRankingAlphabetical,
+	AutocompleteRankingTranslationCount,
+	AutocompleteRankingPopularity,
+}
+
+func (e AutocompleteRanking) IsValid() bool {
+	switch e {
+	case AutocompleteRankingAlphabetical, AutocompleteRankingTranslationCount, AutocompleteRankingPopularity:
+		return true
+	}
+	return false
+}
+
+func (e AutocompleteRanking) String() string {
+	return string(e)
+}
+
+func (e *AutocompleteRanking) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutocompleteRanking(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutocompleteRanking", str)
+	}
+	return nil
+}
+
+func (e AutocompleteRanking) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Grammatical gender of Polish words, masculine split by the forms it takes.
+type Gender string
+
+const (
+	GenderMasculinePersonal  Gender = "MASCULINE_PERSONAL"
+	GenderMasculineAnimate   Gender = "MASCULINE_ANIMATE"
+	GenderMasculineInanimate Gender = "MASCULINE_INANIMATE"
+	GenderFeminine           Gender = "FEMININE"
+	GenderNeuter             Gender = "NEUTER"
+)
+
+var AllGender = []Gender{
+	GenderMasculinePersonal,
+	GenderMasculineAnimate,
+	GenderMasculineInanimate,
+	GenderFeminine,
+	GenderNeuter,
+}
+
+func (e Gender) IsValid() bool {
+	switch e {
+	case GenderMasculinePersonal, GenderMasculineAnimate, GenderMasculineInanimate, GenderFeminine, GenderNeuter:
+		return true
+	}
+	return false
+}
+
+func (e Gender) String() string {
+	return string(e)
+}
+
+func (e *Gender) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Gender(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Gender", str)
+	}
+	return nil
+}
+
+func (e Gender) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GrammaticalCase string
+
+const (
+	GrammaticalCaseNominative   GrammaticalCase = "NOMINATIVE"
+	GrammaticalCaseGenitive     GrammaticalCase = "GENITIVE"
+	GrammaticalCaseDative       GrammaticalCase = "DATIVE"
+	GrammaticalCaseAccusative   GrammaticalCase = "ACCUSATIVE"
+	GrammaticalCaseInstrumental GrammaticalCase = "INSTRUMENTAL"
+	GrammaticalCaseLocative     GrammaticalCase = "LOCATIVE"
+	GrammaticalCaseVocative     GrammaticalCase = "VOCATIVE"
+)
+
+var AllGrammaticalCase = []GrammaticalCase{
+	GrammaticalCaseNominative,
+	GrammaticalCaseGenitive,
+	GrammaticalCaseDative,
+	GrammaticalCaseAccusative,
+	GrammaticalCaseInstrumental,
+	GrammaticalCaseLocative,
+	GrammaticalCaseVocative,
+}
+
+func (e GrammaticalCase) IsValid() bool {
+	switch e {
+	case GrammaticalCaseNominative, GrammaticalCaseGenitive, GrammaticalCaseDative, GrammaticalCaseAccusative, GrammaticalCaseInstrumental, GrammaticalCaseLocative, GrammaticalCaseVocative:
+		return true
+	}
+	return false
+}
+
+func (e GrammaticalCase) String() string {
+	return string(e)
+}
+
+func (e *GrammaticalCase) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GrammaticalCase(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GrammaticalCase", str)
+	}
+	return nil
+}
+
+func (e GrammaticalCase) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GrammaticalNumber string
+
+const (
+	GrammaticalNumberSingular GrammaticalNumber = "SINGULAR"
+	GrammaticalNumberPlural   GrammaticalNumber = "PLURAL"
+)
+
+var AllGrammaticalNumber = []GrammaticalNumber{
+	GrammaticalNumberSingular,
+	GrammaticalNumberPlural,
+}
+
+func (e GrammaticalNumber) IsValid() bool {
+	switch e {
+	case GrammaticalNumberSingular, GrammaticalNumberPlural:
+		return true
+	}
+	return false
+}
+
+func (e GrammaticalNumber) String() string {
+	return string(e)
+}
+
+func (e *GrammaticalNumber) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GrammaticalNumber(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GrammaticalNumber", str)
+	}
+	return nil
+}
+
+func (e GrammaticalNumber) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV ImportFormat = "CSV"
+	ImportFormatTsv ImportFormat = "TSV"
+)
+
+var AllImportFormat = []ImportFormat{
+	ImportFormatCSV,
+	ImportFormatTsv,
+}
+
+func (e ImportFormat) IsValid() bool {
+	switch e {
+	case ImportFormatCSV, ImportFormatTsv:
+		return true
+	}
+	return false
+}
+
+func (e ImportFormat) String() string {
+	return string(e)
+}
+
+func (e *ImportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
+	}
+	return nil
+}
+
+func (e ImportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowStatusCreated ImportRowStatus = "CREATED"
+	// The translation existed and the row added examples to it.
+	ImportRowStatusUpdated        ImportRowStatus = "UPDATED"
+	ImportRowStatusAlreadyExisted ImportRowStatus = "ALREADY_EXISTED"
+	ImportRowStatusFailed         ImportRowStatus = "FAILED"
+)
+
+var AllImportRowStatus = []ImportRowStatus{
+	ImportRowStatusCreated,
+	ImportRowStatusUpdated,
+	ImportRowStatusAlreadyExisted,
+	ImportRowStatusFailed,
+}
+
+func (e ImportRowStatus) IsValid() bool {
+	switch e {
+	case ImportRowStatusCreated, ImportRowStatusUpdated, ImportRowStatusAlreadyExisted, ImportRowStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ImportRowStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportRowStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportRowStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportRowStatus", str)
+	}
+	return nil
+}
+
+func (e ImportRowStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Language string
+
+const (
+	LanguagePolish  Language = "POLISH"
+	LanguageEnglish Language = "ENGLISH"
+)
+
+var AllLanguage = []Language{
+	LanguagePolish,
+	LanguageEnglish,
+}
+
+func (e Language) IsValid() bool {
+	switch e {
+	case LanguagePolish, LanguageEnglish:
+		return true
+	}
+	return false
+}
+
+func (e Language) String() string {
+	return string(e)
+}
+
+func (e *Language) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Language(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Language", str)
+	}
+	return nil
+}
+
+func (e Language) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PartOfSpeech string
+
+const (
+	PartOfSpeechNoun         PartOfSpeech = "NOUN"
+	PartOfSpeechVerb         PartOfSpeech = "VERB"
+	PartOfSpeechAdjective    PartOfSpeech = "ADJECTIVE"
+	PartOfSpeechAdverb       PartOfSpeech = "ADVERB"
+	PartOfSpeechPronoun      PartOfSpeech = "PRONOUN"
+	PartOfSpeechPreposition  PartOfSpeech = "PREPOSITION"
+	PartOfSpeechConjunction  PartOfSpeech = "CONJUNCTION"
+	PartOfSpeechInterjection PartOfSpeech = "INTERJECTION"
+	PartOfSpeechNumeral      PartOfSpeech = "NUMERAL"
+	PartOfSpeechParticle     PartOfSpeech = "PARTICLE"
+	PartOfSpeechDeterminer   PartOfSpeech = "DETERMINER"
+)
+
+var AllPartOfSpeech = []PartOfSpeech{
+	PartOfSpeechNoun,
+	PartOfSpeechVerb,
+	PartOfSpeechAdjective,
+	PartOfSpeechAdverb,
+	PartOfSpeechPronoun,
+	PartOfSpeechPreposition,
+	PartOfSpeechConjunction,
+	PartOfSpeechInterjection,
+	PartOfSpeechNumeral,
+	PartOfSpeechParticle,
+	PartOfSpeechDeterminer,
+}
+
+func (e PartOfSpeech) IsValid() bool {
+	switch e {
+	case PartOfSpeechNoun, PartOfSpeechVerb, PartOfSpeechAdjective, PartOfSpeechAdverb, PartOfSpeechPronoun, PartOfSpeechPreposition, PartOfSpeechConjunction, PartOfSpeechInterjection, PartOfSpeechNumeral, PartOfSpeechParticle, PartOfSpeechDeterminer:
+		return true
+	}
+	return false
+}
+
+func (e PartOfSpeech) String() string {
+	return string(e)
+}
+
+func (e *PartOfSpeech) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PartOfSpeech(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PartOfSpeech", str)
+	}
+	return nil
+}
+
+func (e PartOfSpeech) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Person string
+
+const (
+	PersonFirst  Person = "FIRST"
+	PersonSecond Person = "SECOND"
+	PersonThird  Person = "THIRD"
+)
+
+var AllPerson = []Person{
+	PersonFirst,
+	PersonSecond,
+	PersonThird,
+}
+
+func (e Person) IsValid() bool {
+	switch e {
+	case PersonFirst, PersonSecond, PersonThird:
+		return true
+	}
+	return false
+}
+
+func (e Person) String() string {
+	return string(e)
+}
+
+func (e *Person) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Person(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Person", str)
+	}
+	return nil
+}
+
+func (e Person) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Entries whose versions are kept, which go to the trash when deleted.
+type RevisionEntity string
+
+const (
+	RevisionEntityPolishWord  RevisionEntity = "POLISH_WORD"
+	RevisionEntityEnglishWord RevisionEntity = "ENGLISH_WORD"
+	RevisionEntityTranslation RevisionEntity = "TRANSLATION"
+	RevisionEntityExample     RevisionEntity = "EXAMPLE"
+)
+
+var AllRevisionEntity = []RevisionEntity{
+	RevisionEntityPolishWord,
+	RevisionEntityEnglishWord,
+	RevisionEntityTranslation,
+	RevisionEntityExample,
+}
+
+func (e RevisionEntity) IsValid() bool {
+	switch e {
+	case RevisionEntityPolishWord, RevisionEntityEnglishWord, RevisionEntityTranslation, RevisionEntityExample:
+		return true
+	}
+	return false
+}
+
+func (e RevisionEntity) String() string {
+	return string(e)
+}
+
+func (e *RevisionEntity) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RevisionEntity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RevisionEntity", str)
+	}
+	return nil
+}
+
+func (e RevisionEntity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// What a user may do. Readers run queries and study, editors also create and
+// change entries, admins also delete them and manage users.
+type Role string
+
+const (
+	RoleReader Role = "READER"
+	RoleEditor Role = "EDITOR"
+	RoleAdmin  Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleReader,
+	RoleEditor,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleReader, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StudyDirection string
+
+const (
+	// Shows the Polish word and asks for the English one.
+	StudyDirectionPolishToEnglish StudyDirection = "POLISH_TO_ENGLISH"
+	// Shows the English word and asks for the Polish one.
+	StudyDirectionEnglishToPolish StudyDirection = "ENGLISH_TO_POLISH"
+)
+
+var AllStudyDirection = []StudyDirection{
+	StudyDirectionPolishToEnglish,
+	StudyDirectionEnglishToPolish,
+}
+
+func (e StudyDirection) IsValid() bool {
+	switch e {
+	case StudyDirectionPolishToEnglish, StudyDirectionEnglishToPolish:
+		return true
+	}
+	return false
+}
+
+func (e StudyDirection) String() string {
+	return string(e)
+}
+
+func (e *StudyDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StudyDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StudyDirection", str)
+	}
+	return nil
+}
+
+func (e StudyDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
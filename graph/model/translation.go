@@ -0,0 +1,12 @@
+package model
+
+// Translation is bound in gqlgen.yml instead of being generated. It carries
+// the foreign keys its field resolvers need to load the associations, which
+// are only fetched when a query selects them.
+type Translation struct {
+	ID            int `json:"id"`
+	PolishWordID  int `json:"-"`
+	EnglishWordID int `json:"-"`
+}
+
+func (Translation) IsRevisionedEntity() {}
@@ -5,24 +5,86 @@ package graph
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/audit"
+	"github.com/realagmag/dictionaryGO/internal/auth"
 	"github.com/realagmag/dictionaryGO/internal/converter"
 	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/history"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/srs"
 )
 
+// LearnerHeader names the learner whose cards are studied when the server
+// runs without authentication.
+const LearnerHeader = "X-Learner"
+
 type Resolver struct {
-	DBManager *database.DBManager
+	Store     database.DictionaryStore
 	Converter *converter.Converter
+	Clock     srs.Clock
+	// Auth is nil when the server runs without authentication.
+	Auth *auth.Service
 }
 
-func (r *Resolver) PrepareTranslationSliceToSend(translationDbModels *[]*dbModels.Translation) ([]*model.Translation, error) {
-	translations := make([]*model.Translation, len(*translationDbModels))
-	for i, translationDbModel := range *translationDbModels {
-		if err := r.DBManager.PopulateTranslationWithAssociations(translationDbModel); err != nil {
-			return nil, err
+// principal returns who sent the operation of ctx, or ErrUnauthenticated.
+func (r *Resolver) principal(ctx context.Context) (*auth.Principal, error) {
+	principal := auth.PrincipalFrom(ctx)
+	if r.Auth == nil {
+		return nil, customErrors.ErrAuthenticationDisabled
+	}
+	if principal == nil {
+		return nil, customErrors.ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+// audited returns the store the mutation of ctx makes its changes through,
+// recording them in the audit log and the revision history as done by its
+// sender.
+func (r *Resolver) audited(ctx context.Context) *audit.Store {
+	actor := ""
+	if principal := auth.PrincipalFrom(ctx); principal != nil {
+		actor = principal.Username
+	}
+	revisioned := history.NewStore(r.Store, actor, r.Clock)
+	return audit.NewStore(revisioned, actor, graphql.GetFieldContext(ctx).Field.Name, r.Clock)
+}
+
+// trashTable returns the table of entity the trash methods of the store
+// accept.
+func trashTable(entity model.RevisionEntity) (interface{}, error) {
+	switch entity {
+	case model.RevisionEntityPolishWord:
+		return dbModels.PolishWord{}, nil
+	case model.RevisionEntityEnglishWord:
+		return dbModels.EnglishWord{}, nil
+	case model.RevisionEntityTranslation:
+		return dbModels.Translation{}, nil
+	case model.RevisionEntityExample:
+		return dbModels.Example{}, nil
+	default:
+		return nil, customErrors.ErrUnknownEntity
+	}
+}
+
+// learner returns whose cards the operation of ctx studies: the signed in
+// user, or the X-Learner header without authentication.
+func (r *Resolver) learner(ctx context.Context) (string, error) {
+	if r.Auth != nil {
+		principal, err := r.principal(ctx)
+		if err != nil {
+			return "", err
 		}
-		translations[i] = r.Converter.TranslationToGraphType(translationDbModel)
+		return principal.Username, nil
+	}
+	name := graphql.GetOperationContext(ctx).Headers.Get(LearnerHeader)
+	if name == "" {
+		return "", customErrors.ErrLearnerRequired
 	}
-	return translations, nil
+	return name, nil
 }
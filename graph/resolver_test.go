@@ -0,0 +1,768 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/auth"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func newTestClient() *client.Client {
+	return client.New(NewServer(memstore.NewStore(), Options{}))
+}
+
+type translationResponse struct {
+	ID         int
+	PolishWord struct {
+		Text string
+	}
+	EnglishWord struct {
+		Text string
+	}
+	Examples []struct {
+		Text     string
+		InPolish bool
+	}
+}
+
+func TestCreateTranslationAndLookUp(t *testing.T) {
+	c := newTestClient()
+
+	var created struct {
+		CreateTranslation translationResponse
+	}
+	err := c.Post(`mutation {
+		createTranslation(translation: {
+			polishWord: "wieża", englishWord: "rook",
+			examples: [{text: "Wieża stoi w rogu.", inPolish: true}]
+		}) { id polishWord { text } englishWord { text } examples { text inPolish } }
+	}`, &created)
+	assert.NoError(t, err)
+	assert.Equal(t, "wieża", created.CreateTranslation.PolishWord.Text)
+	assert.Len(t, created.CreateTranslation.Examples, 1)
+
+	var lookup struct {
+		TranslationToEnglish []translationResponse
+	}
+	err = c.Post(`query($word: String!) {
+		translationToEnglish(wordInPolish: $word) { id englishWord { text } }
+	}`, &lookup, client.Var("word", "wieża"))
+	assert.NoError(t, err)
+	assert.Len(t, lookup.TranslationToEnglish, 1)
+	assert.Equal(t, "rook", lookup.TranslationToEnglish[0].EnglishWord.Text)
+}
+
+func TestUpdatePolishWordTextReturnsStoreError(t *testing.T) {
+	c := newTestClient()
+
+	var words struct {
+		A struct{ ID int }
+		B struct{ ID int }
+	}
+	c.MustPost(`mutation {
+		a: createPolishWord(word: "książka") { id }
+		b: createPolishWord(word: "miecz") { id }
+	}`, &words)
+
+	var updated struct {
+		UpdatePolishWordText struct{ Text string }
+	}
+	err := c.Post(`mutation($id: ID!) { updatePolishWordText(id: $id, text: "książka") { text } }`,
+		&updated, client.Var("id", words.B.ID))
+	assert.ErrorContains(t, err, "polish word with this text already exists")
+}
+
+func TestUpdateWithStaleVersionConflicts(t *testing.T) {
+	c := newTestClient()
+
+	var created struct {
+		CreatePolishWord struct {
+			ID      int
+			Version int
+		}
+	}
+	c.MustPost(`mutation { createPolishWord(word: "kto") { id version } }`, &created)
+	assert.Equal(t, 1, created.CreatePolishWord.Version)
+
+	mutation := `mutation($id: ID!, $text: String!, $version: Int) {
+		updatePolishWordText(id: $id, text: $text, expectedVersion: $version) { text version }
+	}`
+	var updated struct {
+		UpdatePolishWordText struct {
+			Text    string
+			Version int
+		}
+	}
+	c.MustPost(mutation, &updated, client.Var("id", created.CreatePolishWord.ID), client.Var("text", "kot"), client.Var("version", 1))
+	assert.Equal(t, 2, updated.UpdatePolishWordText.Version)
+
+	err := c.Post(mutation, &updated, client.Var("id", created.CreatePolishWord.ID), client.Var("text", "kotek"), client.Var("version", 1))
+	assert.ErrorContains(t, err, `"code":"CONFLICT"`)
+	c.MustPost(mutation, &updated, client.Var("id", created.CreatePolishWord.ID), client.Var("text", "kotek"))
+	assert.Equal(t, "kotek", updated.UpdatePolishWordText.Text)
+	assert.Equal(t, 3, updated.UpdatePolishWordText.Version)
+}
+
+func TestUpdateExampleWithVersionFromSearch(t *testing.T) {
+	c := newTestClient()
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "kot", englishWord: "cat", examples: [{text: "Kot śpi.", inPolish: true}]}) { id } }`, &struct{ CreateTranslation struct{ ID int } }{})
+	query := `query { searchExamples(query: "kot") { edges { node { example { id version } } } } }`
+	var found struct {
+		SearchExamples struct {
+			Edges []struct {
+				Node struct {
+					Example struct {
+						ID      int
+						Version int
+					}
+				}
+			}
+		}
+	}
+	c.MustPost(query, &found)
+	example := found.SearchExamples.Edges[0].Node.Example
+	assert.Equal(t, 1, example.Version)
+
+	var updated struct {
+		UpdateExampleText struct {
+			Version int
+		}
+	}
+	c.MustPost(`mutation($id: ID!, $version: Int) { updateExampleText(id: $id, text: "Kot je.", expectedVersion: $version) { version } }`,
+		&updated, client.Var("id", example.ID), client.Var("version", example.Version))
+	assert.Equal(t, 2, updated.UpdateExampleText.Version)
+}
+
+func TestDeleteTranslationThenGetIt(t *testing.T) {
+	c := newTestClient()
+
+	var created struct {
+		CreateTranslation struct{ ID int }
+	}
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "dziecko", englishWord: "child"}) { id } }`, &created)
+
+	var deleted struct{ DeleteTranslation int }
+	c.MustPost(`mutation($id: ID!) { deleteTranslation(id: $id) }`, &deleted, client.Var("id", created.CreateTranslation.ID))
+	assert.Equal(t, created.CreateTranslation.ID, deleted.DeleteTranslation)
+
+	var fetched struct {
+		GetTranslation struct{ ID int }
+	}
+	err := c.Post(`query($id: ID!) { getTranslation(id: $id) { id } }`, &fetched, client.Var("id", created.CreateTranslation.ID))
+	assert.ErrorContains(t, err, "translation not found")
+}
+
+func TestTranslationsConnectionPages(t *testing.T) {
+	c := newTestClient()
+	c.MustPost(`mutation {
+		a: createTranslation(translation: {polishWord: "kot", englishWord: "cat"}) { id }
+		b: createTranslation(translation: {polishWord: "pies", englishWord: "dog"}) { id }
+		c: createTranslation(translation: {polishWord: "koń", englishWord: "horse"}) { id }
+	}`, &map[string]interface{}{})
+
+	type connection struct {
+		TranslationsConnection struct {
+			Edges []struct {
+				Cursor string
+				Node   translationResponse
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   string
+			}
+			TotalCount int
+		}
+	}
+	query := `query($after: String) {
+		translationsConnection(first: 2, after: $after) {
+			edges { cursor node { id polishWord { text } } }
+			pageInfo { hasNextPage endCursor }
+			totalCount
+		}
+	}`
+
+	var firstPage connection
+	c.MustPost(query, &firstPage)
+	assert.Equal(t, 3, firstPage.TranslationsConnection.TotalCount)
+	assert.Len(t, firstPage.TranslationsConnection.Edges, 2)
+	assert.True(t, firstPage.TranslationsConnection.PageInfo.HasNextPage)
+
+	var secondPage connection
+	c.MustPost(query, &secondPage, client.Var("after", firstPage.TranslationsConnection.PageInfo.EndCursor))
+	assert.Len(t, secondPage.TranslationsConnection.Edges, 1)
+	assert.Equal(t, "koń", secondPage.TranslationsConnection.Edges[0].Node.PolishWord.Text)
+	assert.False(t, secondPage.TranslationsConnection.PageInfo.HasNextPage)
+
+	err := c.Post(`{ polishWordsConnection(after: "bogus") { totalCount } }`, &map[string]interface{}{})
+	assert.ErrorContains(t, err, "invalid pagination cursor")
+}
+
+// countingStore records the batch lookups the Translation field resolvers
+// make. The loaders call it from their own goroutines.
+type countingStore struct {
+	database.DictionaryStore
+	polishWordCalls  atomic.Int32
+	englishWordCalls atomic.Int32
+	exampleCalls     atomic.Int32
+}
+
+func (s *countingStore) GetPolishWordsByIds(ids []uint) ([]*dbModels.PolishWord, error) {
+	s.polishWordCalls.Add(1)
+	return s.DictionaryStore.GetPolishWordsByIds(ids)
+}
+
+func (s *countingStore) GetEnglishWordsByIds(ids []uint) ([]*dbModels.EnglishWord, error) {
+	s.englishWordCalls.Add(1)
+	return s.DictionaryStore.GetEnglishWordsByIds(ids)
+}
+
+func (s *countingStore) GetExamplesByTranslationIds(ids []uint) ([]*dbModels.Example, error) {
+	s.exampleCalls.Add(1)
+	return s.DictionaryStore.GetExamplesByTranslationIds(ids)
+}
+
+func TestTranslationAssociationsAreLoadedOnlyWhenSelected(t *testing.T) {
+	store := &countingStore{DictionaryStore: memstore.NewStore()}
+	for i := 0; i < 20; i++ {
+		store.AddTranslation(model.TranslationInput{
+			PolishWord:  fmt.Sprintf("słowo %v", i),
+			EnglishWord: fmt.Sprintf("word %v", i),
+			Examples:    []*model.ExampleInput{{Text: fmt.Sprintf("przykład %v", i), InPolish: true}},
+		})
+	}
+	// Long enough for every lookup to make the batch, however slow the run.
+	c := client.New(NewServer(store, Options{BatchWait: time.Second}))
+
+	var ids struct {
+		Translations []struct{ ID int }
+	}
+	c.MustPost(`{ translations { id } }`, &ids)
+	assert.Len(t, ids.Translations, 20)
+	assert.Zero(t, store.polishWordCalls.Load()+store.englishWordCalls.Load()+store.exampleCalls.Load())
+
+	var full struct {
+		Translations []translationResponse
+	}
+	c.MustPost(`{ translations { id polishWord { text } englishWord { text } examples { text } } }`, &full)
+	assert.Len(t, full.Translations, 20)
+	assert.Equal(t, "słowo 19", full.Translations[19].PolishWord.Text)
+	assert.Equal(t, "przykład 19", full.Translations[19].Examples[0].Text)
+	assert.Equal(t, int32(1), store.polishWordCalls.Load())
+	assert.Equal(t, int32(1), store.englishWordCalls.Load())
+	assert.Equal(t, int32(1), store.exampleCalls.Load())
+}
+
+func TestSuggestions(t *testing.T) {
+	c := newTestClient()
+	c.MustPost(`mutation { createPolishWord(word: "żółw") { id } }`, &map[string]interface{}{})
+
+	var resp struct {
+		Suggestions []struct {
+			Text     string
+			Language string
+			Distance int
+			Score    float64
+		}
+	}
+	c.MustPost(`{ suggestions(word: "zółw", language: POLISH) { text language distance score } }`, &resp)
+	assert.Len(t, resp.Suggestions, 1)
+	assert.Equal(t, "żółw", resp.Suggestions[0].Text)
+	assert.Equal(t, "POLISH", resp.Suggestions[0].Language)
+	assert.Equal(t, 1.0, resp.Suggestions[0].Score)
+}
+
+func TestSetPolishWordFormsAndLookUpByForm(t *testing.T) {
+	c := newTestClient()
+	var created struct {
+		CreateTranslation struct {
+			PolishWord struct{ ID int }
+		}
+	}
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "pies", englishWord: "dog"}) { polishWord { id } } }`, &created)
+
+	var set struct {
+		SetPolishWordForms []struct {
+			Text   string
+			Case   *string
+			Number *string
+		}
+	}
+	c.MustPost(`mutation($id: ID!) {
+		setPolishWordForms(polishWordID: $id, forms: [{text: "psa", case: GENITIVE, number: SINGULAR}, {text: "psy"}]) { text case number }
+	}`, &set, client.Var("id", created.CreateTranslation.PolishWord.ID))
+	assert.Len(t, set.SetPolishWordForms, 2)
+	assert.Equal(t, "GENITIVE", *set.SetPolishWordForms[0].Case)
+	assert.Nil(t, set.SetPolishWordForms[1].Number)
+
+	var resp struct {
+		TranslationToEnglish []struct {
+			PolishWord struct {
+				Text  string
+				Forms []struct{ Text string }
+			}
+			EnglishWord struct{ Text string }
+		}
+	}
+	c.MustPost(`{ translationToEnglish(wordInPolish: "psy") { polishWord { text forms { text } } englishWord { text } } }`, &resp)
+	assert.Len(t, resp.TranslationToEnglish, 1)
+	assert.Equal(t, "pies", resp.TranslationToEnglish[0].PolishWord.Text)
+	assert.Len(t, resp.TranslationToEnglish[0].PolishWord.Forms, 2)
+	assert.Equal(t, "dog", resp.TranslationToEnglish[0].EnglishWord.Text)
+
+	c.MustPost(`{ translationToEnglish(wordInPolish: "psy", includeForms: false) { id } }`, &resp)
+	assert.Len(t, resp.TranslationToEnglish, 0)
+}
+
+func TestImportTranslationsFromUpload(t *testing.T) {
+	c := newTestClient()
+	file, err := os.CreateTemp(t.TempDir(), "*.tsv")
+	assert.NoError(t, err)
+	file.WriteString("polish\tenglish\npies\tdog\n\tcat\n")
+	file.Seek(0, 0)
+
+	var resp struct {
+		ImportTranslations struct {
+			DryRun  bool
+			Created int
+			Failed  int
+			Rows    []struct {
+				Line   int
+				Status string
+				Reason *string
+			}
+		}
+	}
+	c.MustPost(`mutation($file: Upload!) {
+		importTranslations(file: $file, dryRun: true) { dryRun created failed rows { line status reason } }
+	}`, &resp, client.Var("file", file), client.WithFiles())
+	assert.True(t, resp.ImportTranslations.DryRun)
+	assert.Equal(t, 1, resp.ImportTranslations.Created)
+	assert.Equal(t, 1, resp.ImportTranslations.Failed)
+	assert.Equal(t, 3, resp.ImportTranslations.Rows[1].Line)
+	assert.Equal(t, "FAILED", resp.ImportTranslations.Rows[1].Status)
+
+	var translations struct{ Translations []struct{ ID int } }
+	c.MustPost(`{ translations { id } }`, &translations)
+	assert.Len(t, translations.Translations, 0)
+}
+
+func withLearner(name string) client.Option {
+	return func(request *client.Request) {
+		request.HTTP.Header.Set(LearnerHeader, name)
+	}
+}
+
+func TestDueCardsAndReviewCard(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := client.New(NewServer(memstore.NewStore(), Options{Clock: func() time.Time { return now }}))
+	var created struct {
+		CreateTranslation struct{ ID int }
+	}
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "kot", englishWord: "cat"}) { id } }`, &created)
+
+	type card struct {
+		TranslationID int
+		Translation   struct {
+			PolishWord struct{ Text string }
+		}
+		Direction    string
+		DueAt        string
+		IntervalDays int
+		New          bool
+	}
+	var due struct{ DueCards []card }
+	c.MustPost(`{ dueCards { translationID translation { polishWord { text } } direction dueAt intervalDays new } }`, &due, withLearner("ala"))
+	assert.Len(t, due.DueCards, 2)
+	assert.Equal(t, "kot", due.DueCards[0].Translation.PolishWord.Text)
+	assert.Equal(t, "POLISH_TO_ENGLISH", due.DueCards[0].Direction)
+	assert.True(t, due.DueCards[0].New)
+	assert.Equal(t, "2025-03-01T12:00:00Z", due.DueCards[0].DueAt)
+
+	var reviewed struct{ ReviewCard card }
+	c.MustPost(`mutation($id: ID!) { reviewCard(translationID: $id, direction: POLISH_TO_ENGLISH, grade: 5) { dueAt intervalDays new } }`,
+		&reviewed, client.Var("id", created.CreateTranslation.ID), withLearner("ala"))
+	assert.Equal(t, 1, reviewed.ReviewCard.IntervalDays)
+	assert.False(t, reviewed.ReviewCard.New)
+	assert.Equal(t, "2025-03-02T12:00:00Z", reviewed.ReviewCard.DueAt)
+
+	c.MustPost(`{ dueCards(direction: POLISH_TO_ENGLISH) { direction } }`, &due, withLearner("ala"))
+	assert.Empty(t, due.DueCards)
+	c.MustPost(`{ dueCards(direction: POLISH_TO_ENGLISH) { direction } }`, &due, withLearner("ola"))
+	assert.Len(t, due.DueCards, 1)
+
+	err := c.Post(`{ dueCards { direction } }`, &due)
+	assert.ErrorContains(t, err, customErrors.ErrLearnerRequired.Error())
+	err = c.Post(`mutation { reviewCard(translationID: 1, direction: ENGLISH_TO_POLISH, grade: 7) { new } }`, &reviewed, withLearner("ala"))
+	assert.ErrorContains(t, err, customErrors.ErrInvalidGrade.Error())
+}
+
+func TestGenerateQuizAndSubmitAnswers(t *testing.T) {
+	c := newTestClient()
+	for _, words := range [][2]string{{"kot", "cat"}, {"pies", "dog"}, {"koń", "horse"}} {
+		c.MustPost(`mutation($pl: String!, $en: String!) { createTranslation(translation: {polishWord: $pl, englishWord: $en}) { id } }`,
+			&map[string]interface{}{}, client.Var("pl", words[0]), client.Var("en", words[1]))
+	}
+	var tagged struct{ SetTranslationTags []string }
+	c.MustPost(`mutation { setTranslationTags(translationID: 2, tags: ["Lekcja 1", "animals"]) }`, &tagged)
+	assert.Equal(t, []string{"animals", "lekcja 1"}, tagged.SetTranslationTags)
+
+	var generated struct {
+		GenerateQuiz struct {
+			Seed      int
+			Questions []struct {
+				TranslationID int
+				Prompt        string
+				Choices       []string
+				Answer        string
+			}
+		}
+	}
+	c.MustPost(`{ generateQuiz(tag: "lekcja 1", choices: 3, seed: 42) { seed questions { translationID prompt choices answer } } }`, &generated)
+	assert.Equal(t, 42, generated.GenerateQuiz.Seed)
+	assert.Len(t, generated.GenerateQuiz.Questions, 1)
+	question := generated.GenerateQuiz.Questions[0]
+	assert.Equal(t, "pies", question.Prompt)
+	assert.ElementsMatch(t, []string{"cat", "dog", "horse"}, question.Choices)
+
+	var submitted struct {
+		SubmitQuizAnswers struct {
+			Correct int
+			Total   int
+			Score   float64
+			Answers []struct {
+				Correct       bool
+				CorrectAnswer string
+			}
+		}
+	}
+	c.MustPost(`mutation($id: ID!) {
+		submitQuizAnswers(answers: [{translationID: $id, direction: POLISH_TO_ENGLISH, answer: "Dog"}, {translationID: 1, direction: ENGLISH_TO_POLISH, answer: "pies"}]) {
+			correct total score answers { correct correctAnswer }
+		}
+	}`, &submitted, client.Var("id", question.TranslationID))
+	assert.Equal(t, 1, submitted.SubmitQuizAnswers.Correct)
+	assert.Equal(t, 2, submitted.SubmitQuizAnswers.Total)
+	assert.Equal(t, 0.5, submitted.SubmitQuizAnswers.Score)
+	assert.Equal(t, "kot", submitted.SubmitQuizAnswers.Answers[1].CorrectAnswer)
+
+	var translations struct {
+		Translations []struct{ Tags []string }
+	}
+	c.MustPost(`{ translations { tags } }`, &translations)
+	assert.Empty(t, translations.Translations[0].Tags)
+	assert.Len(t, translations.Translations[1].Tags, 2)
+}
+
+func newAuthTestClient(allowAnonymousReads bool) (*client.Client, *auth.Service) {
+	store := memstore.NewStore()
+	service := auth.NewService(store, []byte("secret"))
+	service.CreateUser("ala", "kot ma ale", auth.RoleAdmin)
+	return client.New(NewServer(store, Options{Auth: service, AllowAnonymousReads: allowAnonymousReads})), service
+}
+
+func withToken(token string) client.Option {
+	return func(request *client.Request) {
+		request.HTTP.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	c, _ := newAuthTestClient(false)
+
+	var created map[string]interface{}
+	err := c.Post(`mutation { createPolishWord(word: "kot") { id } }`, &created)
+	assert.ErrorContains(t, err, customErrors.ErrUnauthenticated.Error())
+	err = c.Post(`{ polishWords { text } }`, &created)
+	assert.ErrorContains(t, err, customErrors.ErrUnauthenticated.Error())
+	err = c.Post(`mutation { login(username: "ala", password: "kot ma psa") { token } }`, &created)
+	assert.ErrorContains(t, err, customErrors.ErrInvalidCredentials.Error())
+
+	var login struct {
+		Login struct {
+			Token string
+			User  struct{ Username string }
+		}
+	}
+	c.MustPost(`mutation { login(username: "ala", password: "kot ma ale") { token user { username } } }`, &login)
+	assert.Equal(t, "ala", login.Login.User.Username)
+	token := withToken(login.Login.Token)
+
+	c.MustPost(`mutation { createPolishWord(word: "kot") { id } }`, &created, token)
+	var me struct{ Me struct{ Username string } }
+	c.MustPost(`{ me { username } }`, &me, token)
+	assert.Equal(t, "ala", me.Me.Username)
+
+	var key struct {
+		CreateAPIKey struct {
+			Key    string
+			APIKey struct {
+				ID     int
+				Prefix string
+			}
+		}
+	}
+	c.MustPost(`mutation { createAPIKey(name: "script") { key apiKey { id prefix } } }`, &key, token)
+	viaKey := func(request *client.Request) { request.HTTP.Header.Set(auth.APIKeyHeader, key.CreateAPIKey.Key) }
+	var keys struct{ APIKeys []struct{ Prefix string } }
+	c.MustPost(`{ apiKeys { prefix } }`, &keys, viaKey)
+	assert.Equal(t, key.CreateAPIKey.APIKey.Prefix, keys.APIKeys[0].Prefix)
+
+	// The learner studying is the signed in user.
+	var due struct{ DueCards []struct{ Direction string } }
+	c.MustPost(`{ dueCards { direction } }`, &due, viaKey)
+	assert.Len(t, due.DueCards, 0)
+}
+
+func TestAnonymousReads(t *testing.T) {
+	c, _ := newAuthTestClient(true)
+
+	var resp map[string]interface{}
+	c.MustPost(`{ polishWords { text } me { username } }`, &resp)
+	assert.Nil(t, resp["me"])
+	err := c.Post(`mutation { createPolishWord(word: "kot") { id } }`, &resp)
+	assert.ErrorContains(t, err, customErrors.ErrUnauthenticated.Error())
+}
+
+// withRole signs in as a new user with role.
+func withRole(t *testing.T, service *auth.Service, role auth.Role) client.Option {
+	user, err := service.CreateUser(strings.ToLower(string(role)), "kot ma ale", role)
+	assert.NoError(t, err)
+	token, _, _ := service.IssueToken(user)
+	return withToken(token)
+}
+
+func TestRoles(t *testing.T) {
+	c, service := newAuthTestClient(false)
+	reader := withRole(t, service, auth.RoleReader)
+	editor := withRole(t, service, auth.RoleEditor)
+	admin := withRole(t, service, auth.RoleAdmin)
+
+	var created struct{ CreatePolishWord struct{ ID int } }
+	err := c.Post(`mutation { createPolishWord(word: "kot") { id } }`, &created, reader)
+	assert.ErrorContains(t, err, `"code":"FORBIDDEN"`)
+	assert.ErrorContains(t, err, `"requiredRole":"EDITOR"`)
+	c.MustPost(`mutation { createPolishWord(word: "kot") { id } }`, &created, editor)
+
+	var resp map[string]interface{}
+	c.MustPost(`{ polishWords { text } }`, &resp, reader)
+	deletion := fmt.Sprintf(`mutation { deletePolishWord(id: %v) }`, created.CreatePolishWord.ID)
+	err = c.Post(deletion, &resp, editor)
+	assert.ErrorContains(t, err, customErrors.ErrForbidden.Error())
+	c.MustPost(deletion, &resp, admin)
+
+	err = c.Post(`mutation { setUserRole(username: "reader", role: EDITOR) { role } }`, &resp, editor)
+	assert.ErrorContains(t, err, `"code":"FORBIDDEN"`)
+	var promoted struct{ SetUserRole struct{ Role model.Role } }
+	c.MustPost(`mutation { setUserRole(username: "reader", role: EDITOR) { role } }`, &promoted, admin)
+	assert.Equal(t, model.RoleEditor, promoted.SetUserRole.Role)
+	// The new role applies to the token issued before it.
+	c.MustPost(`mutation { createPolishWord(word: "pies") { id } }`, &resp, reader)
+
+	err = c.Post(`{ polishWords { text } }`, &resp)
+	assert.ErrorContains(t, err, `"code":"UNAUTHENTICATED"`)
+}
+
+func TestEveryOperationRequiresARole(t *testing.T) {
+	schema := NewExecutableSchema(Config{}).Schema()
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation} {
+		for _, field := range root.Fields {
+			if field.Name == "login" || strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			assert.NotNil(t, field.Directives.ForName("hasRole"), "%v.%v", root.Name, field.Name)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	c, service := newAuthTestClient(false)
+	editor := withRole(t, service, auth.RoleEditor)
+	admin := withRole(t, service, auth.RoleAdmin)
+
+	var created struct{ CreatePolishWord struct{ ID int } }
+	c.MustPost(`mutation { createPolishWord(word: "kto") { id } }`, &created, editor)
+	id := created.CreatePolishWord.ID
+	c.MustPost(fmt.Sprintf(`mutation { updatePolishWordText(id: %v, text: "kot") { id } }`, id), &map[string]interface{}{}, editor)
+	err := c.Post(fmt.Sprintf(`mutation { updatePolishWordText(id: %v, text: "") { id } }`, id+1), &map[string]interface{}{}, editor)
+	assert.Error(t, err)
+
+	query := `query($id: ID) { auditLog(entityType: POLISH_WORD, entityID: $id, first: 10) { totalCount edges { node { actor operation entityID before after } } } }`
+	err = c.Post(query, &map[string]interface{}{}, editor, client.Var("id", id))
+	assert.ErrorContains(t, err, `"code":"FORBIDDEN"`)
+
+	var log struct {
+		AuditLog struct {
+			TotalCount int
+			Edges      []struct {
+				Node struct {
+					Actor     string
+					Operation string
+					EntityID  int
+					Before    *string
+					After     *string
+				}
+			}
+		}
+	}
+	c.MustPost(query, &log, admin, client.Var("id", id))
+	assert.Equal(t, 2, log.AuditLog.TotalCount)
+	creation, updated := log.AuditLog.Edges[0].Node, log.AuditLog.Edges[1].Node
+	assert.Equal(t, "createPolishWord", creation.Operation)
+	assert.Nil(t, creation.Before)
+	assert.Equal(t, "editor", updated.Actor)
+	assert.Equal(t, "updatePolishWordText", updated.Operation)
+	assert.Contains(t, *updated.Before, `"text":"kto"`)
+	assert.Contains(t, *updated.After, `"text":"kot"`)
+
+	c.MustPost(`{ auditLog(since: "2999-01-01T00:00:00Z") { totalCount } }`, &log, admin)
+	assert.Zero(t, log.AuditLog.TotalCount)
+}
+
+func TestRevisionHistoryAndRestore(t *testing.T) {
+	c, service := newAuthTestClient(false)
+	editor := withRole(t, service, auth.RoleEditor)
+	admin := withRole(t, service, auth.RoleAdmin)
+
+	var created struct{ CreateTranslation struct{ ID int } }
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "kto", englishWord: "cat", examples: [{text: "Ala ma kota", inPolish: true}]}) { id } }`, &created, editor)
+	var translation struct {
+		GetTranslation struct {
+			PolishWord struct{ ID int }
+			Examples   []struct{ ID int }
+		}
+	}
+	c.MustPost(fmt.Sprintf(`{ getTranslation(id: %v) { polishWord { id } examples { id } } }`, created.CreateTranslation.ID), &translation, editor)
+	wordID := translation.GetTranslation.PolishWord.ID
+	c.MustPost(fmt.Sprintf(`mutation { updatePolishWordText(id: %v, text: "kot") { id } }`, wordID), &map[string]interface{}{}, editor)
+
+	var word struct {
+		GetPolishWord struct {
+			History []struct {
+				Version int
+				Text    string
+				Deleted bool
+				Actor   string
+			}
+		}
+	}
+	c.MustPost(fmt.Sprintf(`{ getPolishWord(id: %v) { history { version text deleted actor } } }`, wordID), &word, editor)
+	assert.Len(t, word.GetPolishWord.History, 2)
+	assert.Equal(t, "kto", word.GetPolishWord.History[0].Text)
+	assert.Equal(t, 2, word.GetPolishWord.History[1].Version)
+	assert.Equal(t, "editor", word.GetPolishWord.History[1].Actor)
+
+	c.MustPost(fmt.Sprintf(`mutation { deletePolishWord(id: %v) }`, wordID), &map[string]interface{}{}, admin)
+	var history struct {
+		History []struct {
+			Version  int
+			Deleted  bool
+			Text     string
+			InPolish bool
+		}
+	}
+	query := `query($id: ID!) { history(entity: EXAMPLE, id: $id) { ... on ExampleRevision { version deleted text inPolish } } }`
+	exampleID := translation.GetTranslation.Examples[0].ID
+	c.MustPost(query, &history, editor, client.Var("id", exampleID))
+	assert.Len(t, history.History, 2)
+	assert.True(t, history.History[1].Deleted)
+	assert.Equal(t, "Ala ma kota", history.History[1].Text)
+
+	var restored struct {
+		RestoreRevision struct {
+			Typename string `json:"__typename"`
+			ID       int
+			Text     string
+		}
+	}
+	mutation := fmt.Sprintf(`mutation { restoreRevision(entity: POLISH_WORD, id: %v, version: 1) { __typename ... on PolishWord { id text } } }`, wordID)
+	err := c.Post(mutation, &restored, editor)
+	assert.NoError(t, err)
+	assert.Equal(t, "PolishWord", restored.RestoreRevision.Typename)
+	assert.Equal(t, wordID, restored.RestoreRevision.ID)
+	assert.Equal(t, "kto", restored.RestoreRevision.Text)
+
+	var restoredTranslation struct {
+		GetTranslation struct {
+			PolishWord struct{ Text string }
+			Examples   []struct{ ID int }
+			History    []struct{ Version int }
+		}
+	}
+	c.MustPost(fmt.Sprintf(`{ getTranslation(id: %v) { polishWord { text } examples { id } history { version } } }`, created.CreateTranslation.ID), &restoredTranslation, editor)
+	assert.Equal(t, "kto", restoredTranslation.GetTranslation.PolishWord.Text)
+	assert.Equal(t, []struct{ ID int }{{exampleID}}, restoredTranslation.GetTranslation.Examples)
+	assert.Len(t, restoredTranslation.GetTranslation.History, 3)
+
+	err = c.Post(fmt.Sprintf(`mutation { restoreRevision(entity: POLISH_WORD, id: %v, version: 9) { __typename } }`, wordID), &restored, editor)
+	assert.ErrorContains(t, err, customErrors.ErrRevisionNotFound.Error())
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	c, service := newAuthTestClient(false)
+	editor := withRole(t, service, auth.RoleEditor)
+	admin := withRole(t, service, auth.RoleAdmin)
+
+	var created struct {
+		CreateTranslation struct {
+			ID         int
+			PolishWord struct{ ID int }
+		}
+	}
+	c.MustPost(`mutation { createTranslation(translation: {polishWord: "kot", englishWord: "cat", examples: [{text: "Ala ma kota", inPolish: true}]}) { id polishWord { id } } }`, &created, editor)
+	wordID := created.CreateTranslation.PolishWord.ID
+	c.MustPost(fmt.Sprintf(`mutation { deletePolishWord(id: %v) }`, wordID), &map[string]interface{}{}, admin)
+
+	var trash struct {
+		Trash []struct {
+			Entity string
+			ID     int
+			Text   string
+		}
+	}
+	c.MustPost(`{ trash { entity id text } }`, &trash, editor)
+	assert.Len(t, trash.Trash, 3)
+	c.MustPost(`{ trash(entity: TRANSLATION) { entity id text } }`, &trash, editor)
+	assert.Len(t, trash.Trash, 1)
+	assert.Equal(t, created.CreateTranslation.ID, trash.Trash[0].ID)
+	assert.Equal(t, "kot - cat", trash.Trash[0].Text)
+
+	var restored struct {
+		Restore struct {
+			Typename string `json:"__typename"`
+			Text     string
+		}
+	}
+	c.MustPost(fmt.Sprintf(`mutation { restore(entity: POLISH_WORD, id: %v) { __typename ... on PolishWord { text } } }`, wordID), &restored, editor)
+	assert.Equal(t, "PolishWord", restored.Restore.Typename)
+	assert.Equal(t, "kot", restored.Restore.Text)
+	var translation struct {
+		GetTranslation struct{ Examples []struct{ Text string } }
+	}
+	c.MustPost(fmt.Sprintf(`{ getTranslation(id: %v) { examples { text } } }`, created.CreateTranslation.ID), &translation, editor)
+	assert.Len(t, translation.GetTranslation.Examples, 1)
+	err := c.Post(fmt.Sprintf(`mutation { restore(entity: POLISH_WORD, id: %v) { __typename } }`, wordID), &restored, editor)
+	assert.ErrorContains(t, err, customErrors.ErrNotInTrash.Error())
+
+	c.MustPost(fmt.Sprintf(`mutation { deleteTranslation(id: %v) }`, created.CreateTranslation.ID), &map[string]interface{}{}, admin)
+	var purged struct{ PurgeTrash int }
+	err = c.Post(`mutation { purgeTrash }`, &purged, editor)
+	assert.ErrorContains(t, err, `"code":"FORBIDDEN"`)
+	c.MustPost(`mutation { purgeTrash(olderThan: "2000-01-01T00:00:00Z") }`, &purged, admin)
+	assert.Equal(t, 0, purged.PurgeTrash)
+	c.MustPost(`mutation { purgeTrash }`, &purged, admin)
+	assert.Equal(t, 2, purged.PurgeTrash)
+	c.MustPost(`{ trash { entity id text } }`, &trash, editor)
+	assert.Empty(t, trash.Trash)
+}
@@ -6,14 +6,102 @@ package graph
 
 import (
 	"context"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/realagmag/dictionaryGO/graph/loaders"
 	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/auth"
+	"github.com/realagmag/dictionaryGO/internal/converter"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/history"
+	"github.com/realagmag/dictionaryGO/internal/importer"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/quiz"
 )
 
+// History is the resolver for the history field.
+func (r *englishWordResolver) History(ctx context.Context, obj *model.EnglishWord) ([]*model.EnglishWordRevision, error) {
+	revisions, err := r.Store.GetEnglishWordRevisions(uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return converter.RevisionSliceToGraphType(revisions, r.Converter.EnglishWordRevisionToGraphType), nil
+}
+
+// History is the resolver for the history field.
+func (r *exampleResolver) History(ctx context.Context, obj *model.Example) ([]*model.ExampleRevision, error) {
+	revisions, err := r.Store.GetExampleRevisions(uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return converter.RevisionSliceToGraphType(revisions, r.Converter.ExampleRevisionToGraphType), nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, username string, password string) (*model.AuthPayload, error) {
+	if r.Auth == nil {
+		return nil, customErrors.ErrAuthenticationDisabled
+	}
+	token, expiresAt, user, err := r.Auth.Login(username, password)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuthPayload{Token: token, ExpiresAt: expiresAt, User: r.Converter.UserToGraphType(user)}, nil
+}
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, username string, password string, role model.Role) (*model.User, error) {
+	if r.Auth == nil {
+		return nil, customErrors.ErrAuthenticationDisabled
+	}
+	user, err := r.Auth.WithStore(r.audited(ctx)).CreateUser(username, password, auth.Role(role))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.UserToGraphType(user), nil
+}
+
+// SetUserRole is the resolver for the setUserRole field.
+func (r *mutationResolver) SetUserRole(ctx context.Context, username string, role model.Role) (*model.User, error) {
+	if r.Auth == nil {
+		return nil, customErrors.ErrAuthenticationDisabled
+	}
+	user, err := r.Auth.WithStore(r.audited(ctx)).SetRole(username, auth.Role(role))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.UserToGraphType(user), nil
+}
+
+// CreateAPIKey is the resolver for the createAPIKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, name string) (*model.NewAPIKey, error) {
+	principal, err := r.principal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, apiKey, err := r.Auth.WithStore(r.audited(ctx)).CreateAPIKey(principal.UserID, name)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NewAPIKey{Key: key, APIKey: r.Converter.APIKeyToGraphType(apiKey)}, nil
+}
+
+// DeleteAPIKey is the resolver for the deleteAPIKey field.
+func (r *mutationResolver) DeleteAPIKey(ctx context.Context, id int) (int, error) {
+	principal, err := r.principal(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.audited(ctx).DeleteAPIKey(principal.UserID, uint(id)); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 // CreatePolishWord is the resolver for the createPolishWord field.
-func (r *mutationResolver) CreatePolishWord(ctx context.Context, word string) (*model.PolishWord, error) {
-	polishWord, err := r.DBManager.AddPolishWord(word)
+func (r *mutationResolver) CreatePolishWord(ctx context.Context, word string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect) (*model.PolishWord, error) {
+	polishWord, err := r.audited(ctx).AddPolishWord(r.Converter.PolishWordFromArgs(word, partOfSpeech, gender, aspect))
 	if err != nil {
 		return nil, err
 	}
@@ -21,8 +109,8 @@ func (r *mutationResolver) CreatePolishWord(ctx context.Context, word string) (*
 }
 
 // CreateEnglishWord is the resolver for the createEnglishWord field.
-func (r *mutationResolver) CreateEnglishWord(ctx context.Context, word string) (*model.EnglishWord, error) {
-	englishWord, err := r.DBManager.AddEnglishWord(word)
+func (r *mutationResolver) CreateEnglishWord(ctx context.Context, word string, partOfSpeech *model.PartOfSpeech) (*model.EnglishWord, error) {
+	englishWord, err := r.audited(ctx).AddEnglishWord(r.Converter.EnglishWordFromArgs(word, partOfSpeech))
 	if err != nil {
 		return nil, err
 	}
@@ -31,19 +119,16 @@ func (r *mutationResolver) CreateEnglishWord(ctx context.Context, word string) (
 
 // CreateTranslation is the resolver for the createTranslation field.
 func (r *mutationResolver) CreateTranslation(ctx context.Context, translation model.TranslationInput) (*model.Translation, error) {
-	translationModel, err := r.DBManager.AddTranslation(translation)
+	translationModel, err := r.audited(ctx).AddTranslation(translation)
 	if err != nil {
 		return nil, err
 	}
-	if err = r.DBManager.PopulateTranslationWithAssociations(translationModel); err != nil {
-		return nil, err
-	}
 	return r.Converter.TranslationToGraphType(translationModel), nil
 }
 
 // CreateExample is the resolver for the createExample field.
 func (r *mutationResolver) CreateExample(ctx context.Context, example model.IndividualExampleInput) (*model.Example, error) {
-	exampleModel, err := r.DBManager.AddExampleToTranslation(example.Example, uint(example.TranslationID))
+	exampleModel, err := r.audited(ctx).AddExampleToTranslation(example.Example, uint(example.TranslationID))
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +137,7 @@ func (r *mutationResolver) CreateExample(ctx context.Context, example model.Indi
 
 // DeletePolishWord is the resolver for the deletePolishWord field.
 func (r *mutationResolver) DeletePolishWord(ctx context.Context, id int) (int, error) {
-	if err := r.DBManager.DeleteRecordFromTable(dbModels.PolishWord{}, uint(id)); err != nil {
+	if err := r.audited(ctx).DeleteRecordFromTable(dbModels.PolishWord{}, uint(id)); err != nil {
 		return 0, err
 	}
 	return id, nil
@@ -60,7 +145,7 @@ func (r *mutationResolver) DeletePolishWord(ctx context.Context, id int) (int, e
 
 // DeleteEnglishWord is the resolver for the deleteEnglishWord field.
 func (r *mutationResolver) DeleteEnglishWord(ctx context.Context, id int) (int, error) {
-	if err := r.DBManager.DeleteRecordFromTable(dbModels.EnglishWord{}, uint(id)); err != nil {
+	if err := r.audited(ctx).DeleteRecordFromTable(dbModels.EnglishWord{}, uint(id)); err != nil {
 		return 0, err
 	}
 	return id, nil
@@ -68,7 +153,7 @@ func (r *mutationResolver) DeleteEnglishWord(ctx context.Context, id int) (int,
 
 // DeleteTranslation is the resolver for the deleteTranslation field.
 func (r *mutationResolver) DeleteTranslation(ctx context.Context, id int) (int, error) {
-	if err := r.DBManager.DeleteRecordFromTable(dbModels.Translation{}, uint(id)); err != nil {
+	if err := r.audited(ctx).DeleteRecordFromTable(dbModels.Translation{}, uint(id)); err != nil {
 		return 0, err
 	}
 	return id, nil
@@ -76,15 +161,37 @@ func (r *mutationResolver) DeleteTranslation(ctx context.Context, id int) (int,
 
 // DeleteExample is the resolver for the deleteExample field.
 func (r *mutationResolver) DeleteExample(ctx context.Context, id int) (int, error) {
-	if err := r.DBManager.DeleteRecordFromTable(dbModels.Example{}, uint(id)); err != nil {
+	if err := r.audited(ctx).DeleteRecordFromTable(dbModels.Example{}, uint(id)); err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
+// Restore is the resolver for the restore field.
+func (r *mutationResolver) Restore(ctx context.Context, entity model.RevisionEntity, id int) (model.RevisionedEntity, error) {
+	table, err := trashTable(entity)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := r.audited(ctx).RestoreFromTrash(table, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.RestoredToGraphType(restored)
+}
+
+// PurgeTrash is the resolver for the purgeTrash field.
+func (r *mutationResolver) PurgeTrash(ctx context.Context, olderThan *time.Time) (int32, error) {
+	purged, err := r.audited(ctx).PurgeTrash(olderThan)
+	if err != nil {
+		return 0, err
+	}
+	return int32(purged), nil
+}
+
 // UpdateExampleText is the resolver for the updateExampleText field.
-func (r *mutationResolver) UpdateExampleText(ctx context.Context, id int, text string) (*model.Example, error) {
-	exampleModel, err := r.DBManager.ChangeExampleText(uint(id), text)
+func (r *mutationResolver) UpdateExampleText(ctx context.Context, id int, text string, expectedVersion *int32) (*model.Example, error) {
+	exampleModel, err := r.audited(ctx).ChangeExampleText(uint(id), text, r.Converter.VersionFromArgs(expectedVersion))
 	if err != nil {
 		return nil, err
 	}
@@ -92,8 +199,8 @@ func (r *mutationResolver) UpdateExampleText(ctx context.Context, id int, text s
 }
 
 // UpdatePolishWordText is the resolver for the updatePolishWordText field.
-func (r *mutationResolver) UpdatePolishWordText(ctx context.Context, id int, text string) (*model.PolishWord, error) {
-	polishWordModel, err := r.DBManager.ChangePolishWordText(uint(id), text)
+func (r *mutationResolver) UpdatePolishWordText(ctx context.Context, id int, text string, expectedVersion *int32) (*model.PolishWord, error) {
+	polishWordModel, err := r.audited(ctx).ChangePolishWordText(uint(id), text, r.Converter.VersionFromArgs(expectedVersion))
 	if err != nil {
 		return nil, err
 	}
@@ -101,17 +208,97 @@ func (r *mutationResolver) UpdatePolishWordText(ctx context.Context, id int, tex
 }
 
 // UpdateEnglishWordText is the resolver for the updateEnglishWordText field.
-func (r *mutationResolver) UpdateEnglishWordText(ctx context.Context, id int, text string) (*model.EnglishWord, error) {
-	englishWordModel, err := r.DBManager.ChangeEnglishWordText(uint(id), text)
+func (r *mutationResolver) UpdateEnglishWordText(ctx context.Context, id int, text string, expectedVersion *int32) (*model.EnglishWord, error) {
+	englishWordModel, err := r.audited(ctx).ChangeEnglishWordText(uint(id), text, r.Converter.VersionFromArgs(expectedVersion))
 	if err != nil {
 		return nil, err
 	}
 	return r.Converter.EnglishToGraphType(englishWordModel), nil
 }
 
+// RestoreRevision is the resolver for the restoreRevision field.
+func (r *mutationResolver) RestoreRevision(ctx context.Context, entity model.RevisionEntity, id int, version int32) (model.RevisionedEntity, error) {
+	restored, err := history.Restore(r.audited(ctx), string(entity), uint(id), uint(version))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.RestoredToGraphType(restored)
+}
+
+// SetPolishWordForms is the resolver for the setPolishWordForms field.
+func (r *mutationResolver) SetPolishWordForms(ctx context.Context, polishWordID int, forms []*model.WordFormInput, replace bool) ([]*model.WordForm, error) {
+	storedForms, err := r.audited(ctx).SetPolishWordForms(uint(polishWordID), r.Converter.WordFormsFromInput(forms), replace)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.WordFormSliceToGraphType(storedForms), nil
+}
+
+// SetTranslationTags is the resolver for the setTranslationTags field.
+func (r *mutationResolver) SetTranslationTags(ctx context.Context, translationID int, tags []string) ([]string, error) {
+	translationTags, err := r.audited(ctx).SetTranslationTags(uint(translationID), tags)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.TagNames(translationTags), nil
+}
+
+// ImportTranslations is the resolver for the importTranslations field.
+func (r *mutationResolver) ImportTranslations(ctx context.Context, file graphql.Upload, format *model.ImportFormat, dryRun bool, batchSize *int32) (*model.ImportReport, error) {
+	rows, err := importer.ReadRows(file.File, r.Converter.ImportFormatFromArgs(format, file.Filename))
+	if err != nil {
+		return nil, err
+	}
+	report, err := importer.Import(r.audited(ctx), rows, r.Converter.ImportOptionsFromArgs(dryRun, batchSize))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.ImportReportToGraphType(report), nil
+}
+
+// ReviewCard is the resolver for the reviewCard field.
+func (r *mutationResolver) ReviewCard(ctx context.Context, translationID int, direction model.StudyDirection, grade int32) (*model.Card, error) {
+	name, err := r.learner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	card, err := r.audited(ctx).ReviewCard(name, uint(translationID), string(direction), int(grade), r.Clock())
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.CardToGraphType(card), nil
+}
+
+// SubmitQuizAnswers is the resolver for the submitQuizAnswers field.
+func (r *mutationResolver) SubmitQuizAnswers(ctx context.Context, answers []*model.QuizAnswerInput) (*model.QuizResult, error) {
+	result, err := quiz.Grade(r.Store, r.Converter.QuizAnswersFromInput(answers))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.QuizResultToGraphType(result), nil
+}
+
+// Forms is the resolver for the forms field.
+func (r *polishWordResolver) Forms(ctx context.Context, obj *model.PolishWord) ([]*model.WordForm, error) {
+	forms, err := loaders.For(ctx).WordForms.Load(ctx, uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.WordFormSliceToGraphType(forms), nil
+}
+
+// History is the resolver for the history field.
+func (r *polishWordResolver) History(ctx context.Context, obj *model.PolishWord) ([]*model.PolishWordRevision, error) {
+	revisions, err := r.Store.GetPolishWordRevisions(uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return converter.RevisionSliceToGraphType(revisions, r.Converter.PolishWordRevisionToGraphType), nil
+}
+
 // PolishWords is the resolver for the polishWords field.
 func (r *queryResolver) PolishWords(ctx context.Context) ([]*model.PolishWord, error) {
-	words, err := r.DBManager.GetPolishWords()
+	words, err := r.Store.GetPolishWords()
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +307,7 @@ func (r *queryResolver) PolishWords(ctx context.Context) ([]*model.PolishWord, e
 
 // EnglishWords is the resolver for the englishWords field.
 func (r *queryResolver) EnglishWords(ctx context.Context) ([]*model.EnglishWord, error) {
-	words, err := r.DBManager.GetEnglishWords()
+	words, err := r.Store.GetEnglishWords()
 	if err != nil {
 		return nil, err
 	}
@@ -129,42 +316,217 @@ func (r *queryResolver) EnglishWords(ctx context.Context) ([]*model.EnglishWord,
 
 // Translations is the resolver for the translations field.
 func (r *queryResolver) Translations(ctx context.Context) ([]*model.Translation, error) {
-	translationDbModels, err := r.DBManager.GetTranslations()
+	translationDbModels, err := r.Store.GetTranslations()
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.TranslationSliceToGraphType(translationDbModels), nil
+}
+
+// PolishWordsConnection is the resolver for the polishWordsConnection field.
+func (r *queryResolver) PolishWordsConnection(ctx context.Context, first *int32, after *string, last *int32, before *string) (*model.PolishWordConnection, error) {
+	page, err := r.Converter.PageRequestFromArgs(first, after, last, before)
 	if err != nil {
 		return nil, err
 	}
-	translations, err := r.PrepareTranslationSliceToSend(&translationDbModels)
+	words, err := r.Store.GetPolishWordsPage(page)
 	if err != nil {
 		return nil, err
 	}
-	return translations, nil
+	return r.Converter.PolishWordPageToConnection(words), nil
 }
 
-// TranslationToEnglish is the resolver for the translationToEnglish field.
-func (r *queryResolver) TranslationToEnglish(ctx context.Context, wordInPolish string) ([]*model.Translation, error) {
-	translationsToEnglish, err := r.DBManager.GetTranslationsToEnglish(wordInPolish)
+// EnglishWordsConnection is the resolver for the englishWordsConnection field.
+func (r *queryResolver) EnglishWordsConnection(ctx context.Context, first *int32, after *string, last *int32, before *string) (*model.EnglishWordConnection, error) {
+	page, err := r.Converter.PageRequestFromArgs(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	words, err := r.Store.GetEnglishWordsPage(page)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.EnglishWordPageToConnection(words), nil
+}
+
+// TranslationsConnection is the resolver for the translationsConnection field.
+func (r *queryResolver) TranslationsConnection(ctx context.Context, first *int32, after *string, last *int32, before *string) (*model.TranslationConnection, error) {
+	page, err := r.Converter.PageRequestFromArgs(first, after, last, before)
 	if err != nil {
 		return nil, err
 	}
-	translations, err := r.PrepareTranslationSliceToSend(&translationsToEnglish)
+	translations, err := r.Store.GetTranslationsPage(page)
 	if err != nil {
 		return nil, err
 	}
-	return translations, nil
+	return r.Converter.TranslationPageToConnection(translations), nil
+}
+
+// TranslationToEnglish is the resolver for the translationToEnglish field.
+func (r *queryResolver) TranslationToEnglish(ctx context.Context, wordInPolish string, caseSensitive bool, foldDiacritics bool, includeForms bool, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect) ([]*model.Translation, error) {
+	translationsToEnglish, err := r.Store.GetTranslationsToEnglish(wordInPolish, r.Converter.LookupOptionsFromArgs(caseSensitive, foldDiacritics, includeForms, partOfSpeech, gender, aspect))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.TranslationSliceToGraphType(translationsToEnglish), nil
 }
 
 // TranslationToPolish is the resolver for the translationToPolish field.
-func (r *queryResolver) TranslationToPolish(ctx context.Context, wordInEnglish string) ([]*model.Translation, error) {
-	translationsToPolish, err := r.DBManager.GetTranslationsToPolish(wordInEnglish)
+func (r *queryResolver) TranslationToPolish(ctx context.Context, wordInEnglish string, caseSensitive bool, foldDiacritics bool, partOfSpeech *model.PartOfSpeech) ([]*model.Translation, error) {
+	translationsToPolish, err := r.Store.GetTranslationsToPolish(wordInEnglish, r.Converter.LookupOptionsFromArgs(caseSensitive, foldDiacritics, false, partOfSpeech, nil, nil))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.TranslationSliceToGraphType(translationsToPolish), nil
+}
+
+// Suggestions is the resolver for the suggestions field.
+func (r *queryResolver) Suggestions(ctx context.Context, word string, language model.Language, limit *int32) ([]*model.Suggestion, error) {
+	suggestions, err := r.Store.GetSuggestions(word, language, r.Converter.LimitFromArg(limit))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.SuggestionSliceToGraphType(suggestions, language), nil
+}
+
+// SearchExamples is the resolver for the searchExamples field.
+func (r *queryResolver) SearchExamples(ctx context.Context, query string, language *model.Language, first *int32, after *string) (*model.ExampleSearchConnection, error) {
+	page, err := r.Converter.PageRequestFromArgs(first, after, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := r.Store.SearchExamples(query, language, page)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.ExampleMatchPageToConnection(matches), nil
+}
+
+// Autocomplete is the resolver for the autocomplete field.
+func (r *queryResolver) Autocomplete(ctx context.Context, prefix string, language model.Language, ranking model.AutocompleteRanking, limit *int32) ([]*model.WordEntry, error) {
+	entries, err := r.Store.Autocomplete(prefix, language, ranking, r.Converter.LimitFromArg(limit))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.WordEntrySliceToGraphType(entries, language), nil
+}
+
+// DueCards is the resolver for the dueCards field.
+func (r *queryResolver) DueCards(ctx context.Context, limit *int32, direction *model.StudyDirection) ([]*model.Card, error) {
+	name, err := r.learner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.Store.GetDueCards(name, r.Converter.StudyDirectionsFromArg(direction), r.Clock(), r.Converter.LimitFromArg(limit))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.CardSliceToGraphType(cards), nil
+}
+
+// GenerateQuiz is the resolver for the generateQuiz field.
+func (r *queryResolver) GenerateQuiz(ctx context.Context, size *int32, direction model.StudyDirection, tag *string, choices *int32, seed *int32) (*model.Quiz, error) {
+	options := r.Converter.QuizOptionsFromArgs(size, direction, tag, choices, seed)
+	questions, err := quiz.Generate(r.Store, options)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.QuizToGraphType(options, questions), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	principal := auth.PrincipalFrom(ctx)
+	if principal == nil {
+		return nil, nil
+	}
+	user, err := r.Store.GetUserById(principal.UserID)
 	if err != nil {
 		return nil, err
 	}
-	return r.PrepareTranslationSliceToSend(&translationsToPolish)
+	return r.Converter.UserToGraphType(user), nil
+}
+
+// APIKeys is the resolver for the apiKeys field.
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	principal, err := r.principal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := r.Store.GetAPIKeysByUserId(principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.APIKeySliceToGraphType(keys), nil
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, entityType *model.AuditEntityType, entityID *int, since *time.Time, first *int32, after *string) (*model.AuditEntryConnection, error) {
+	page, err := r.Converter.PageRequestFromArgs(first, after, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := r.Store.GetAuditLogPage(r.Converter.AuditFilterFromArgs(entityType, entityID, since), page)
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.AuditEntryPageToConnection(entries), nil
+}
+
+// History is the resolver for the history field.
+func (r *queryResolver) History(ctx context.Context, entity model.RevisionEntity, id int) ([]model.Revision, error) {
+	switch entity {
+	case model.RevisionEntityPolishWord:
+		revisions, err := r.Store.GetPolishWordRevisions(uint(id))
+		if err != nil {
+			return nil, err
+		}
+		return converter.RevisionUnion(converter.RevisionSliceToGraphType(revisions, r.Converter.PolishWordRevisionToGraphType)), nil
+	case model.RevisionEntityEnglishWord:
+		revisions, err := r.Store.GetEnglishWordRevisions(uint(id))
+		if err != nil {
+			return nil, err
+		}
+		return converter.RevisionUnion(converter.RevisionSliceToGraphType(revisions, r.Converter.EnglishWordRevisionToGraphType)), nil
+	case model.RevisionEntityTranslation:
+		revisions, err := r.Store.GetTranslationRevisions(uint(id))
+		if err != nil {
+			return nil, err
+		}
+		return converter.RevisionUnion(converter.RevisionSliceToGraphType(revisions, r.Converter.TranslationRevisionToGraphType)), nil
+	case model.RevisionEntityExample:
+		revisions, err := r.Store.GetExampleRevisions(uint(id))
+		if err != nil {
+			return nil, err
+		}
+		return converter.RevisionUnion(converter.RevisionSliceToGraphType(revisions, r.Converter.ExampleRevisionToGraphType)), nil
+	default:
+		return nil, customErrors.ErrUnknownEntity
+	}
+}
+
+// Trash is the resolver for the trash field.
+func (r *queryResolver) Trash(ctx context.Context, entity *model.RevisionEntity) ([]*model.TrashItem, error) {
+	trash, err := r.Store.GetTrash()
+	if err != nil {
+		return nil, err
+	}
+	items := r.Converter.TrashToGraphType(trash)
+	if entity == nil {
+		return items, nil
+	}
+	filtered := []*model.TrashItem{}
+	for _, item := range items {
+		if item.Entity == *entity {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
 }
 
 // GetPolishWord is the resolver for the getPolishWord field.
 func (r *queryResolver) GetPolishWord(ctx context.Context, id int) (*model.PolishWord, error) {
-	polishWordDbModel, err := r.DBManager.GetPolishWordById(uint(id))
+	polishWordDbModel, err := r.Store.GetPolishWordById(uint(id))
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +535,7 @@ func (r *queryResolver) GetPolishWord(ctx context.Context, id int) (*model.Polis
 
 // GetEnglishWord is the resolver for the getEnglishWord field.
 func (r *queryResolver) GetEnglishWord(ctx context.Context, id int) (*model.EnglishWord, error) {
-	englishWordDbModel, err := r.DBManager.GetEnglishWordById(uint(id))
+	englishWordDbModel, err := r.Store.GetEnglishWordById(uint(id))
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +544,7 @@ func (r *queryResolver) GetEnglishWord(ctx context.Context, id int) (*model.Engl
 
 // GetExample is the resolver for the getExample field.
 func (r *queryResolver) GetExample(ctx context.Context, id int) (*model.Example, error) {
-	exampleDbModel, err := r.DBManager.GetExampleById(uint(id))
+	exampleDbModel, err := r.Store.GetExampleById(uint(id))
 	if err != nil {
 		return nil, err
 	}
@@ -191,21 +553,75 @@ func (r *queryResolver) GetExample(ctx context.Context, id int) (*model.Example,
 
 // GetTranslation is the resolver for the getTranslation field.
 func (r *queryResolver) GetTranslation(ctx context.Context, id int) (*model.Translation, error) {
-	translationDbModel, err := r.DBManager.GetTranslationById(uint(id))
+	translationDbModel, err := r.Store.GetTranslationById(uint(id))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.TranslationToGraphType(translationDbModel), nil
+}
+
+// PolishWord is the resolver for the polishWord field.
+func (r *translationResolver) PolishWord(ctx context.Context, obj *model.Translation) (*model.PolishWord, error) {
+	polishWord, err := loaders.For(ctx).PolishWords.Load(ctx, uint(obj.PolishWordID))
 	if err != nil {
 		return nil, err
 	}
-	if err := r.DBManager.PopulateTranslationWithAssociations(translationDbModel); err != nil {
+	return r.Converter.PolishToGraphType(polishWord), nil
+}
+
+// EnglishWord is the resolver for the englishWord field.
+func (r *translationResolver) EnglishWord(ctx context.Context, obj *model.Translation) (*model.EnglishWord, error) {
+	englishWord, err := loaders.For(ctx).EnglishWords.Load(ctx, uint(obj.EnglishWordID))
+	if err != nil {
 		return nil, err
 	}
-	return r.Converter.TranslationToGraphType(translationDbModel), nil
+	return r.Converter.EnglishToGraphType(englishWord), nil
 }
 
+// Examples is the resolver for the examples field.
+func (r *translationResolver) Examples(ctx context.Context, obj *model.Translation) ([]*model.Example, error) {
+	examples, err := loaders.For(ctx).Examples.Load(ctx, uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return r.Converter.ExampleSliceToGraphType(&examples), nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *translationResolver) Tags(ctx context.Context, obj *model.Translation) ([]string, error) {
+	return loaders.For(ctx).Tags.Load(ctx, uint(obj.ID))
+}
+
+// History is the resolver for the history field.
+func (r *translationResolver) History(ctx context.Context, obj *model.Translation) ([]*model.TranslationRevision, error) {
+	revisions, err := r.Store.GetTranslationRevisions(uint(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return converter.RevisionSliceToGraphType(revisions, r.Converter.TranslationRevisionToGraphType), nil
+}
+
+// EnglishWord returns EnglishWordResolver implementation.
+func (r *Resolver) EnglishWord() EnglishWordResolver { return &englishWordResolver{r} }
+
+// Example returns ExampleResolver implementation.
+func (r *Resolver) Example() ExampleResolver { return &exampleResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// PolishWord returns PolishWordResolver implementation.
+func (r *Resolver) PolishWord() PolishWordResolver { return &polishWordResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Translation returns TranslationResolver implementation.
+func (r *Resolver) Translation() TranslationResolver { return &translationResolver{r} }
+
+type englishWordResolver struct{ *Resolver }
+type exampleResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type polishWordResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type translationResolver struct{ *Resolver }
@@ -1,40 +1,65 @@
 package graph
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
-	"github.com/realagmag/dictionaryGO/config"
+	"github.com/realagmag/dictionaryGO/graph/loaders"
+	"github.com/realagmag/dictionaryGO/internal/auth"
 	"github.com/realagmag/dictionaryGO/internal/converter"
 	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/exporter"
+	"github.com/realagmag/dictionaryGO/internal/srs"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
 const defaultPort = "8080"
 
-func StartServer() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
-	}
+// Options configure NewServer. Without Auth every request may run every
+// operation, learners are then named by the X-Learner header.
+type Options struct {
+	// Clock tells when reviews happen, time.Now when nil.
+	Clock srs.Clock
+	Auth  *auth.Service
+	// AllowAnonymousReads lets requests without credentials run queries.
+	AllowAnonymousReads bool
+	// BatchWait is how long the association lookups of an operation are
+	// collected into one store call, loaders.DefaultBatchWait when zero.
+	BatchWait time.Duration
+}
 
+// NewServer builds the GraphQL handler serving the dictionary from store.
+func NewServer(store database.DictionaryStore, options Options) http.Handler {
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
 	srv := handler.New(NewExecutableSchema(
 		Config{
 			Resolvers: &Resolver{
-				database.NewDBManager(config.DB),
-				&converter.Converter{},
+				Store:     store,
+				Converter: &converter.Converter{},
+				Clock:     options.Clock,
+				Auth:      options.Auth,
+			},
+			Directives: DirectiveRoot{
+				HasRole: hasRole(options.Auth != nil, options.AllowAnonymousReads),
 			}}))
 
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
 
+	srv.SetErrorPresenter(presentError)
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
 	srv.Use(extension.Introspection{})
@@ -42,8 +67,38 @@ func StartServer() {
 		Cache: lru.New[string](100),
 	})
 
+	// Loaders cache what they fetch, so every operation gets fresh ones.
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(loaders.WithLoaders(ctx, loaders.NewLoaders(store, options.BatchWait)))
+	})
+	if options.Auth == nil {
+		return srv
+	}
+	return options.Auth.Middleware(srv)
+}
+
+func StartServer(store database.DictionaryStore) {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if len(secret) == 0 {
+		log.Println("JWT_SECRET is not set, tokens will stop working on restart")
+		var err error
+		if secret, err = auth.RandomSecret(); err != nil {
+			log.Fatalf("failed to generate a JWT secret: %v", err)
+		}
+	}
+	service := auth.NewService(store, secret)
+	allowAnonymousReads := os.Getenv("ALLOW_ANONYMOUS_READS") == "true"
+
+	srv := NewServer(store, Options{Auth: service, AllowAnonymousReads: allowAnonymousReads})
+
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	http.Handle("/query", srv)
+	http.Handle("/export", service.Middleware(auth.RequirePrincipal(exporter.Handler(store), allowAnonymousReads)))
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
@@ -0,0 +1,385 @@
+// Package audit records who changed what in the dictionary. Changes made
+// through a Store are written to the audit log in the transaction of the
+// change, so the log never misses a change nor records one rolled back.
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/converter"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// Entity types, named like the AuditEntityType enum.
+const (
+	EntityPolishWord  = "POLISH_WORD"
+	EntityEnglishWord = "ENGLISH_WORD"
+	EntityTranslation = "TRANSLATION"
+	EntityExample     = "EXAMPLE"
+	EntityUser        = "USER"
+	EntityAPIKey      = "API_KEY"
+	EntityCard        = "CARD"
+)
+
+// change is what a single call did to an entity. A nil Before is a creation,
+// a nil After a deletion.
+type change struct {
+	entityType string
+	entityID   uint
+	before     any
+	after      any
+}
+
+// Store records every change made through it as done by actor in operation.
+// Reads pass through to the wrapped store.
+type Store struct {
+	database.DictionaryStore
+	actor     string
+	operation string
+	now       func() time.Time
+	converter converter.Converter
+}
+
+var _ database.DictionaryStore = (*Store)(nil)
+
+// NewStore wraps store, dating the entries it records by now.
+func NewStore(store database.DictionaryStore, actor string, operation string, now func() time.Time) *Store {
+	return &Store{DictionaryStore: store, actor: actor, operation: operation, now: now}
+}
+
+// record runs fn in a transaction of the wrapped store and writes the
+// changes it reports to the audit log in the same transaction.
+func (s *Store) record(fn func(store database.DictionaryStore) ([]change, error)) error {
+	return s.DictionaryStore.WithinTransaction(func(store database.DictionaryStore) error {
+		changes, err := fn(store)
+		if err != nil {
+			return err
+		}
+		occurredAt := s.now()
+		for _, change := range changes {
+			entry := dbModels.AuditEntry{
+				Actor:      s.actor,
+				OccurredAt: occurredAt,
+				Operation:  s.operation,
+				EntityType: change.entityType,
+				EntityID:   change.entityID,
+			}
+			if entry.Before, err = marshal(change.before); err != nil {
+				return err
+			}
+			if entry.After, err = marshal(change.after); err != nil {
+				return err
+			}
+			if _, err := store.AddAuditEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func marshal(snapshot any) (*string, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	text := string(encoded)
+	return &text, nil
+}
+
+// WithinTransaction keeps recording the changes fn makes.
+func (s *Store) WithinTransaction(fn func(store database.DictionaryStore) error) error {
+	return s.DictionaryStore.WithinTransaction(func(store database.DictionaryStore) error {
+		return fn(&Store{DictionaryStore: store, actor: s.actor, operation: s.operation, now: s.now})
+	})
+}
+
+// AddPolishWord, AddEnglishWord and AddExampleToTranslation record nothing
+// when they return the stored entry instead of creating one.
+func (s *Store) AddPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	var added *dbModels.PolishWord
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		_, findErr := store.FindPolishWord(word)
+		if added, err = store.AddPolishWord(word); err != nil || findErr == nil {
+			return nil, err
+		}
+		after, err := s.polishWord(store, added.ID)
+		return []change{{EntityPolishWord, added.ID, nil, after}}, err
+	})
+	return added, err
+}
+
+func (s *Store) AddEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
+	var added *dbModels.EnglishWord
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		_, findErr := store.FindEnglishWord(word)
+		if added, err = store.AddEnglishWord(word); err != nil || findErr == nil {
+			return nil, err
+		}
+		return []change{{EntityEnglishWord, added.ID, nil, s.converter.EnglishToGraphType(added)}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) SetPolishWordForms(polishWordID uint, forms []dbModels.WordForm, replace bool) ([]*dbModels.WordForm, error) {
+	var stored []*dbModels.WordForm
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		before, err := s.polishWord(store, polishWordID)
+		if err != nil {
+			return nil, err
+		}
+		if stored, err = store.SetPolishWordForms(polishWordID, forms, replace); err != nil {
+			return nil, err
+		}
+		after, err := s.polishWord(store, polishWordID)
+		return []change{{EntityPolishWord, polishWordID, before, after}}, err
+	})
+	return stored, err
+}
+
+func (s *Store) AddTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
+	var added *dbModels.Translation
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		var before any
+		if existing, err := store.FindTranslation(translationInput); err == nil {
+			if before, err = s.translation(store, existing.ID); err != nil {
+				return nil, err
+			}
+		}
+		if added, err = store.AddTranslation(translationInput); err != nil {
+			return nil, err
+		}
+		after, err := s.translation(store, added.ID)
+		return []change{{EntityTranslation, added.ID, before, after}}, err
+	})
+	return added, err
+}
+
+func (s *Store) AddExampleToTranslation(example *model.ExampleInput, translationID uint) (*dbModels.Example, error) {
+	var added *dbModels.Example
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		_, findErr := store.FindExample(translationID, example.Text)
+		if added, err = store.AddExampleToTranslation(example, translationID); err != nil || findErr == nil {
+			return nil, err
+		}
+		return []change{{EntityExample, added.ID, nil, s.converter.ExampleToGraphType(added)}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) SetTranslationTags(translationID uint, tags []string) ([]*dbModels.TranslationTag, error) {
+	var stored []*dbModels.TranslationTag
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		before, err := s.translation(store, translationID)
+		if err != nil {
+			return nil, err
+		}
+		if stored, err = store.SetTranslationTags(translationID, tags); err != nil {
+			return nil, err
+		}
+		after, err := s.translation(store, translationID)
+		return []change{{EntityTranslation, translationID, before, after}}, err
+	})
+	return stored, err
+}
+
+// ReviewCard records the card after the review only, the schedule before it
+// follows from the previous entry of the card.
+func (s *Store) ReviewCard(learner string, translationID uint, direction string, grade int, now time.Time) (*dbModels.ReviewCard, error) {
+	var card *dbModels.ReviewCard
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if card, err = store.ReviewCard(learner, translationID, direction, grade, now); err != nil {
+			return nil, err
+		}
+		return []change{{EntityCard, card.ID, nil, s.converter.CardToGraphType(card)}}, nil
+	})
+	return card, err
+}
+
+func (s *Store) AddUser(user dbModels.User) (*dbModels.User, error) {
+	var added *dbModels.User
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if added, err = store.AddUser(user); err != nil {
+			return nil, err
+		}
+		return []change{{EntityUser, added.ID, nil, s.converter.UserToGraphType(added)}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) SetUserRole(id uint, role string) (*dbModels.User, error) {
+	var updated *dbModels.User
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		before, err := store.GetUserById(id)
+		if err != nil {
+			return nil, err
+		}
+		if updated, err = store.SetUserRole(id, role); err != nil {
+			return nil, err
+		}
+		return []change{{EntityUser, id, s.converter.UserToGraphType(before), s.converter.UserToGraphType(updated)}}, nil
+	})
+	return updated, err
+}
+
+func (s *Store) AddAPIKey(key dbModels.APIKey) (*dbModels.APIKey, error) {
+	var added *dbModels.APIKey
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if added, err = store.AddAPIKey(key); err != nil {
+			return nil, err
+		}
+		return []change{{EntityAPIKey, added.ID, nil, s.converter.APIKeyToGraphType(added)}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) DeleteAPIKey(userID uint, id uint) error {
+	return s.record(func(store database.DictionaryStore) ([]change, error) {
+		keys, err := store.GetAPIKeysByUserId(userID)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.DeleteAPIKey(userID, id); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if key.ID == id {
+				return []change{{EntityAPIKey, id, s.converter.APIKeyToGraphType(key), nil}}, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// DeleteRecordFromTable records the deleted entity only, not the ones its
+// deletion cascades to. Deleting a missing entity records nothing.
+func (s *Store) DeleteRecordFromTable(table interface{}, id uint) error {
+	return s.record(func(store database.DictionaryStore) ([]change, error) {
+		entityType, before, err := s.snapshot(store, table, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.DeleteRecordFromTable(table, id); err != nil {
+			return nil, err
+		}
+		if before == nil {
+			return nil, nil
+		}
+		return []change{{entityType, id, before, nil}}, nil
+	})
+}
+
+// RestoreFromTrash records the restored entity only, like
+// DeleteRecordFromTable.
+func (s *Store) RestoreFromTrash(table interface{}, id uint) (interface{}, error) {
+	var restored interface{}
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if restored, err = store.RestoreFromTrash(table, id); err != nil {
+			return nil, err
+		}
+		entityType, after, err := s.snapshot(store, table, id)
+		return []change{{entityType, id, nil, after}}, err
+	})
+	return restored, err
+}
+
+// PurgeTrash records every entity it deletes for good, as it was in the
+// trash, with when it was deleted and the cutoff of the purge.
+func (s *Store) PurgeTrash(olderThan *time.Time) (int64, error) {
+	var purged int64
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		trash, err := store.GetTrash()
+		if err != nil {
+			return nil, err
+		}
+		changes = s.purged(trash, olderThan)
+		if purged, err = store.PurgeTrash(olderThan); err != nil {
+			return nil, err
+		}
+		return changes, nil
+	})
+	return purged, err
+}
+
+// RestoreRecord records the entity before and after the restore, a recreated
+// entity has no before.
+func (s *Store) RestoreRecord(record interface{}) error {
+	id, err := recordID(record)
+	if err != nil {
+		return err
+	}
+	return s.record(func(store database.DictionaryStore) ([]change, error) {
+		entityType, before, err := s.snapshot(store, record, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.RestoreRecord(record); err != nil {
+			return nil, err
+		}
+		_, after, err := s.snapshot(store, record, id)
+		return []change{{entityType, id, before, after}}, err
+	})
+}
+
+// The Change methods lock the entry before reading it, so concurrent changes
+// record the state the previous one left.
+func (s *Store) ChangeExampleText(id uint, text string, expectedVersion *uint) (*dbModels.Example, error) {
+	var updated *dbModels.Example
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if err := store.LockRecord(&dbModels.Example{}, id); err != nil {
+			return nil, err
+		}
+		before, err := store.GetExampleById(id)
+		if err != nil {
+			return nil, err
+		}
+		if updated, err = store.ChangeExampleText(id, text, expectedVersion); err != nil {
+			return nil, err
+		}
+		return []change{{EntityExample, id, s.converter.ExampleToGraphType(before), s.converter.ExampleToGraphType(updated)}}, nil
+	})
+	return updated, err
+}
+
+func (s *Store) ChangePolishWordText(id uint, text string, expectedVersion *uint) (*dbModels.PolishWord, error) {
+	var updated *dbModels.PolishWord
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if err := store.LockRecord(&dbModels.PolishWord{}, id); err != nil {
+			return nil, err
+		}
+		before, err := s.polishWord(store, id)
+		if err != nil {
+			return nil, err
+		}
+		if updated, err = store.ChangePolishWordText(id, text, expectedVersion); err != nil {
+			return nil, err
+		}
+		after, err := s.polishWord(store, id)
+		return []change{{EntityPolishWord, id, before, after}}, err
+	})
+	return updated, err
+}
+
+func (s *Store) ChangeEnglishWordText(id uint, text string, expectedVersion *uint) (*dbModels.EnglishWord, error) {
+	var updated *dbModels.EnglishWord
+	err := s.record(func(store database.DictionaryStore) (changes []change, err error) {
+		if err := store.LockRecord(&dbModels.EnglishWord{}, id); err != nil {
+			return nil, err
+		}
+		before, err := store.GetEnglishWordById(id)
+		if err != nil {
+			return nil, err
+		}
+		if updated, err = store.ChangeEnglishWordText(id, text, expectedVersion); err != nil {
+			return nil, err
+		}
+		return []change{{EntityEnglishWord, id, s.converter.EnglishToGraphType(before), s.converter.EnglishToGraphType(updated)}}, nil
+	})
+	return updated, err
+}
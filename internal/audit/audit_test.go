@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func auditLog(t *testing.T, store database.DictionaryStore) []*dbModels.AuditEntry {
+	page, err := store.GetAuditLogPage(database.AuditFilter{}, database.PageRequest{})
+	assert.NoError(t, err)
+	return page.Items
+}
+
+func TestChangesAreRecorded(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "updatePolishWordText", func() time.Time { return now })
+
+	word, _ := inner.AddPolishWord(dbModels.PolishWord{Text: "kto"})
+	_, err := store.ChangePolishWordText(word.ID, "kot", nil)
+	assert.NoError(t, err)
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "ala", entries[0].Actor)
+	assert.Equal(t, now, entries[0].OccurredAt)
+	assert.Equal(t, "updatePolishWordText", entries[0].Operation)
+	assert.Equal(t, EntityPolishWord, entries[0].EntityType)
+	assert.Equal(t, word.ID, entries[0].EntityID)
+	assert.JSONEq(t, `{"id":1,"text":"kto","forms":[],"version":1}`, *entries[0].Before)
+	assert.JSONEq(t, `{"id":1,"text":"kot","forms":[],"version":2}`, *entries[0].After)
+
+	_, err = store.ChangePolishWordText(999, "pies", nil)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+	assert.Len(t, auditLog(t, inner), 1)
+}
+
+func TestAddingStoredEntriesRecordsNothing(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "createPolishWord", func() time.Time { return now })
+
+	for range 2 {
+		_, err := store.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+		assert.NoError(t, err)
+		_, err = store.AddEnglishWord(dbModels.EnglishWord{Text: "cat"})
+		assert.NoError(t, err)
+	}
+	translation, _ := inner.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	for range 2 {
+		_, err := store.AddExampleToTranslation(&model.ExampleInput{Text: "Kot śpi.", InPolish: true}, translation.ID)
+		assert.NoError(t, err)
+	}
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 3, "only the first additions created something")
+	for _, entry := range entries {
+		assert.Nil(t, entry.Before)
+	}
+}
+
+func TestDeletionsAreRecordedWithTheDeletedEntity(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "deleteTranslation", func() time.Time { return now })
+
+	translation, _ := inner.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	inner.SetTranslationTags(translation.ID, []string{"zwierzęta"})
+	assert.NoError(t, store.DeleteRecordFromTable(dbModels.Translation{}, translation.ID))
+	// Nothing is deleted, so nothing is recorded.
+	assert.NoError(t, store.DeleteRecordFromTable(dbModels.Translation{}, translation.ID))
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 1)
+	assert.Nil(t, entries[0].After)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"polishWord": {"id": 1, "text": "kot", "forms": null, "version": 1},
+		"englishWord": {"id": 1, "text": "cat", "version": 1},
+		"examples": [{"id": 1, "text": "Ala ma kota", "inPolish": true, "translationID": 1, "version": 1}],
+		"tags": ["zwierzęta"]
+	}`, *entries[0].Before)
+}
+
+func TestEntriesAreRolledBackWithTheirChange(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "importTranslations", func() time.Time { return now })
+
+	failure := errors.New("dry run")
+	err := store.WithinTransaction(func(tx database.DictionaryStore) error {
+		if _, err := tx.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"}); err != nil {
+			return err
+		}
+		assert.Len(t, auditLog(t, tx), 1)
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	assert.Empty(t, auditLog(t, inner))
+
+	store.WithinTransaction(func(tx database.DictionaryStore) error {
+		_, err := tx.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+		return err
+	})
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, EntityTranslation, entries[0].EntityType)
+	assert.Nil(t, entries[0].Before)
+}
+
+func TestRestoresAreRecorded(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "restoreRevision", func() time.Time { return now })
+
+	word, _ := inner.AddEnglishWord(dbModels.EnglishWord{Text: "cat"})
+	inner.DeleteRecordFromTable(dbModels.EnglishWord{}, word.ID)
+	assert.NoError(t, store.RestoreRecord(&dbModels.EnglishWord{ID: word.ID, Text: "cat"}))
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, EntityEnglishWord, entries[0].EntityType)
+	assert.Nil(t, entries[0].Before)
+	assert.JSONEq(t, `{"id":1,"text":"cat","version":2}`, *entries[0].After)
+}
+
+func TestRestoresFromTrashAreRecorded(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "ala", "restore", func() time.Time { return now })
+
+	word, _ := inner.AddEnglishWord(dbModels.EnglishWord{Text: "cat"})
+	inner.DeleteRecordFromTable(dbModels.EnglishWord{}, word.ID)
+	_, err := store.RestoreFromTrash(dbModels.EnglishWord{}, word.ID)
+	assert.NoError(t, err)
+	_, err = store.RestoreFromTrash(dbModels.EnglishWord{}, word.ID)
+	assert.Equal(t, customErrors.ErrNotInTrash, err)
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "restore", entries[0].Operation)
+	assert.Nil(t, entries[0].Before)
+	assert.JSONEq(t, `{"id":1,"text":"cat","version":1}`, *entries[0].After)
+}
+
+func TestPurgesAreRecorded(t *testing.T) {
+	inner := memstore.NewStore()
+	store := NewStore(inner, "admin", "purgeTrash", func() time.Time { return now })
+
+	translation, _ := inner.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	inner.AddPolishWord(dbModels.PolishWord{Text: "pies"})
+	inner.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+
+	past := time.Now().Add(-time.Hour)
+	purged, err := store.PurgeTrash(&past)
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.Empty(t, auditLog(t, inner))
+
+	purged, err = store.PurgeTrash(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	entries := auditLog(t, inner)
+	assert.Len(t, entries, 2)
+	entityTypes := []string{entries[0].EntityType, entries[1].EntityType}
+	assert.ElementsMatch(t, []string{EntityTranslation, EntityPolishWord}, entityTypes)
+	for _, entry := range entries {
+		assert.Equal(t, "admin", entry.Actor)
+		assert.Equal(t, "purgeTrash", entry.Operation)
+		assert.Nil(t, entry.After)
+		assert.Contains(t, *entry.Before, `"olderThan":null`)
+		if entry.EntityType == EntityPolishWord {
+			assert.Equal(t, translation.PolishWordID, entry.EntityID)
+			assert.Contains(t, *entry.Before, `"entity":{"id":1,"text":"kot"`)
+		}
+	}
+}
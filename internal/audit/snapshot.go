@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// Entities are recorded in the shape the API returns them, translations with
+// their words, examples and tags, Polish words with their forms.
+type translationSnapshot struct {
+	ID          int                `json:"id"`
+	PolishWord  *model.PolishWord  `json:"polishWord"`
+	EnglishWord *model.EnglishWord `json:"englishWord"`
+	Examples    []*model.Example   `json:"examples"`
+	Tags        []string           `json:"tags"`
+}
+
+func (s *Store) polishWord(store database.DictionaryStore, id uint) (*model.PolishWord, error) {
+	word, err := store.GetPolishWordById(id)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := store.GetWordFormsByPolishWordIds([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(forms, func(i, j int) bool { return forms[i].ID < forms[j].ID })
+	snapshot := s.converter.PolishToGraphType(word)
+	snapshot.Forms = s.converter.WordFormSliceToGraphType(forms)
+	return snapshot, nil
+}
+
+func (s *Store) translation(store database.DictionaryStore, id uint) (*translationSnapshot, error) {
+	translation := &dbModels.Translation{ID: id}
+	if err := store.PopulateTranslationWithAssociations(translation); err != nil {
+		return nil, err
+	}
+	tags, err := store.GetTagsByTranslationIds([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	names := s.converter.TagNames(tags)
+	sort.Strings(names)
+	return &translationSnapshot{
+		ID:          int(translation.ID),
+		PolishWord:  s.converter.PolishToGraphType(&translation.PolishWord),
+		EnglishWord: s.converter.EnglishToGraphType(&translation.EnglishWord),
+		Examples:    s.converter.ExampleSliceToGraphType(&translation.Examples),
+		Tags:        names,
+	}, nil
+}
+
+// snapshot returns the entity type of a table DeleteRecordFromTable accepts,
+// or of a record RestoreRecord accepts, and the entity with id, nil when
+// there is none.
+func (s *Store) snapshot(store database.DictionaryStore, table interface{}, id uint) (string, any, error) {
+	var (
+		entityType string
+		entity     any
+		err        error
+	)
+	switch table.(type) {
+	case dbModels.PolishWord, *dbModels.PolishWord:
+		entityType = EntityPolishWord
+		entity, err = s.polishWord(store, id)
+	case dbModels.EnglishWord, *dbModels.EnglishWord:
+		entityType = EntityEnglishWord
+		var word *dbModels.EnglishWord
+		if word, err = store.GetEnglishWordById(id); err == nil {
+			entity = s.converter.EnglishToGraphType(word)
+		}
+	case dbModels.Translation, *dbModels.Translation:
+		entityType = EntityTranslation
+		entity, err = s.translation(store, id)
+	case dbModels.Example, *dbModels.Example:
+		entityType = EntityExample
+		var example *dbModels.Example
+		if example, err = store.GetExampleById(id); err == nil {
+			entity = s.converter.ExampleToGraphType(example)
+		}
+	default:
+		return "", nil, fmt.Errorf("audit: unsupported table %T", table)
+	}
+	if isNotFound(err) {
+		return entityType, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return entityType, entity, nil
+}
+
+// purgedSnapshot is an entity purged from the trash.
+type purgedSnapshot struct {
+	Entity    any        `json:"entity"`
+	DeletedAt time.Time  `json:"deletedAt"`
+	OlderThan *time.Time `json:"olderThan"`
+}
+
+// purged returns the changes purging the entries of trash deleted before
+// olderThan, or all of them, makes.
+func (s *Store) purged(trash *database.Trash, olderThan *time.Time) []change {
+	var changes []change
+	add := func(entityType string, id uint, deletedAt time.Time, entity any) {
+		if olderThan != nil && !deletedAt.Before(*olderThan) {
+			return
+		}
+		snapshot := purgedSnapshot{Entity: entity, DeletedAt: deletedAt, OlderThan: olderThan}
+		changes = append(changes, change{entityType, id, snapshot, nil})
+	}
+	for _, example := range trash.Examples {
+		add(EntityExample, example.ID, example.DeletedAt.Time, s.converter.ExampleToGraphType(example))
+	}
+	for _, translation := range trash.Translations {
+		add(EntityTranslation, translation.ID, translation.DeletedAt.Time, &translationSnapshot{
+			ID:          int(translation.ID),
+			PolishWord:  s.converter.PolishToGraphType(&translation.PolishWord),
+			EnglishWord: s.converter.EnglishToGraphType(&translation.EnglishWord),
+		})
+	}
+	for _, word := range trash.EnglishWords {
+		add(EntityEnglishWord, word.ID, word.DeletedAt.Time, s.converter.EnglishToGraphType(word))
+	}
+	for _, word := range trash.PolishWords {
+		add(EntityPolishWord, word.ID, word.DeletedAt.Time, s.converter.PolishToGraphType(word))
+	}
+	return changes
+}
+
+// recordID returns the ID of a record RestoreRecord accepts.
+func recordID(record interface{}) (uint, error) {
+	switch record := record.(type) {
+	case *dbModels.PolishWord:
+		return record.ID, nil
+	case *dbModels.EnglishWord:
+		return record.ID, nil
+	case *dbModels.Translation:
+		return record.ID, nil
+	case *dbModels.Example:
+		return record.ID, nil
+	default:
+		return 0, fmt.Errorf("audit: unsupported record %T", record)
+	}
+}
+
+func isNotFound(err error) bool {
+	for _, notFound := range []error{
+		customErrors.ErrPolishWordNotFound,
+		customErrors.ErrEnglishWordNotFound,
+		customErrors.ErrTranslationNotFound,
+		customErrors.ErrExampleNotFound,
+	} {
+		if errors.Is(err, notFound) {
+			return true
+		}
+	}
+	return false
+}
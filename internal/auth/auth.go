@@ -0,0 +1,190 @@
+// Package auth signs users in with passwords, API keys and JWT bearer
+// tokens, and carries the authenticated principal in request contexts.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// APIKeyPrefix starts every API key, so keys can be told apart from
+	// tokens and found by secret scanners.
+	APIKeyPrefix = "dgo_"
+	// apiKeyShownLength is how much of a key is kept in the clear to tell
+	// keys apart.
+	apiKeyShownLength = len(APIKeyPrefix) + 8
+
+	DefaultTokenTTL   = 24 * time.Hour
+	MinPasswordLength = 8
+)
+
+// Principal is who sent a request and what they may do.
+type Principal struct {
+	UserID   uint
+	Username string
+	Role     Role
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of an authenticated request, nil for
+// anonymous ones.
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(ctxKey{}).(*Principal)
+	return principal
+}
+
+// Service manages the users of store and signs their tokens with Secret.
+type Service struct {
+	Store    database.DictionaryStore
+	Secret   []byte
+	TokenTTL time.Duration
+	Now      func() time.Time
+}
+
+func NewService(store database.DictionaryStore, secret []byte) *Service {
+	return &Service{Store: store, Secret: secret, TokenTTL: DefaultTokenTTL, Now: time.Now}
+}
+
+// WithStore returns a copy of the service keeping its users in store.
+func (service *Service) WithStore(store database.DictionaryStore) *Service {
+	copied := *service
+	copied.Store = store
+	return &copied
+}
+
+// RandomSecret returns a secret for signing tokens, for when none is
+// configured. Tokens signed with it stop working on restart.
+func RandomSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (service *Service) CreateUser(username string, password string, role Role) (*dbModels.User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, customErrors.ErrInvalidUsername
+	}
+	if len(password) < MinPasswordLength {
+		return nil, customErrors.ErrPasswordTooShort
+	}
+	if _, ok := roleRanks[role]; !ok {
+		return nil, customErrors.ErrUnknownRole
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	return service.Store.AddUser(dbModels.User{Username: username, PasswordHash: string(hash), Role: string(role), CreatedAt: service.Now()})
+}
+
+// SetRole changes what the user may do, from their next request on.
+func (service *Service) SetRole(username string, role Role) (*dbModels.User, error) {
+	if _, ok := roleRanks[role]; !ok {
+		return nil, customErrors.ErrUnknownRole
+	}
+	user, err := service.Store.GetUserByUsername(strings.TrimSpace(username))
+	if err != nil {
+		return nil, err
+	}
+	return service.Store.SetUserRole(user.ID, string(role))
+}
+
+// Login checks the password of the user and issues a token for them. Unknown
+// users and wrong passwords are both reported as ErrInvalidCredentials.
+func (service *Service) Login(username string, password string) (string, time.Time, *dbModels.User, error) {
+	user, err := service.Store.GetUserByUsername(strings.TrimSpace(username))
+	if errors.Is(err, customErrors.ErrUserNotFound) {
+		return "", time.Time{}, nil, customErrors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", time.Time{}, nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return "", time.Time{}, nil, customErrors.ErrInvalidCredentials
+	}
+	token, expiresAt, err := service.IssueToken(user)
+	if err != nil {
+		return "", time.Time{}, nil, err
+	}
+	return token, expiresAt, user, nil
+}
+
+// CreateAPIKey returns a new key of the user. The key is not stored, only
+// its hash, so it cannot be shown again.
+func (service *Service) CreateAPIKey(userID uint, name string) (string, *dbModels.APIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey, err := service.Store.AddAPIKey(dbModels.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    key[:apiKeyShownLength],
+		Hash:      hashAPIKey(key),
+		CreatedAt: service.Now(),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return key, apiKey, nil
+}
+
+// Authenticate returns the principal of an API key or a token, recognized
+// by APIKeyPrefix.
+func (service *Service) Authenticate(credential string) (*Principal, error) {
+	if strings.HasPrefix(credential, APIKeyPrefix) {
+		key, err := service.Store.GetAPIKeyByHash(hashAPIKey(credential))
+		if errors.Is(err, customErrors.ErrAPIKeyNotFound) {
+			return nil, customErrors.ErrInvalidToken
+		}
+		if err != nil {
+			return nil, err
+		}
+		return principalOf(&key.User), nil
+	}
+
+	claims, err := service.verifyToken(credential)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens of removed users must stop working before they expire, and
+	// changed roles must apply at once, so the user is read again.
+	user, err := service.Store.GetUserById(claims.UserID)
+	if errors.Is(err, customErrors.ErrUserNotFound) {
+		return nil, customErrors.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return principalOf(user), nil
+}
+
+func principalOf(user *dbModels.User) *Principal {
+	return &Principal{UserID: user.ID, Username: user.Username, Role: Role(user.Role)}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
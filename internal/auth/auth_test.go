@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService() *Service {
+	service := NewService(memstore.NewStore(), []byte("secret"))
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.Now = func() time.Time { return now }
+	return service
+}
+
+func TestCreateUserAndLogin(t *testing.T) {
+	service := newTestService()
+
+	user, err := service.CreateUser(" ala ", "kot ma ale", RoleReader)
+	assert.NoError(t, err)
+	assert.Equal(t, "ala", user.Username)
+	assert.NotContains(t, user.PasswordHash, "kot ma ale")
+	_, err = service.CreateUser("ala", "kot ma ale", RoleReader)
+	assert.Equal(t, customErrors.ErrUserAlreadyExists, err)
+	_, err = service.CreateUser("ola", "short", RoleReader)
+	assert.Equal(t, customErrors.ErrPasswordTooShort, err)
+	_, err = service.CreateUser(" ", "kot ma ale", RoleReader)
+	assert.Equal(t, customErrors.ErrInvalidUsername, err)
+
+	token, expiresAt, loggedIn, err := service.Login("ala", "kot ma ale")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, loggedIn.ID)
+	assert.Equal(t, service.Now().Add(DefaultTokenTTL), expiresAt)
+
+	principal, err := service.Authenticate(token)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{UserID: user.ID, Username: "ala", Role: RoleReader}, principal)
+
+	_, _, _, err = service.Login("ala", "kot ma psa")
+	assert.Equal(t, customErrors.ErrInvalidCredentials, err)
+	_, _, _, err = service.Login("ola", "kot ma ale")
+	assert.Equal(t, customErrors.ErrInvalidCredentials, err)
+}
+
+func TestRoles(t *testing.T) {
+	assert.True(t, RoleAdmin.Includes(RoleEditor))
+	assert.True(t, RoleEditor.Includes(RoleEditor))
+	assert.False(t, RoleEditor.Includes(RoleAdmin))
+	assert.False(t, RoleReader.Includes(RoleEditor))
+	assert.False(t, Role("").Includes(RoleReader))
+
+	role, err := ParseRole(" editor ")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleEditor, role)
+	_, err = ParseRole("owner")
+	assert.Equal(t, customErrors.ErrUnknownRole, err)
+
+	service := newTestService()
+	_, err = service.CreateUser("ala", "kot ma ale", "OWNER")
+	assert.Equal(t, customErrors.ErrUnknownRole, err)
+	user, _ := service.CreateUser("ala", "kot ma ale", RoleReader)
+	key, _, _ := service.CreateAPIKey(user.ID, "script")
+
+	// A new role applies to credentials issued before it.
+	_, err = service.SetRole("ala", RoleEditor)
+	assert.NoError(t, err)
+	principal, _ := service.Authenticate(key)
+	assert.Equal(t, RoleEditor, principal.Role)
+	_, err = service.SetRole("ola", RoleEditor)
+	assert.Equal(t, customErrors.ErrUserNotFound, err)
+}
+
+func TestRejectedTokens(t *testing.T) {
+	service := newTestService()
+	user, _ := service.CreateUser("ala", "kot ma ale", RoleReader)
+	token, _, _ := service.IssueToken(user)
+
+	parts := strings.Split(token, ".")
+	forgedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"2","name":"ola","iat":0,"exp":9999999999}`))
+	forged := parts[0] + "." + forgedClaims + "." + parts[2]
+	other := NewService(service.Store, []byte("other secret"))
+	otherToken, _, _ := other.IssueToken(user)
+	for _, rejected := range []string{"", "a.b", forged, otherToken, "eyJhbGciOiJub25lIn0." + parts[1] + "."} {
+		_, err := service.Authenticate(rejected)
+		assert.Equal(t, customErrors.ErrInvalidToken, err, rejected)
+	}
+
+	later := service.Now().Add(DefaultTokenTTL)
+	service.Now = func() time.Time { return later }
+	_, err := service.Authenticate(token)
+	assert.Equal(t, customErrors.ErrInvalidToken, err)
+}
+
+func TestAPIKeys(t *testing.T) {
+	service := newTestService()
+	user, _ := service.CreateUser("ala", "kot ma ale", RoleReader)
+
+	key, apiKey, err := service.CreateAPIKey(user.ID, "import script")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+	assert.NotContains(t, apiKey.Hash, key)
+
+	principal, err := service.Authenticate(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "ala", principal.Username)
+
+	service.Store.DeleteAPIKey(user.ID, apiKey.ID)
+	_, err = service.Authenticate(key)
+	assert.Equal(t, customErrors.ErrInvalidToken, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	service := newTestService()
+	user, _ := service.CreateUser("ala", "kot ma ale", RoleReader)
+	key, _, _ := service.CreateAPIKey(user.ID, "script")
+	token, _, _ := service.IssueToken(user)
+	handler := service.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal := PrincipalFrom(r.Context()); principal != nil {
+			w.Write([]byte(principal.Username))
+		}
+	}))
+
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/query", nil)
+		if header != "" {
+			request.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	assert.Equal(t, "ala", serve("Authorization", "Bearer "+token).Body.String())
+	assert.Equal(t, "ala", serve("Authorization", "Bearer "+key).Body.String())
+	assert.Equal(t, "ala", serve(APIKeyHeader, key).Body.String())
+	assert.Equal(t, "", serve("", "").Body.String())
+
+	rejected := serve("Authorization", "Bearer nonsense")
+	assert.Equal(t, http.StatusUnauthorized, rejected.Code)
+	assert.Contains(t, rejected.Body.String(), customErrors.ErrInvalidToken.Error())
+
+	required := RequirePrincipal(http.NotFoundHandler(), false)
+	recorder := httptest.NewRecorder()
+	required.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/export", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
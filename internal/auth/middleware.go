@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+)
+
+// APIKeyHeader is an alternative to sending an API key as a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// Middleware authenticates requests carrying an "Authorization: Bearer"
+// token or API key, or an X-API-Key header, and puts their principal into
+// the request context. Requests without credentials pass on anonymously,
+// requests with invalid ones are rejected with 401.
+func (service *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential := r.Header.Get(APIKeyHeader)
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			credential = strings.TrimSpace(bearer)
+		}
+		if credential == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := service.Authenticate(credential)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, customErrors.ErrInvalidToken) {
+				status = http.StatusUnauthorized
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			writeError(w, status, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// RequirePrincipal rejects anonymous requests with 401 unless allowAnonymous
+// is set. It expects to run behind Middleware.
+func RequirePrincipal(next http.Handler, allowAnonymous bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowAnonymous && PrincipalFrom(r.Context()) == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, customErrors.ErrUnauthenticated)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError answers like a GraphQL server, so clients read one error shape.
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]string{{"message": err.Error()}}})
+}
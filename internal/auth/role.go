@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"strings"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+)
+
+// Role tells what a user may do, named like the Role enum of the schema.
+// Every role may do what the roles before it may.
+type Role string
+
+const (
+	// RoleReader runs queries and studies.
+	RoleReader Role = "READER"
+	// RoleEditor also creates and changes entries.
+	RoleEditor Role = "EDITOR"
+	// RoleAdmin also deletes entries and manages users.
+	RoleAdmin Role = "ADMIN"
+)
+
+var roleRanks = map[Role]int{RoleReader: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// ParseRole accepts the name of a role in any case.
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToUpper(strings.TrimSpace(name)))
+	if _, ok := roleRanks[role]; !ok {
+		return "", customErrors.ErrUnknownRole
+	}
+	return role, nil
+}
+
+// Includes tells whether role may do what required may. Unknown roles may
+// do nothing.
+func (role Role) Includes(required Role) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// Tokens are JWTs signed with HS256, the only algorithm accepted.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type claims struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+
+	UserID uint `json:"-"`
+}
+
+// IssueToken returns a token of user valid for TokenTTL and its expiry.
+func (service *Service) IssueToken(user *dbModels.User) (string, time.Time, error) {
+	now := service.Now()
+	expiresAt := now.Add(service.TokenTTL).Truncate(time.Second)
+	payload, err := json.Marshal(claims{
+		Subject:   strconv.FormatUint(uint64(user.ID), 10),
+		Name:      user.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + service.sign(unsigned), expiresAt, nil
+}
+
+func (service *Service) verifyToken(token string) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, customErrors.ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(service.sign(parts[0]+"."+parts[1]))) {
+		return nil, customErrors.ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, customErrors.ErrInvalidToken
+	}
+	var decoded claims
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil, customErrors.ErrInvalidToken
+	}
+	if service.Now().Unix() >= decoded.ExpiresAt {
+		return nil, customErrors.ErrInvalidToken
+	}
+	userID, err := strconv.ParseUint(decoded.Subject, 10, 0)
+	if err != nil {
+		return nil, customErrors.ErrInvalidToken
+	}
+	decoded.UserID = uint(userID)
+	return &decoded, nil
+}
+
+func (service *Service) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, service.Secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
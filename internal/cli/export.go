@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/exporter"
+)
+
+const exportUsage = "usage: export [-format JSON|JSONL|CSV|ANKI] [-o FILE] " +
+	"[-direction PL_EN|EN_PL|BOTH] [-deck NAME] [-note-type NAME] [-ids ID,...]"
+
+// Export runs the export subcommand, writing every translation of store to
+// FILE or to out. The format defaults to the extension of FILE, then JSON.
+// The remaining flags apply to Anki exports.
+func Export(store database.DictionaryStore, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "", "JSON, JSONL, CSV or ANKI")
+	path := flags.String("o", "", "file to write instead of stdout")
+	direction := flags.String("direction", string(exporter.BothDirections), "side of the translations asked on Anki cards")
+	deck := flags.String("deck", exporter.DefaultAnkiDeck, "Anki deck to import the notes to")
+	noteType := flags.String("note-type", exporter.DefaultAnkiNoteType, "Anki note type with the fields Front and Back")
+	ids := flags.String("ids", "", "translations to export to Anki, all when empty")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errors.New(exportUsage)
+	}
+
+	exportFormat := exporter.FormatFromFilename(*path)
+	if *format != "" {
+		var err error
+		if exportFormat, err = exporter.ParseFormat(*format); err != nil {
+			return err
+		}
+	}
+	export := func(w io.Writer) (int, error) { return exporter.Export(store, w, exportFormat) }
+	if exportFormat == exporter.FormatAnki {
+		options := exporter.AnkiOptions{Deck: *deck, NoteType: *noteType}
+		var err error
+		if options.Direction, err = exporter.ParseAnkiDirection(*direction); err != nil {
+			return err
+		}
+		if options.TranslationIDs, err = exporter.ParseIDs(*ids); err != nil {
+			return err
+		}
+		export = func(w io.Writer) (int, error) { return exporter.ExportAnki(store, w, options) }
+	}
+	if *path == "" {
+		_, err := export(out)
+		return err
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	count, err := export(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d records to %s\n", count, *path)
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/importer"
+)
+
+const importUsage = "usage: import [-dry-run] [-batch-size N] [-format CSV|TSV] FILE"
+
+// Import runs the import subcommand, adding the translations of a CSV or TSV
+// file to store and printing a line for each row. FILE "-" reads stdin. When
+// a batch fails, the rows of the batches committed before it are printed.
+func Import(store database.DictionaryStore, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing")
+	batchSize := flags.Int("batch-size", 0, "commit every N rows, 0 imports the file in one transaction")
+	format := flags.String("format", "", "CSV or TSV, by default taken from the file name")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+
+	path := flags.Arg(0)
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	importFormat := importer.Format(strings.ToUpper(*format))
+	if *format == "" {
+		importFormat = importer.FormatFromFilename(path)
+	}
+
+	rows, err := importer.ReadRows(input, importFormat)
+	if err != nil {
+		return err
+	}
+	report, err := importer.Import(store, rows, importer.Options{DryRun: *dryRun, BatchSize: *batchSize})
+	if report == nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		fmt.Fprintf(out, "line %d: %s -> %s: %s", row.Line, row.PolishWord, row.EnglishWord, row.Status)
+		if row.Reason != "" {
+			fmt.Fprintf(out, " (%s)", row.Reason)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "%d created, %d updated, %d already existed, %d failed\n", report.Created, report.Updated, report.AlreadyExisted, report.Failed)
+	if report.DryRun {
+		fmt.Fprintln(out, "dry run, nothing was written")
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/importer"
+)
+
+const importKaikkiUsage = "usage: import-kaikki [-batch-size N] [-checkpoint FILE] [-restart] FILE"
+
+// ImportKaikki runs the import-kaikki subcommand, adding the entries of a
+// Kaikki.org Wiktionary JSONL extract to store. An interrupted import picks
+// up where it stopped when run again.
+func ImportKaikki(store database.DictionaryStore, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import-kaikki", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	batchSize := flags.Int("batch-size", importer.DefaultKaikkiBatchSize, "entries committed together")
+	checkpoint := flags.String("checkpoint", "", "file keeping the progress, FILE.progress by default")
+	restart := flags.Bool("restart", false, "start from the beginning of the file")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importKaikkiUsage)
+	}
+
+	report, err := importer.ImportKaikki(store, flags.Arg(0), importer.KaikkiOptions{
+		BatchSize:  *batchSize,
+		Checkpoint: *checkpoint,
+		Restart:    *restart,
+	})
+	if report != nil {
+		if report.ResumedAt > 0 {
+			fmt.Fprintf(out, "resumed after line %d\n", report.ResumedAt)
+		}
+		fmt.Fprintf(out, "%d lines read, %d entries imported, %d skipped, %d translations, %d forms\n",
+			report.Lines, report.Entries-report.Skipped, report.Skipped, report.Translations, report.Forms)
+	}
+	return err
+}
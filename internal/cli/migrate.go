@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/realagmag/dictionaryGO/internal/migrations"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up|down|status"
+
+// Migrate runs the migrate subcommand: "up" applies every pending migration,
+// "down" rolls back the latest one and "status" lists all of them.
+func Migrate(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	migrator := migrations.NewMigrator(db)
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled back %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d_%-40s %s\n", status.Migration.Version, status.Migration.Name, state)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/internal/auth"
+	"github.com/realagmag/dictionaryGO/internal/database"
+)
+
+const createUserUsage = "usage: create-user [-role READER|EDITOR|ADMIN] [-api-key NAME] USERNAME, with the password on stdin"
+
+// CreateUser runs the create-user subcommand, adding an account whose
+// password is the first line of in. The account is a reader unless -role
+// says otherwise. With -api-key it also prints a new API key of the account,
+// for scripts.
+func CreateUser(store database.DictionaryStore, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	keyName := flags.String("api-key", "", "also create an API key with this name")
+	roleName := flags.String("role", string(auth.RoleReader), "READER, EDITOR or ADMIN")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(createUserUsage)
+	}
+	role, err := auth.ParseRole(*roleName)
+	if err != nil {
+		return err
+	}
+
+	// Tokens are not issued here, so no secret is needed.
+	service := auth.NewService(store, nil)
+	password, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	user, err := service.CreateUser(flags.Arg(0), strings.TrimRight(password, "\r\n"), role)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "created %s %s\n", strings.ToLower(user.Role), user.Username)
+
+	if *keyName == "" {
+		return nil
+	}
+	key, _, err := service.CreateAPIKey(user.ID, *keyName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "api key %s: %s\n", *keyName, key)
+	return nil
+}
+
+// DemoAPIKey adds a "demo" admin with a random password to store, which is
+// expected to be empty, and returns an API key of it.
+func DemoAPIKey(store database.DictionaryStore) (string, error) {
+	service := auth.NewService(store, nil)
+	password, err := auth.RandomSecret()
+	if err != nil {
+		return "", err
+	}
+	user, err := service.CreateUser("demo", base64.RawURLEncoding.EncodeToString(password), auth.RoleAdmin)
+	if err != nil {
+		return "", err
+	}
+	key, _, err := service.CreateAPIKey(user.ID, "demo")
+	return key, err
+}
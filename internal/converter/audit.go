@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+func (c *Converter) AuditFilterFromArgs(entityType *model.AuditEntityType, entityID *int, since *time.Time) database.AuditFilter {
+	filter := database.AuditFilter{EntityType: database.EnumValue(entityType), Since: since}
+	if entityID != nil {
+		id := uint(*entityID)
+		filter.EntityID = &id
+	}
+	return filter
+}
+
+func (c *Converter) AuditEntryToGraphType(entry *dbModels.AuditEntry) *model.AuditEntry {
+	converted := &model.AuditEntry{
+		ID:         int(entry.ID),
+		OccurredAt: entry.OccurredAt,
+		Operation:  entry.Operation,
+		EntityType: model.AuditEntityType(entry.EntityType),
+		EntityID:   int(entry.EntityID),
+		Before:     entry.Before,
+		After:      entry.After,
+	}
+	if entry.Actor != "" {
+		converted.Actor = &entry.Actor
+	}
+	return converted
+}
+
+func (c *Converter) AuditEntryPageToConnection(page *database.Page[dbModels.AuditEntry]) *model.AuditEntryConnection {
+	edges := make([]*model.AuditEntryEdge, len(page.Items))
+	for i, entry := range page.Items {
+		edges[i] = &model.AuditEntryEdge{Cursor: EncodeCursor(entry.ID), Node: c.AuditEntryToGraphType(entry)}
+	}
+	return &model.AuditEntryConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, func(entry *dbModels.AuditEntry) uint { return entry.ID }),
+		TotalCount: int32(page.TotalCount),
+	}
+}
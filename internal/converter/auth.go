@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+func (c *Converter) UserToGraphType(user *dbModels.User) *model.User {
+	return &model.User{ID: int(user.ID), Username: user.Username, Role: model.Role(user.Role), CreatedAt: user.CreatedAt}
+}
+
+func (c *Converter) APIKeyToGraphType(key *dbModels.APIKey) *model.APIKey {
+	return &model.APIKey{ID: int(key.ID), Name: key.Name, Prefix: key.Prefix, CreatedAt: key.CreatedAt}
+}
+
+func (c *Converter) APIKeySliceToGraphType(keys []*dbModels.APIKey) []*model.APIKey {
+	result := make([]*model.APIKey, len(keys))
+	for i, key := range keys {
+		result[i] = c.APIKeyToGraphType(key)
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
 )
 
@@ -10,18 +11,86 @@ type Converter struct {
 
 func (c *Converter) PolishToGraphType(word *dbModels.PolishWord) *model.PolishWord {
 	return &model.PolishWord{
-		ID:   int(word.ID),
-		Text: word.Text,
+		ID:           int(word.ID),
+		Text:         word.Text,
+		PartOfSpeech: enumOrNil[model.PartOfSpeech](word.PartOfSpeech),
+		Gender:       enumOrNil[model.Gender](word.Gender),
+		Aspect:       enumOrNil[model.Aspect](word.Aspect),
+		Version:      int32(word.Version),
 	}
 }
 
 func (c *Converter) EnglishToGraphType(word *dbModels.EnglishWord) *model.EnglishWord {
 	return &model.EnglishWord{
-		ID:   int(word.ID),
-		Text: word.Text,
+		ID:           int(word.ID),
+		Text:         word.Text,
+		PartOfSpeech: enumOrNil[model.PartOfSpeech](word.PartOfSpeech),
+		Version:      int32(word.Version),
 	}
 }
 
+func (c *Converter) PolishWordFromArgs(word string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect) dbModels.PolishWord {
+	return dbModels.PolishWord{
+		Text:         word,
+		PartOfSpeech: database.EnumValue(partOfSpeech),
+		Gender:       database.EnumValue(gender),
+		Aspect:       database.EnumValue(aspect),
+	}
+}
+
+func (c *Converter) EnglishWordFromArgs(word string, partOfSpeech *model.PartOfSpeech) dbModels.EnglishWord {
+	return dbModels.EnglishWord{
+		Text:         word,
+		PartOfSpeech: database.EnumValue(partOfSpeech),
+	}
+}
+
+// VersionFromArgs returns the version an update expects, nil when unset. A
+// negative version matches no entry.
+func (c *Converter) VersionFromArgs(version *int32) *uint {
+	if version == nil {
+		return nil
+	}
+	if *version < 0 {
+		conflicting := uint(0)
+		return &conflicting
+	}
+	expected := uint(*version)
+	return &expected
+}
+
+func (c *Converter) WordFormToGraphType(form *dbModels.WordForm) *model.WordForm {
+	return &model.WordForm{
+		ID:           int(form.ID),
+		PolishWordID: int(form.PolishWordID),
+		Text:         form.Text,
+		Case:         enumOrNil[model.GrammaticalCase](form.GrammaticalCase),
+		Number:       enumOrNil[model.GrammaticalNumber](form.Number),
+		Person:       enumOrNil[model.Person](form.Person),
+	}
+}
+
+func (c *Converter) WordFormSliceToGraphType(forms []*dbModels.WordForm) []*model.WordForm {
+	convertedForms := make([]*model.WordForm, len(forms))
+	for i, form := range forms {
+		convertedForms[i] = c.WordFormToGraphType(form)
+	}
+	return convertedForms
+}
+
+func (c *Converter) WordFormsFromInput(forms []*model.WordFormInput) []dbModels.WordForm {
+	convertedForms := make([]dbModels.WordForm, len(forms))
+	for i, form := range forms {
+		convertedForms[i] = dbModels.WordForm{
+			Text:            form.Text,
+			GrammaticalCase: database.EnumValue(form.Case),
+			Number:          database.EnumValue(form.Number),
+			Person:          database.EnumValue(form.Person),
+		}
+	}
+	return convertedForms
+}
+
 func (c *Converter) PolishSliceToGraphType(words []*dbModels.PolishWord) []*model.PolishWord {
 	convertedWords := make([]*model.PolishWord, len(words))
 	for i, word := range words {
@@ -38,21 +107,31 @@ func (c *Converter) EnglishSliceToGraphType(words []*dbModels.EnglishWord) []*mo
 	return convertedWords
 }
 
+// TranslationToGraphType keeps only the keys of the associations, they are
+// loaded by the Translation field resolvers when a query selects them.
 func (c *Converter) TranslationToGraphType(translation *dbModels.Translation) *model.Translation {
 	return &model.Translation{
-		ID:          int(translation.ID),
-		PolishWord:  c.PolishToGraphType(&translation.PolishWord),
-		EnglishWord: c.EnglishToGraphType(&translation.EnglishWord),
-		Examples:    c.ExampleSliceToGraphType(&translation.Examples),
+		ID:            int(translation.ID),
+		PolishWordID:  int(translation.PolishWordID),
+		EnglishWordID: int(translation.EnglishWordID),
 	}
 }
 
+func (c *Converter) TranslationSliceToGraphType(translations []*dbModels.Translation) []*model.Translation {
+	convertedTranslations := make([]*model.Translation, len(translations))
+	for i, translation := range translations {
+		convertedTranslations[i] = c.TranslationToGraphType(translation)
+	}
+	return convertedTranslations
+}
+
 func (c *Converter) ExampleToGraphType(example *dbModels.Example) *model.Example {
 	return &model.Example{
 		ID:            int(example.ID),
 		Text:          example.Text,
 		InPolish:      example.InPolish,
 		TranslationID: int(example.TranslationID),
+		Version:       int32(example.Version),
 	}
 }
 
@@ -63,3 +142,59 @@ func (c *Converter) ExampleSliceToGraphType(examples *[]dbModels.Example) []*mod
 	}
 	return convertedExamples
 }
+
+func (c *Converter) LookupOptionsFromArgs(caseSensitive bool, foldDiacritics bool, includeForms bool, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect) database.LookupOptions {
+	return database.LookupOptions{
+		IgnoreCase:     !caseSensitive,
+		FoldDiacritics: foldDiacritics,
+		IncludeForms:   includeForms,
+		PartOfSpeech:   database.EnumValue(partOfSpeech),
+		Gender:         database.EnumValue(gender),
+		Aspect:         database.EnumValue(aspect),
+	}
+}
+
+func (c *Converter) LimitFromArg(limit *int32) int {
+	if limit == nil {
+		return database.DefaultLimit
+	}
+	return int(*limit)
+}
+
+func (c *Converter) SuggestionSliceToGraphType(suggestions []*database.Suggestion, language model.Language) []*model.Suggestion {
+	convertedSuggestions := make([]*model.Suggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		convertedSuggestions[i] = &model.Suggestion{
+			ID:       int(suggestion.ID),
+			Text:     suggestion.Text,
+			Language: language,
+			Distance: int32(suggestion.Distance),
+			Score:    suggestion.Score,
+		}
+	}
+	return convertedSuggestions
+}
+
+func (c *Converter) WordEntrySliceToGraphType(entries []*database.WordEntry, language model.Language) []*model.WordEntry {
+	convertedEntries := make([]*model.WordEntry, len(entries))
+	for i, entry := range entries {
+		convertedEntries[i] = &model.WordEntry{
+			ID:               int(entry.ID),
+			Text:             entry.Text,
+			Language:         language,
+			TranslationCount: int32(entry.TranslationCount),
+			LookupCount:      int32(entry.LookupCount),
+		}
+	}
+	return convertedEntries
+}
+
+// enumOrNil turns a stored grammatical value into a GraphQL enum, unspecified
+// values become null.
+func enumOrNil[T ~string](value string) *T {
+	if value == "" {
+		return nil
+	}
+	enum := T(value)
+	return &enum
+}
@@ -3,6 +3,7 @@ package converter
 import (
 	"testing"
 
+	"github.com/realagmag/dictionaryGO/graph/model"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -76,28 +77,40 @@ func TestTranslationToGraphType(t *testing.T) {
 	converter := Converter{}
 
 	translation := &dbModels.Translation{
-		ID: 1,
+		ID:            1,
+		PolishWordID:  2,
+		EnglishWordID: 3,
 		PolishWord: dbModels.PolishWord{
-			ID:   1,
+			ID:   2,
 			Text: "kot",
 		},
 		EnglishWord: dbModels.EnglishWord{
-			ID:   1,
+			ID:   3,
 			Text: "cat",
 		},
-		Examples: []dbModels.Example{
-			{ID: 1, Text: "Kot jest zwierzęciem.", InPolish: true, TranslationID: 1},
-		},
 	}
 
 	result := converter.TranslationToGraphType(translation)
 
 	assert.NotNil(t, result)
 	assert.Equal(t, 1, result.ID)
-	assert.Equal(t, "kot", result.PolishWord.Text)
-	assert.Equal(t, "cat", result.EnglishWord.Text)
-	assert.Len(t, result.Examples, 1)
-	assert.Equal(t, "Kot jest zwierzęciem.", result.Examples[0].Text)
+	assert.Equal(t, 2, result.PolishWordID)
+	assert.Equal(t, 3, result.EnglishWordID)
+}
+
+func TestTranslationSliceToGraphType(t *testing.T) {
+	converter := Converter{}
+
+	translations := []*dbModels.Translation{
+		{ID: 1, PolishWordID: 1, EnglishWordID: 1},
+		{ID: 2, PolishWordID: 1, EnglishWordID: 2},
+	}
+
+	result := converter.TranslationSliceToGraphType(translations)
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, 2, result[1].ID)
+	assert.Equal(t, 2, result[1].EnglishWordID)
 }
 
 func TestExampleToGraphType(t *testing.T) {
@@ -136,3 +149,14 @@ func TestExampleSliceToGraphType(t *testing.T) {
 	assert.Equal(t, 2, result[1].ID)
 	assert.Equal(t, "The cat is an animal.", result[1].Text)
 }
+
+func TestPolishToGraphTypeWithGrammar(t *testing.T) {
+	converter := &Converter{}
+	polishWord := &dbModels.PolishWord{ID: 3, Text: "kobieta", PartOfSpeech: "NOUN", Gender: "FEMININE"}
+
+	result := converter.PolishToGraphType(polishWord)
+
+	assert.Equal(t, model.PartOfSpeechNoun, *result.PartOfSpeech)
+	assert.Equal(t, model.GenderFeminine, *result.Gender)
+	assert.Nil(t, result.Aspect)
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+func (c *Converter) PolishWordRevisionToGraphType(revision *dbModels.PolishWordRevision) *model.PolishWordRevision {
+	return &model.PolishWordRevision{
+		Version:      int32(revision.Version),
+		Text:         revision.Text,
+		PartOfSpeech: enumOrNil[model.PartOfSpeech](revision.PartOfSpeech),
+		Gender:       enumOrNil[model.Gender](revision.Gender),
+		Aspect:       enumOrNil[model.Aspect](revision.Aspect),
+		Deleted:      revision.Deleted,
+		Actor:        actorOrNil(revision.Actor),
+		RecordedAt:   revision.RecordedAt,
+	}
+}
+
+func (c *Converter) EnglishWordRevisionToGraphType(revision *dbModels.EnglishWordRevision) *model.EnglishWordRevision {
+	return &model.EnglishWordRevision{
+		Version:      int32(revision.Version),
+		Text:         revision.Text,
+		PartOfSpeech: enumOrNil[model.PartOfSpeech](revision.PartOfSpeech),
+		Deleted:      revision.Deleted,
+		Actor:        actorOrNil(revision.Actor),
+		RecordedAt:   revision.RecordedAt,
+	}
+}
+
+func (c *Converter) TranslationRevisionToGraphType(revision *dbModels.TranslationRevision) *model.TranslationRevision {
+	return &model.TranslationRevision{
+		Version:       int32(revision.Version),
+		PolishWordID:  int(revision.PolishWordID),
+		EnglishWordID: int(revision.EnglishWordID),
+		Deleted:       revision.Deleted,
+		Actor:         actorOrNil(revision.Actor),
+		RecordedAt:    revision.RecordedAt,
+	}
+}
+
+func (c *Converter) ExampleRevisionToGraphType(revision *dbModels.ExampleRevision) *model.ExampleRevision {
+	return &model.ExampleRevision{
+		Version:       int32(revision.Version),
+		TranslationID: int(revision.TranslationID),
+		Text:          revision.Text,
+		InPolish:      revision.InPolish,
+		Deleted:       revision.Deleted,
+		Actor:         actorOrNil(revision.Actor),
+		RecordedAt:    revision.RecordedAt,
+	}
+}
+
+// RevisionSliceToGraphType converts revisions with one of the converters
+// above, e.g. c.PolishWordRevisionToGraphType.
+func RevisionSliceToGraphType[R any, G model.Revision](revisions []*R, convert func(*R) G) []G {
+	converted := make([]G, len(revisions))
+	for i, revision := range revisions {
+		converted[i] = convert(revision)
+	}
+	return converted
+}
+
+// RevisionUnion returns revisions as members of the Revision union.
+func RevisionUnion[G model.Revision](revisions []G) []model.Revision {
+	union := make([]model.Revision, len(revisions))
+	for i, revision := range revisions {
+		union[i] = revision
+	}
+	return union
+}
+
+// RestoredToGraphType converts an entity returned by history.Restore or
+// RestoreFromTrash.
+func (c *Converter) RestoredToGraphType(restored interface{}) (model.RevisionedEntity, error) {
+	switch restored := restored.(type) {
+	case *dbModels.PolishWord:
+		return c.PolishToGraphType(restored), nil
+	case *dbModels.EnglishWord:
+		return c.EnglishToGraphType(restored), nil
+	case *dbModels.Translation:
+		return c.TranslationToGraphType(restored), nil
+	case *dbModels.Example:
+		return c.ExampleToGraphType(restored), nil
+	default:
+		return nil, fmt.Errorf("converter: unsupported entity %T", restored)
+	}
+}
+
+func actorOrNil(actor string) *string {
+	if actor == "" {
+		return nil
+	}
+	return &actor
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/importer"
+)
+
+func (c *Converter) ImportOptionsFromArgs(dryRun bool, batchSize *int32) importer.Options {
+	options := importer.Options{DryRun: dryRun}
+	if batchSize != nil {
+		options.BatchSize = int(*batchSize)
+	}
+	return options
+}
+
+// ImportFormatFromArgs returns format, or the one of the file name if unset.
+func (c *Converter) ImportFormatFromArgs(format *model.ImportFormat, filename string) importer.Format {
+	if format == nil {
+		return importer.FormatFromFilename(filename)
+	}
+	return importer.Format(*format)
+}
+
+func (c *Converter) ImportReportToGraphType(report *importer.Report) *model.ImportReport {
+	rows := make([]*model.ImportRowResult, len(report.Rows))
+	for i, row := range report.Rows {
+		rows[i] = &model.ImportRowResult{
+			Line:        int32(row.Line),
+			PolishWord:  row.PolishWord,
+			EnglishWord: row.EnglishWord,
+			Status:      model.ImportRowStatus(row.Status),
+		}
+		if row.TranslationID != 0 {
+			id := int(row.TranslationID)
+			rows[i].TranslationID = &id
+		}
+		if row.Reason != "" {
+			reason := row.Reason
+			rows[i].Reason = &reason
+		}
+	}
+	return &model.ImportReport{
+		DryRun:         report.DryRun,
+		Created:        int32(report.Created),
+		Updated:        int32(report.Updated),
+		AlreadyExisted: int32(report.AlreadyExisted),
+		Failed:         int32(report.Failed),
+		Rows:           rows,
+	}
+}
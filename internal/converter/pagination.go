@@ -0,0 +1,133 @@
+package converter
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+const cursorPrefix = "cursor:"
+
+// EncodeCursor turns a record ID into the opaque cursor handed to clients.
+func EncodeCursor(id uint) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+func DecodeCursor(cursor string) (uint, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, customErrors.ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 0)
+	if err != nil {
+		return 0, customErrors.ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+func (c *Converter) PageRequestFromArgs(first *int32, after *string, last *int32, before *string) (database.PageRequest, error) {
+	var page database.PageRequest
+	if first != nil {
+		size := int(*first)
+		page.First = &size
+	}
+	if last != nil {
+		size := int(*last)
+		page.Last = &size
+	}
+	if after != nil {
+		id, err := DecodeCursor(*after)
+		if err != nil {
+			return page, err
+		}
+		page.After = &id
+	}
+	if before != nil {
+		id, err := DecodeCursor(*before)
+		if err != nil {
+			return page, err
+		}
+		page.Before = &id
+	}
+	return page, nil
+}
+
+func (c *Converter) PolishWordPageToConnection(page *database.Page[dbModels.PolishWord]) *model.PolishWordConnection {
+	edges := make([]*model.PolishWordEdge, len(page.Items))
+	for i, word := range page.Items {
+		edges[i] = &model.PolishWordEdge{Cursor: EncodeCursor(word.ID), Node: c.PolishToGraphType(word)}
+	}
+	return &model.PolishWordConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, func(word *dbModels.PolishWord) uint { return word.ID }),
+		TotalCount: int32(page.TotalCount),
+	}
+}
+
+func (c *Converter) EnglishWordPageToConnection(page *database.Page[dbModels.EnglishWord]) *model.EnglishWordConnection {
+	edges := make([]*model.EnglishWordEdge, len(page.Items))
+	for i, word := range page.Items {
+		edges[i] = &model.EnglishWordEdge{Cursor: EncodeCursor(word.ID), Node: c.EnglishToGraphType(word)}
+	}
+	return &model.EnglishWordConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, func(word *dbModels.EnglishWord) uint { return word.ID }),
+		TotalCount: int32(page.TotalCount),
+	}
+}
+
+func (c *Converter) TranslationPageToConnection(page *database.Page[dbModels.Translation]) *model.TranslationConnection {
+	edges := make([]*model.TranslationEdge, len(page.Items))
+	for i, translation := range page.Items {
+		edges[i] = &model.TranslationEdge{Cursor: EncodeCursor(translation.ID), Node: c.TranslationToGraphType(translation)}
+	}
+	return &model.TranslationConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, func(translation *dbModels.Translation) uint { return translation.ID }),
+		TotalCount: int32(page.TotalCount),
+	}
+}
+
+func (c *Converter) ExampleMatchPageToConnection(page *database.Page[database.ExampleMatch]) *model.ExampleSearchConnection {
+	edges := make([]*model.ExampleSearchEdge, len(page.Items))
+	for i, match := range page.Items {
+		edges[i] = &model.ExampleSearchEdge{
+			Cursor: EncodeCursor(match.ID),
+			Node: &model.ExampleSearchResult{
+				Example: c.ExampleToGraphType(&dbModels.Example{
+					ID:            match.ID,
+					TranslationID: match.TranslationID,
+					Text:          match.Text,
+					InPolish:      match.InPolish,
+					Version:       match.Version,
+				}),
+				Snippet: match.Snippet,
+				Rank:    match.Rank,
+			},
+		}
+	}
+	return &model.ExampleSearchConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, func(match *database.ExampleMatch) uint { return match.ID }),
+		TotalCount: int32(page.TotalCount),
+	}
+}
+
+func pageInfo[T any](page *database.Page[T], id func(*T) uint) *model.PageInfo {
+	info := &model.PageInfo{
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: page.HasPreviousPage,
+	}
+	if len(page.Items) > 0 {
+		start := EncodeCursor(id(page.Items[0]))
+		end := EncodeCursor(id(page.Items[len(page.Items)-1]))
+		info.StartCursor = &start
+		info.EndCursor = &end
+	}
+	return info
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id, err := DecodeCursor(EncodeCursor(42))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, cursor := range []string{"", "not base64!", "b3RoZXI6NDI=", "Y3Vyc29yOmFiYw=="} {
+		_, err := DecodeCursor(cursor)
+		assert.Equal(t, customErrors.ErrInvalidCursor, err, cursor)
+	}
+}
+
+func TestPageRequestFromArgs(t *testing.T) {
+	converter := Converter{}
+	first := int32(10)
+	after := EncodeCursor(7)
+
+	page, err := converter.PageRequestFromArgs(&first, &after, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, *page.First)
+	assert.Equal(t, uint(7), *page.After)
+	assert.Nil(t, page.Last)
+	assert.Nil(t, page.Before)
+}
+
+func TestPolishWordPageToConnection(t *testing.T) {
+	converter := Converter{}
+	page := &database.Page[dbModels.PolishWord]{
+		Items:       []*dbModels.PolishWord{{ID: 3, Text: "kot"}, {ID: 5, Text: "pies"}},
+		HasNextPage: true,
+		TotalCount:  9,
+	}
+
+	result := converter.PolishWordPageToConnection(page)
+
+	assert.Len(t, result.Edges, 2)
+	assert.Equal(t, "pies", result.Edges[1].Node.Text)
+	assert.Equal(t, EncodeCursor(3), *result.PageInfo.StartCursor)
+	assert.Equal(t, EncodeCursor(5), *result.PageInfo.EndCursor)
+	assert.True(t, result.PageInfo.HasNextPage)
+	assert.Equal(t, int32(9), result.TotalCount)
+}
+
+func TestEmptyPageHasNoCursors(t *testing.T) {
+	converter := Converter{}
+
+	result := converter.EnglishWordPageToConnection(&database.Page[dbModels.EnglishWord]{})
+
+	assert.Empty(t, result.Edges)
+	assert.Nil(t, result.PageInfo.StartCursor)
+	assert.Nil(t, result.PageInfo.EndCursor)
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"math/rand/v2"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/quiz"
+)
+
+// QuizOptionsFromArgs fills in the defaults of the generateQuiz arguments,
+// a missing seed is chosen at random.
+func (c *Converter) QuizOptionsFromArgs(size *int32, direction model.StudyDirection, tag *string, choices *int32, seed *int32) quiz.Options {
+	options := quiz.Options{Size: quiz.DefaultSize, Choices: quiz.DefaultChoices, Direction: string(direction)}
+	if size != nil {
+		options.Size = int(*size)
+	}
+	if choices != nil {
+		options.Choices = int(*choices)
+	}
+	if tag != nil {
+		options.Tag = *tag
+	}
+	if seed != nil {
+		options.Seed = uint64(*seed)
+	} else {
+		options.Seed = uint64(rand.Int32())
+	}
+	return options
+}
+
+func (c *Converter) QuizToGraphType(options quiz.Options, questions []*quiz.Question) *model.Quiz {
+	result := &model.Quiz{Seed: int32(options.Seed), Questions: make([]*model.QuizQuestion, len(questions))}
+	for i, question := range questions {
+		result.Questions[i] = &model.QuizQuestion{
+			TranslationID: int(question.TranslationID),
+			Direction:     model.StudyDirection(question.Direction),
+			Prompt:        question.Prompt,
+			Choices:       question.Choices,
+			Answer:        question.Answer,
+		}
+		if question.Cloze != "" {
+			result.Questions[i].Cloze = &question.Cloze
+		}
+	}
+	return result
+}
+
+func (c *Converter) QuizAnswersFromInput(answers []*model.QuizAnswerInput) []quiz.Answer {
+	result := make([]quiz.Answer, len(answers))
+	for i, answer := range answers {
+		result[i] = quiz.Answer{
+			TranslationID: uint(answer.TranslationID),
+			Direction:     string(answer.Direction),
+			Answer:        answer.Answer,
+		}
+	}
+	return result
+}
+
+func (c *Converter) QuizResultToGraphType(result *quiz.Result) *model.QuizResult {
+	answers := make([]*model.QuizAnswerResult, len(result.Answers))
+	for i, answer := range result.Answers {
+		answers[i] = &model.QuizAnswerResult{
+			TranslationID: int(answer.TranslationID),
+			Direction:     model.StudyDirection(answer.Direction),
+			Answer:        answer.Answer.Answer,
+			Correct:       answer.Correct,
+			CorrectAnswer: answer.CorrectAnswer,
+		}
+	}
+	return &model.QuizResult{
+		Correct: int32(result.Correct),
+		Total:   int32(len(result.Answers)),
+		Score:   result.Score(),
+		Answers: answers,
+	}
+}
+
+func (c *Converter) TagNames(tags []*dbModels.TranslationTag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// StudyDirectionsFromArg returns direction, or all directions if unset.
+func (c *Converter) StudyDirectionsFromArg(direction *model.StudyDirection) []string {
+	if direction == nil {
+		directions := make([]string, len(model.AllStudyDirection))
+		for i, direction := range model.AllStudyDirection {
+			directions[i] = string(direction)
+		}
+		return directions
+	}
+	return []string{string(*direction)}
+}
+
+func (c *Converter) CardToGraphType(card *dbModels.ReviewCard) *model.Card {
+	return &model.Card{
+		TranslationID:  int(card.TranslationID),
+		Translation:    c.TranslationToGraphType(&card.Translation),
+		Direction:      model.StudyDirection(card.Direction),
+		DueAt:          card.DueAt,
+		IntervalDays:   int32(card.IntervalDays),
+		Repetitions:    int32(card.Repetitions),
+		Lapses:         int32(card.Lapses),
+		EaseFactor:     card.EaseFactor,
+		LastReviewedAt: card.LastReviewedAt,
+		New:            card.ID == 0,
+	}
+}
+
+func (c *Converter) CardSliceToGraphType(cards []*dbModels.ReviewCard) []*model.Card {
+	result := make([]*model.Card, len(cards))
+	for i, card := range cards {
+		result[i] = c.CardToGraphType(card)
+	}
+	return result
+}
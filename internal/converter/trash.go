@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"sort"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+)
+
+// TrashToGraphType lists the entries of trash, the most recently deleted
+// first. Entries deleted together keep words before translations before
+// examples.
+func (c *Converter) TrashToGraphType(trash *database.Trash) []*model.TrashItem {
+	items := []*model.TrashItem{}
+	for _, word := range trash.PolishWords {
+		items = append(items, &model.TrashItem{
+			Entity:    model.RevisionEntityPolishWord,
+			ID:        int(word.ID),
+			Text:      word.Text,
+			DeletedAt: word.DeletedAt.Time,
+		})
+	}
+	for _, word := range trash.EnglishWords {
+		items = append(items, &model.TrashItem{
+			Entity:    model.RevisionEntityEnglishWord,
+			ID:        int(word.ID),
+			Text:      word.Text,
+			DeletedAt: word.DeletedAt.Time,
+		})
+	}
+	for _, translation := range trash.Translations {
+		items = append(items, &model.TrashItem{
+			Entity:    model.RevisionEntityTranslation,
+			ID:        int(translation.ID),
+			Text:      translation.PolishWord.Text + " - " + translation.EnglishWord.Text,
+			DeletedAt: translation.DeletedAt.Time,
+		})
+	}
+	for _, example := range trash.Examples {
+		items = append(items, &model.TrashItem{
+			Entity:    model.RevisionEntityExample,
+			ID:        int(example.ID),
+			Text:      example.Text,
+			DeletedAt: example.DeletedAt.Time,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"time"
+
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// AuditFilter selects audit entries. Empty fields match every entry.
+type AuditFilter struct {
+	EntityType string
+	EntityID   *uint
+	Since      *time.Time
+}
+
+func (manager *DBManager) AddAuditEntry(entry dbModels.AuditEntry) (*dbModels.AuditEntry, error) {
+	entry.ID = 0
+	if err := manager.db.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (manager *DBManager) GetAuditLogPage(filter AuditFilter, page PageRequest) (*Page[dbModels.AuditEntry], error) {
+	query := manager.db.Model(&dbModels.AuditEntry{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Since != nil {
+		query = query.Where("occurred_at >= ?", *filter.Since)
+	}
+	return paginate[dbModels.AuditEntry](query, page)
+}
+
+// Matches tells whether entry is selected by filter, for stores filtering in
+// memory.
+func (filter AuditFilter) Matches(entry *dbModels.AuditEntry) bool {
+	return (filter.EntityType == "" || entry.EntityType == filter.EntityType) &&
+		(filter.EntityID == nil || entry.EntityID == *filter.EntityID) &&
+		(filter.Since == nil || !entry.OccurredAt.Before(*filter.Since))
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// WordEntry is a word together with the figures autocomplete ranks it by.
+type WordEntry struct {
+	ID               uint
+	Text             string
+	FoldedKey        string
+	TranslationCount int64
+	LookupCount      int64
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// wherePrefix keeps the words whose folded key starts with key. Postgres
+// matches it with LIKE on the text_pattern_ops index, other databases compare
+// bytes and use a range scan on the plain index instead.
+func wherePrefix(query *gorm.DB, key string) *gorm.DB {
+	if key == "" {
+		return query
+	}
+	if query.Dialector.Name() == "postgres" {
+		return query.Where("folded_key LIKE ?", likeEscaper.Replace(key)+"%")
+	}
+	return query.Where("folded_key >= ? AND folded_key < ?", key, key+string(utf8.MaxRune))
+}
+
+func autocompleteOrder(ranking model.AutocompleteRanking) string {
+	switch ranking {
+	case model.AutocompleteRankingTranslationCount:
+		return "translation_count DESC, folded_key, text"
+	case model.AutocompleteRankingPopularity:
+		return "lookup_count DESC, folded_key, text"
+	default:
+		return "folded_key, text"
+	}
+}
+
+// SortWordEntries orders entries the way autocomplete does in the database.
+func SortWordEntries(entries []*WordEntry, ranking model.AutocompleteRanking) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case ranking == model.AutocompleteRankingTranslationCount && a.TranslationCount != b.TranslationCount:
+			return a.TranslationCount > b.TranslationCount
+		case ranking == model.AutocompleteRankingPopularity && a.LookupCount != b.LookupCount:
+			return a.LookupCount > b.LookupCount
+		case a.FoldedKey != b.FoldedKey:
+			return a.FoldedKey < b.FoldedKey
+		default:
+			return a.Text < b.Text
+		}
+	})
+}
+
+// HasPrefix reports whether text matches prefix the way autocomplete does.
+func HasPrefix(text, prefix string) bool {
+	return strings.HasPrefix(normalize.FoldedKey(text), normalize.FoldedKey(prefix))
+}
@@ -0,0 +1,51 @@
+package database
+
+import "strings"
+
+// constraint identifies a database constraint in driver error messages.
+// PostgreSQL reports violations by constraint name while SQLite lists the
+// constrained columns, so both spellings are kept.
+type constraint struct {
+	postgres string
+	sqlite   string
+}
+
+var (
+	polishWordTextUnique = constraint{
+		"idx_polish_words_text_part_of_speech",
+		"UNIQUE constraint failed: polish_words.text, polish_words.part_of_speech",
+	}
+	englishWordTextUnique = constraint{
+		"idx_english_words_text_part_of_speech",
+		"UNIQUE constraint failed: english_words.text, english_words.part_of_speech",
+	}
+	usernameUnique         = constraint{"idx_users_username", "UNIQUE constraint failed: users.username"}
+	exampleTextUnique      = constraint{"idx_translation_text", "UNIQUE constraint failed: examples.translation_id, examples.text"}
+	translationWordsUnique = constraint{
+		"idx_polish_english",
+		"UNIQUE constraint failed: translations.polish_word_id, translations.english_word_id",
+	}
+	polishWordRevisionUnique = constraint{
+		"idx_polish_word_revisions_version",
+		"UNIQUE constraint failed: polish_word_revisions.polish_word_id, polish_word_revisions.version",
+	}
+	englishWordRevisionUnique = constraint{
+		"idx_english_word_revisions_version",
+		"UNIQUE constraint failed: english_word_revisions.english_word_id, english_word_revisions.version",
+	}
+	translationRevisionUnique = constraint{
+		"idx_translation_revisions_version",
+		"UNIQUE constraint failed: translation_revisions.translation_id, translation_revisions.version",
+	}
+	exampleRevisionUnique = constraint{
+		"idx_example_revisions_version",
+		"UNIQUE constraint failed: example_revisions.example_id, example_revisions.version",
+	}
+	// SQLite does not name the foreign key that failed, examples only have one.
+	exampleTranslationForeignKey = constraint{"fk_translations_examples", "FOREIGN KEY constraint failed"}
+)
+
+func (c constraint) violatedBy(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, c.postgres) || strings.Contains(message, c.sqlite)
+}
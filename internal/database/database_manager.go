@@ -2,39 +2,54 @@ package database
 
 import (
 	"errors"
-	"strings"
 
 	"github.com/realagmag/dictionaryGO/graph/model"
 	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DBManager struct {
-	db *gorm.DB
+	db      *gorm.DB
+	lookups *lookupCounter
 }
 
 func NewDBManager(db *gorm.DB) *DBManager {
-	return &DBManager{db: db}
+	return &DBManager{db: db, lookups: newLookupCounter(db)}
 }
 
-func (manager *DBManager) AddPolishWord(word string) (*dbModels.PolishWord, error) {
+// FlushLookups writes the lookups counted since the last write.
+func (manager *DBManager) FlushLookups() error {
+	return manager.lookups.flush()
+}
+
+func (manager *DBManager) AddPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	if err := ValidateGrammar(word); err != nil {
+		return nil, err
+	}
 	var polishWord dbModels.PolishWord
 
 	err := manager.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Raw(`SELECT * FROM polish_words WHERE text = ? FOR UPDATE`, word).Scan(&polishWord).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("text = ? AND part_of_speech = ?", word.Text, word.PartOfSpeech).
+			Find(&polishWord).Error; err != nil {
 			return err
 		}
-		if polishWord.ID == 0 {
-			polishWord = dbModels.PolishWord{Text: word}
-			if err := tx.Create(&polishWord).Error; err != nil {
-				return err
-			}
+		if polishWord.ID != 0 {
+			return MatchGrammar(polishWord, word)
 		}
-		return nil
+		polishWord = dbModels.PolishWord{
+			Text:         word.Text,
+			PartOfSpeech: word.PartOfSpeech,
+			Gender:       word.Gender,
+			Aspect:       word.Aspect,
+		}
+		return tx.Create(&polishWord).Error
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "uni_polish_words_text") {
+		if polishWordTextUnique.violatedBy(err) {
 			return nil, customErrors.ErrPolishWordAlreadyExists
 		}
 		return nil, err
@@ -42,15 +57,17 @@ func (manager *DBManager) AddPolishWord(word string) (*dbModels.PolishWord, erro
 	return &polishWord, nil
 }
 
-func (manager *DBManager) AddEnglishWord(word string) (*dbModels.EnglishWord, error) {
+func (manager *DBManager) AddEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
 	var englishWord dbModels.EnglishWord
 
 	err := manager.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Raw(`SELECT * FROM english_words WHERE text = ? FOR UPDATE`, word).Scan(&englishWord).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("text = ? AND part_of_speech = ?", word.Text, word.PartOfSpeech).
+			Find(&englishWord).Error; err != nil {
 			return err
 		}
 		if englishWord.ID == 0 {
-			englishWord = dbModels.EnglishWord{Text: word}
+			englishWord = dbModels.EnglishWord{Text: word.Text, PartOfSpeech: word.PartOfSpeech}
 			if err := tx.Create(&englishWord).Error; err != nil {
 				return err
 			}
@@ -58,7 +75,7 @@ func (manager *DBManager) AddEnglishWord(word string) (*dbModels.EnglishWord, er
 		return nil
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "uni_english_words_text") {
+		if englishWordTextUnique.violatedBy(err) {
 			return nil, customErrors.ErrEnglishWordAlreadyExists
 		}
 		return nil, err
@@ -82,14 +99,53 @@ func (manager *DBManager) GetEnglishWords() ([]*dbModels.EnglishWord, error) {
 	return words, nil
 }
 
+func (manager *DBManager) GetPolishWordsPage(page PageRequest) (*Page[dbModels.PolishWord], error) {
+	return paginate[dbModels.PolishWord](manager.db, page)
+}
+
+func (manager *DBManager) GetEnglishWordsPage(page PageRequest) (*Page[dbModels.EnglishWord], error) {
+	return paginate[dbModels.EnglishWord](manager.db, page)
+}
+
+func (manager *DBManager) SetPolishWordForms(polishWordID uint, forms []dbModels.WordForm, replace bool) ([]*dbModels.WordForm, error) {
+	var stored []*dbModels.WordForm
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		var polishWord dbModels.PolishWord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&polishWord, polishWordID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return customErrors.ErrPolishWordNotFound
+			}
+			return err
+		}
+		if replace {
+			if err := tx.Where("polish_word_id = ?", polishWordID).Delete(&dbModels.WordForm{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, form := range forms {
+			form.ID, form.PolishWordID = 0, polishWordID
+			err := tx.Where("polish_word_id = ? AND text = ? AND grammatical_case = ? AND number = ? AND person = ?",
+				polishWordID, form.Text, form.GrammaticalCase, form.Number, form.Person).
+				FirstOrCreate(&form).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Where("polish_word_id = ?", polishWordID).Order("id").Find(&stored).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
 func (manager *DBManager) AddTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
-	polishWord := translationInput.PolishWord
-	englishWord := translationInput.EnglishWord
+	polishWord, englishWord := TranslationWords(translationInput)
 	examples := translationInput.Examples
 	var translation dbModels.Translation
 	err := manager.db.Transaction(func(tx *gorm.DB) error {
 		for attempt := 0; attempt < 3; attempt++ {
-			txManager := &DBManager{db: tx}
+			txManager := &DBManager{db: tx, lookups: manager.lookups}
 
 			polishWordModel, err := txManager.AddPolishWord(polishWord)
 			if err != nil {
@@ -132,7 +188,67 @@ func (manager *DBManager) AddTranslation(translationInput model.TranslationInput
 	return &translation, err
 }
 
+func (manager *DBManager) FindPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	var polishWord dbModels.PolishWord
+	// Find instead of First, a missing word is expected and not logged.
+	if err := manager.db.Where("text = ? AND part_of_speech = ?", word.Text, word.PartOfSpeech).
+		Limit(1).Find(&polishWord).Error; err != nil {
+		return nil, err
+	}
+	if polishWord.ID == 0 {
+		return nil, customErrors.ErrPolishWordNotFound
+	}
+	return &polishWord, nil
+}
+
+func (manager *DBManager) FindEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
+	var englishWord dbModels.EnglishWord
+	if err := manager.db.Where("text = ? AND part_of_speech = ?", word.Text, word.PartOfSpeech).
+		Limit(1).Find(&englishWord).Error; err != nil {
+		return nil, err
+	}
+	if englishWord.ID == 0 {
+		return nil, customErrors.ErrEnglishWordNotFound
+	}
+	return &englishWord, nil
+}
+
+func (manager *DBManager) FindExample(translationID uint, text string) (*dbModels.Example, error) {
+	var example dbModels.Example
+	if err := manager.db.Where("translation_id = ? AND text = ?", translationID, text).
+		Limit(1).Find(&example).Error; err != nil {
+		return nil, err
+	}
+	if example.ID == 0 {
+		return nil, customErrors.ErrExampleNotFound
+	}
+	return &example, nil
+}
+
+func (manager *DBManager) FindTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
+	polishWord, englishWord := TranslationWords(translationInput)
+	var translation dbModels.Translation
+	// Find instead of First, a missing translation is expected and not logged.
+	err := manager.db.
+		Joins("JOIN polish_words ON polish_words.id = translations.polish_word_id").
+		Joins("JOIN english_words ON english_words.id = translations.english_word_id").
+		Where("polish_words.text = ? AND polish_words.part_of_speech = ?", polishWord.Text, polishWord.PartOfSpeech).
+		Where("english_words.text = ? AND english_words.part_of_speech = ?", englishWord.Text, englishWord.PartOfSpeech).
+		Limit(1).Find(&translation).Error
+	if err != nil {
+		return nil, err
+	}
+	if translation.ID == 0 {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+	return &translation, nil
+}
+
 func (manager *DBManager) AddExampleToTranslation(example *model.ExampleInput, translationID uint) (*dbModels.Example, error) {
+	// A translation in the trash still satisfies the foreign key
+	if _, err := manager.GetTranslationById(translationID); err != nil {
+		return nil, err
+	}
 	var dbExample dbModels.Example
 	err := manager.db.Where("translation_id = ? AND text = ?", translationID, example.Text).
 		FirstOrCreate(&dbExample, dbModels.Example{
@@ -142,7 +258,7 @@ func (manager *DBManager) AddExampleToTranslation(example *model.ExampleInput, t
 		}).Error
 
 	if err != nil {
-		if strings.Contains(err.Error(), "fk_translations_examples") {
+		if exampleTranslationForeignKey.violatedBy(err) {
 			return nil, customErrors.ErrTranslationNotFound
 		}
 		return nil, err
@@ -151,7 +267,52 @@ func (manager *DBManager) AddExampleToTranslation(example *model.ExampleInput, t
 }
 
 func (manager *DBManager) PopulateTranslationWithAssociations(translation *dbModels.Translation) error {
-	return manager.db.Preload("PolishWord").Preload("EnglishWord").Preload("Examples").First(translation).Error
+	err := manager.db.Preload("PolishWord").Preload("EnglishWord").Preload("Examples").First(translation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return customErrors.ErrTranslationNotFound
+	}
+	return err
+}
+
+// populateBatchSize keeps the IN lists of the preload queries well below the
+// 65535 bind parameters PostgreSQL accepts in a single statement.
+const populateBatchSize = 10000
+
+// PopulateTranslationsWithAssociations loads the associations of all given
+// translations with one query per table instead of one round per translation.
+func (manager *DBManager) PopulateTranslationsWithAssociations(translations []*dbModels.Translation) error {
+	for start := 0; start < len(translations); start += populateBatchSize {
+		end := min(start+populateBatchSize, len(translations))
+		if err := manager.populateTranslationsBatch(translations[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (manager *DBManager) populateTranslationsBatch(translations []*dbModels.Translation) error {
+	ids := make([]uint, len(translations))
+	for i, translation := range translations {
+		ids[i] = translation.ID
+	}
+	var loaded []*dbModels.Translation
+	if err := manager.db.Preload("PolishWord").Preload("EnglishWord").Preload("Examples", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Find(&loaded, ids).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*dbModels.Translation, len(loaded))
+	for _, translation := range loaded {
+		byID[translation.ID] = translation
+	}
+	for _, translation := range translations {
+		populated, ok := byID[translation.ID]
+		if !ok {
+			return customErrors.ErrTranslationNotFound
+		}
+		*translation = *populated
+	}
+	return nil
 }
 
 func (manager *DBManager) GetTranslations() ([]*dbModels.Translation, error) {
@@ -162,42 +323,130 @@ func (manager *DBManager) GetTranslations() ([]*dbModels.Translation, error) {
 	return translations, nil
 }
 
-func (manager *DBManager) GetTranslationsToEnglish(wordInPolish string) ([]*dbModels.Translation, error) {
-	var translations []*dbModels.Translation
-	if err := manager.db.
-		Where("polish_word_id IN (SELECT id FROM polish_words WHERE text = ?)", wordInPolish).
-		Find(&translations).Error; err != nil {
+func (manager *DBManager) GetTranslationsPage(page PageRequest) (*Page[dbModels.Translation], error) {
+	return paginate[dbModels.Translation](manager.db, page)
+}
+
+func (manager *DBManager) GetTranslationsToEnglish(wordInPolish string, options LookupOptions) ([]*dbModels.Translation, error) {
+	wordIds, err := lookupPolishWordIds(manager.db, wordInPolish, options)
+	if err != nil {
+		return nil, err
+	}
+	translations := []*dbModels.Translation{}
+	if len(wordIds) == 0 {
+		return translations, nil
+	}
+	manager.lookups.add("polish_words", wordIds)
+	if err := manager.db.Where("polish_word_id IN ?", wordIds).Order("id").Find(&translations).Error; err != nil {
 		return nil, err
 	}
 	return translations, nil
 }
 
-func (manager *DBManager) GetTranslationsToPolish(wordInEnglish string) ([]*dbModels.Translation, error) {
-	var translations []*dbModels.Translation
-	if err := manager.db.
-		Where("english_word_id IN (SELECT id FROM english_words WHERE text = ?)", wordInEnglish).
-		Find(&translations).Error; err != nil {
+func (manager *DBManager) GetTranslationsToPolish(wordInEnglish string, options LookupOptions) ([]*dbModels.Translation, error) {
+	// English words have neither gender nor aspect
+	options.Gender, options.Aspect = "", ""
+	wordIds, err := lookupWordIds(manager.db, &dbModels.EnglishWord{}, wordInEnglish, options)
+	if err != nil {
+		return nil, err
+	}
+	translations := []*dbModels.Translation{}
+	if len(wordIds) == 0 {
+		return translations, nil
+	}
+	manager.lookups.add("english_words", wordIds)
+	if err := manager.db.Where("english_word_id IN ?", wordIds).Order("id").Find(&translations).Error; err != nil {
 		return nil, err
 	}
 	return translations, nil
 }
 
-func (manager *DBManager) DeleteRecordFromTable(table interface{}, id uint) error {
-	return manager.db.Delete(&table, id).Error
+func (manager *DBManager) GetSuggestions(word string, language model.Language, limit int) ([]*Suggestion, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	var table interface{}
+	switch language {
+	case model.LanguagePolish:
+		table = &dbModels.PolishWord{}
+	case model.LanguageEnglish:
+		table = &dbModels.EnglishWord{}
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+	candidates, err := suggestionCandidates(manager.db, table, word)
+	if err != nil {
+		return nil, err
+	}
+	return RankSuggestions(word, candidates, limit)
 }
 
-func (manager *DBManager) ChangeExampleText(id uint, text string) (*dbModels.Example, error) {
-	var example dbModels.Example
-	err := manager.db.First(&example, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErrors.ErrExampleNotFound
-		}
+func (manager *DBManager) Autocomplete(prefix string, language model.Language, ranking model.AutocompleteRanking, limit int) ([]*WordEntry, error) {
+	if err := ValidateLimit(limit); err != nil {
 		return nil, err
 	}
-	example.Text = text
-	if err := manager.db.Save(&example).Error; err != nil {
-		if strings.Contains(err.Error(), "idx_translation_text") {
+	var table, foreignKey string
+	switch language {
+	case model.LanguagePolish:
+		table, foreignKey = "polish_words", "polish_word_id"
+	case model.LanguageEnglish:
+		table, foreignKey = "english_words", "english_word_id"
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+	query := manager.db.Table(table).Select(
+		"id, text, folded_key, lookup_count, " +
+			"(SELECT COUNT(*) FROM translations WHERE translations." + foreignKey + " = " + table + ".id" +
+			" AND translations.deleted_at IS NULL) AS translation_count",
+	).Where("deleted_at IS NULL")
+	entries := []*WordEntry{}
+	if err := wherePrefix(query, normalize.FoldedKey(prefix)).
+		Order(autocompleteOrder(ranking)).
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (manager *DBManager) SearchExamples(query string, language *model.Language, page PageRequest) (*Page[ExampleMatch], error) {
+	if manager.db.Dialector.Name() == "postgres" {
+		return searchExamplesPostgres(manager.db, query, language, page)
+	}
+	return searchExampleWords(manager.db, query, language, page)
+}
+
+func (manager *DBManager) WithinTransaction(fn func(store DictionaryStore) error) error {
+	return manager.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&DBManager{db: tx, lookups: manager.lookups})
+	})
+}
+
+func (manager *DBManager) LockRecord(table interface{}, id uint) error {
+	var locked []uint
+	return manager.db.Unscoped().Model(table).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).Pluck("id", &locked).Error
+}
+
+func (manager *DBManager) ChangeExampleText(id uint, text string, expectedVersion *uint) (*dbModels.Example, error) {
+	var example dbModels.Example
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		// Locked, so concurrent changes wait for each other instead of overwriting one another
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&example, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return customErrors.ErrExampleNotFound
+			}
+			return err
+		}
+		if expectedVersion != nil && *expectedVersion != example.Version {
+			return customErrors.ErrVersionConflict
+		}
+		example.Text = text
+		example.Version++
+		return tx.Select("text", "search_words", "version").Updates(&example).Error
+	})
+	if err != nil {
+		if exampleTextUnique.violatedBy(err) {
 			return nil, customErrors.ErrExampleAlreadyExists
 		}
 		return nil, err
@@ -205,36 +454,50 @@ func (manager *DBManager) ChangeExampleText(id uint, text string) (*dbModels.Exa
 	return &example, nil
 }
 
-func (manager *DBManager) ChangePolishWordText(id uint, text string) (*dbModels.PolishWord, error) {
+func (manager *DBManager) ChangePolishWordText(id uint, text string, expectedVersion *uint) (*dbModels.PolishWord, error) {
 	var polishWord dbModels.PolishWord
-	err := manager.db.First(&polishWord, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErrors.ErrPolishWordNotFound
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		// Locked, so concurrent changes wait for each other instead of overwriting one another
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&polishWord, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return customErrors.ErrPolishWordNotFound
+			}
+			return err
 		}
-		return nil, err
-	}
-	polishWord.Text = text
-	if err := manager.db.Save(&polishWord).Error; err != nil {
-		if strings.Contains(err.Error(), "uni_polish_words_text") {
+		if expectedVersion != nil && *expectedVersion != polishWord.Version {
+			return customErrors.ErrVersionConflict
+		}
+		polishWord.Text = text
+		polishWord.Version++
+		return tx.Select("text", "search_key", "folded_key", "version").Updates(&polishWord).Error
+	})
+	if err != nil {
+		if polishWordTextUnique.violatedBy(err) {
 			return nil, customErrors.ErrPolishWordAlreadyExists
 		}
 		return nil, err
 	}
 	return &polishWord, nil
 }
-func (manager *DBManager) ChangeEnglishWordText(id uint, text string) (*dbModels.EnglishWord, error) {
+func (manager *DBManager) ChangeEnglishWordText(id uint, text string, expectedVersion *uint) (*dbModels.EnglishWord, error) {
 	var englishWord dbModels.EnglishWord
-	err := manager.db.First(&englishWord, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErrors.ErrEnglishWordNotFound
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		// Locked, so concurrent changes wait for each other instead of overwriting one another
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&englishWord, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return customErrors.ErrEnglishWordNotFound
+			}
+			return err
 		}
-		return nil, err
-	}
-	englishWord.Text = text
-	if err := manager.db.Save(&englishWord).Error; err != nil {
-		if strings.Contains(err.Error(), "uni_english_words_text") {
+		if expectedVersion != nil && *expectedVersion != englishWord.Version {
+			return customErrors.ErrVersionConflict
+		}
+		englishWord.Text = text
+		englishWord.Version++
+		return tx.Select("text", "search_key", "folded_key", "version").Updates(&englishWord).Error
+	})
+	if err != nil {
+		if englishWordTextUnique.violatedBy(err) {
 			return nil, customErrors.ErrEnglishWordAlreadyExists
 		}
 		return nil, err
@@ -289,3 +552,53 @@ func (manager *DBManager) GetTranslationById(id uint) (*dbModels.Translation, er
 	}
 	return &translation, nil
 }
+
+func (manager *DBManager) GetPolishWordsByIds(ids []uint) ([]*dbModels.PolishWord, error) {
+	var words []*dbModels.PolishWord
+	if err := manager.db.Find(&words, ids).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func (manager *DBManager) GetEnglishWordsByIds(ids []uint) ([]*dbModels.EnglishWord, error) {
+	var words []*dbModels.EnglishWord
+	if err := manager.db.Find(&words, ids).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func (manager *DBManager) GetExamplesByTranslationIds(ids []uint) ([]*dbModels.Example, error) {
+	var examples []*dbModels.Example
+	if err := manager.db.Where("translation_id IN ?", ids).Order("id").Find(&examples).Error; err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+func (manager *DBManager) GetTranslationsByPolishWordIds(ids []uint) ([]*dbModels.Translation, error) {
+	var translations []*dbModels.Translation
+	if err := manager.db.Where("polish_word_id IN ?", ids).Order("id").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (manager *DBManager) GetTranslationsByEnglishWordIds(ids []uint) ([]*dbModels.Translation, error) {
+	var translations []*dbModels.Translation
+	if err := manager.db.Where("english_word_id IN ?", ids).Order("id").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (manager *DBManager) GetWordFormsByPolishWordIds(ids []uint) ([]*dbModels.WordForm, error) {
+	var forms []*dbModels.WordForm
+	if err := manager.db.Where("polish_word_id IN ?", ids).
+		Where("polish_word_id IN (?)", manager.db.Model(&dbModels.PolishWord{}).Select("id")).
+		Order("id").Find(&forms).Error; err != nil {
+		return nil, err
+	}
+	return forms, nil
+}
@@ -1,19 +1,25 @@
 package database
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/realagmag/dictionaryGO/config"
 	"github.com/realagmag/dictionaryGO/graph/model"
 	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/migrations"
 	dbModels "github.com/realagmag/dictionaryGO/internal/models"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -26,43 +32,33 @@ func setupTestDB() {
 		log.Fatal("Failed to get project root path:", err)
 	}
 	envPath := filepath.Join(projectRoot, ".env")
+	// The variables may also come from the environment, e.g. when running
+	// the suite against SQLite with DB_DRIVER=sqlite.
 	err = godotenv.Load(envPath)
-	if err != nil {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatal("Error loading .env file")
 	}
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("TEST_DB_NAME"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_SSLMODE"),
-		os.Getenv("DB_TIMEZONE"),
-	)
 
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err = config.Open(os.Getenv("TEST_DB_NAME"))
 	if err != nil {
 		log.Fatalf("failed to connect to the database: %v", err)
 	}
 
-	migErr := db.AutoMigrate(&dbModels.PolishWord{}, &dbModels.EnglishWord{}, &dbModels.Translation{}, &dbModels.Example{})
+	_, migErr := migrations.NewMigrator(db).Up()
 	if migErr != nil {
 		log.Fatal("Failed to migrate database:", migErr)
 	}
-	// GORM didn't apply on delete cascade to foreign key
-	db.Exec(`ALTER TABLE examples 
-         DROP CONSTRAINT IF EXISTS fk_translations_examples;
-         ALTER TABLE examples
-         ADD CONSTRAINT fk_translations_examples 
-         FOREIGN KEY (translation_id) 
-         REFERENCES translations(id) 
-         ON DELETE CASCADE;`)
 	manager = NewDBManager(db)
 }
 
 func clearTestDB(db *gorm.DB) {
-	db.Exec("TRUNCATE TABLE examples, translations, english_words, polish_words RESTART IDENTITY CASCADE;")
+	// Counts pending from a test would otherwise go to the words of the next
+	manager.FlushLookups()
+	if db.Dialector.Name() == "sqlite" {
+		db.Exec("DELETE FROM polish_word_revisions; DELETE FROM english_word_revisions; DELETE FROM translation_revisions; DELETE FROM example_revisions; DELETE FROM audit_entries; DELETE FROM api_keys; DELETE FROM users; DELETE FROM translation_tags; DELETE FROM review_cards; DELETE FROM word_forms; DELETE FROM examples; DELETE FROM translations; DELETE FROM english_words; DELETE FROM polish_words; DELETE FROM sqlite_sequence;")
+		return
+	}
+	db.Exec("TRUNCATE TABLE polish_word_revisions, english_word_revisions, translation_revisions, example_revisions, audit_entries, api_keys, users, translation_tags, review_cards, word_forms, examples, translations, english_words, polish_words RESTART IDENTITY CASCADE;")
 }
 
 func TestMain(m *testing.M) {
@@ -77,7 +73,7 @@ func TestAddPolishWord(t *testing.T) {
 
 	word := "koń"
 
-	polishWord, err := manager.AddPolishWord(word)
+	polishWord, err := manager.AddPolishWord(dbModels.PolishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, word, polishWord.Text)
 }
@@ -87,11 +83,11 @@ func TestAddSamePolishWord(t *testing.T) {
 
 	word := "kot"
 
-	polishWord, err := manager.AddPolishWord(word)
+	polishWord, err := manager.AddPolishWord(dbModels.PolishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, word, polishWord.Text)
 
-	polishWord2, err := manager.AddPolishWord(word)
+	polishWord2, err := manager.AddPolishWord(dbModels.PolishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, polishWord.ID, polishWord2.ID)
 	assert.Equal(t, polishWord.Text, polishWord2.Text)
@@ -102,23 +98,71 @@ func TestAddDifferentPolishWord(t *testing.T) {
 
 	word := "kot"
 
-	polishWord, err := manager.AddPolishWord(word)
+	polishWord, err := manager.AddPolishWord(dbModels.PolishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, word, polishWord.Text)
 
 	word2 := "pies"
-	polishWord2, err := manager.AddPolishWord(word2)
+	polishWord2, err := manager.AddPolishWord(dbModels.PolishWord{Text: word2})
 	assert.NoError(t, err)
 	assert.NotEqual(t, polishWord.ID, polishWord2.ID)
 	assert.NotEqual(t, polishWord.Text, polishWord2.Text)
 }
 
+func TestAddSameWordAsDifferentPartsOfSpeech(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	noun, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: "run", PartOfSpeech: "NOUN"})
+	assert.NoError(t, err)
+	verb, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: "run", PartOfSpeech: "VERB"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, noun.ID, verb.ID)
+	verb2, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: "run", PartOfSpeech: "VERB"})
+	assert.NoError(t, err)
+	assert.Equal(t, verb.ID, verb2.ID)
+
+	_, err = manager.ChangeEnglishWordText(noun.ID, "run", nil)
+	assert.NoError(t, err)
+	unspecified, _ := manager.AddEnglishWord(dbModels.EnglishWord{Text: "sprint"})
+	_, err = manager.ChangeEnglishWordText(unspecified.ID, "run", nil)
+	assert.NoError(t, err)
+	other, _ := manager.AddEnglishWord(dbModels.EnglishWord{Text: "jog"})
+	_, err = manager.ChangeEnglishWordText(other.ID, "run", nil)
+	assert.Equal(t, customErrors.ErrEnglishWordAlreadyExists, err)
+}
+
+func TestAddPolishWordChecksGrammar(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	_, err := manager.AddPolishWord(dbModels.PolishWord{Text: "szybki", PartOfSpeech: "ADJECTIVE", Gender: "FEMININE"})
+	assert.Equal(t, customErrors.ErrGenderWithoutNoun, err)
+	_, err = manager.AddPolishWord(dbModels.PolishWord{Text: "kot", Aspect: "PERFECTIVE"})
+	assert.Equal(t, customErrors.ErrAspectWithoutVerb, err)
+
+	cat, err := manager.AddPolishWord(dbModels.PolishWord{Text: "kot", PartOfSpeech: "NOUN", Gender: "MASCULINE_ANIMATE"})
+	assert.NoError(t, err)
+	same, err := manager.AddPolishWord(dbModels.PolishWord{Text: "kot", PartOfSpeech: "NOUN"})
+	assert.NoError(t, err)
+	assert.Equal(t, cat.ID, same.ID)
+	_, err = manager.AddPolishWord(dbModels.PolishWord{Text: "kot", PartOfSpeech: "NOUN", Gender: "FEMININE"})
+	assert.Equal(t, customErrors.ErrGrammarMismatch, err)
+
+	verb := model.PartOfSpeechVerb
+	perfective, imperfective := model.AspectPerfective, model.AspectImperfective
+	_, err = manager.AddTranslation(model.TranslationInput{PolishWord: "biec", EnglishWord: "run", PartOfSpeech: &verb, PolishAspect: &imperfective})
+	assert.NoError(t, err)
+	_, err = manager.AddTranslation(model.TranslationInput{PolishWord: "biec", EnglishWord: "race", PartOfSpeech: &verb, PolishAspect: &perfective})
+	assert.Equal(t, customErrors.ErrGrammarMismatch, err)
+	englishWords, _ := manager.GetEnglishWords()
+	assert.Len(t, englishWords, 1, "nothing of a rejected translation is stored")
+}
+
 func TestAddEnglishWord(t *testing.T) {
 	defer clearTestDB(manager.db)
 
 	word := "cat"
 
-	englishWord, err := manager.AddEnglishWord(word)
+	englishWord, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, word, englishWord.Text)
 }
@@ -128,11 +172,11 @@ func TestAddSameEnglishWord(t *testing.T) {
 
 	word := "cat"
 
-	englishWord, err := manager.AddEnglishWord(word)
+	englishWord, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, word, englishWord.Text)
 
-	englishWord2, err := manager.AddEnglishWord(word)
+	englishWord2, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: word})
 	assert.NoError(t, err)
 	assert.Equal(t, englishWord.ID, englishWord2.ID)
 }
@@ -165,10 +209,10 @@ func TestAddTranslationExistingWordsNoExamples(t *testing.T) {
 	defer clearTestDB(manager.db)
 
 	enWord := "cat"
-	englishWord, err := manager.AddEnglishWord(enWord)
+	englishWord, err := manager.AddEnglishWord(dbModels.EnglishWord{Text: enWord})
 	assert.NoError(t, err)
 	plWord := "kot"
-	polishWord, err := manager.AddPolishWord(plWord)
+	polishWord, err := manager.AddPolishWord(dbModels.PolishWord{Text: plWord})
 	assert.NoError(t, err)
 	translationInput := model.TranslationInput{PolishWord: plWord, EnglishWord: enWord}
 	translation, err := manager.AddTranslation(translationInput)
@@ -312,9 +356,9 @@ func TestAddExampleToNotExistingTranslation(t *testing.T) {
 func TestGetPolishWords(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddPolishWord("kotlet")
-	manager.AddPolishWord("świeca")
-	manager.AddPolishWord("kaczka")
+	manager.AddPolishWord(dbModels.PolishWord{Text: "kotlet"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "świeca"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "kaczka"})
 	polishWords, err := manager.GetPolishWords()
 	assert.NoError(t, err)
 	assert.Len(t, polishWords, 3)
@@ -326,8 +370,8 @@ func TestGetPolishWords(t *testing.T) {
 func TestGetEnglishWords(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddEnglishWord("meal")
-	manager.AddEnglishWord("dream")
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "meal"})
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "dream"})
 	englishWords, err := manager.GetEnglishWords()
 	assert.NoError(t, err)
 	assert.Len(t, englishWords, 2)
@@ -349,7 +393,7 @@ func TestGetTranslationsToEnglish(t *testing.T) {
 		PolishWord:  "koń",
 		EnglishWord: "horse",
 	})
-	translationsToEnglish, err := manager.GetTranslationsToEnglish("wieża")
+	translationsToEnglish, err := manager.GetTranslationsToEnglish("wieża", LookupOptions{})
 	assert.NoError(t, err)
 	assert.Len(t, translationsToEnglish, 2)
 	firstTranslation := translationsToEnglish[0]
@@ -361,7 +405,7 @@ func TestGetTranslationsToEnglish(t *testing.T) {
 	assert.Equal(t, "rook", secondTranslation.EnglishWord.Text)
 	assert.Equal(t, "wieża", secondTranslation.PolishWord.Text)
 
-	translationsToEnglish, err = manager.GetTranslationsToEnglish("koń")
+	translationsToEnglish, err = manager.GetTranslationsToEnglish("koń", LookupOptions{})
 	assert.NoError(t, err)
 	assert.Len(t, translationsToEnglish, 1)
 	manager.PopulateTranslationWithAssociations(translationsToEnglish[0])
@@ -383,7 +427,7 @@ func TestGetTranslationsToPolish(t *testing.T) {
 		PolishWord:  "koń",
 		EnglishWord: "horse",
 	})
-	translationsToPolish, err := manager.GetTranslationsToPolish("book")
+	translationsToPolish, err := manager.GetTranslationsToPolish("book", LookupOptions{})
 	assert.NoError(t, err)
 	assert.Len(t, translationsToPolish, 2)
 	firstTranslation := translationsToPolish[0]
@@ -395,7 +439,7 @@ func TestGetTranslationsToPolish(t *testing.T) {
 	assert.Equal(t, "book", secondTranslation.EnglishWord.Text)
 	assert.Equal(t, "książka", secondTranslation.PolishWord.Text)
 
-	translationsToPolish, err = manager.GetTranslationsToEnglish("koń")
+	translationsToPolish, err = manager.GetTranslationsToEnglish("koń", LookupOptions{})
 	assert.NoError(t, err)
 	assert.Len(t, translationsToPolish, 1)
 	manager.PopulateTranslationWithAssociations(translationsToPolish[0])
@@ -403,12 +447,329 @@ func TestGetTranslationsToPolish(t *testing.T) {
 	assert.Equal(t, "koń", translationsToPolish[0].PolishWord.Text)
 }
 
+func TestGetTranslationsWithLookupOptions(t *testing.T) {
+	defer clearTestDB(manager.db)
+	manager.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "turtle"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "Żółw", EnglishWord: "Turtle"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "żołnierz", EnglishWord: "soldier"})
+
+	translations, err := manager.GetTranslationsToEnglish("zolw", LookupOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 0)
+
+	translations, err = manager.GetTranslationsToEnglish("zolw", LookupOptions{FoldDiacritics: true})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1)
+	manager.PopulateTranslationWithAssociations(translations[0])
+	assert.Equal(t, "żółw", translations[0].PolishWord.Text)
+
+	translations, err = manager.GetTranslationsToEnglish("ZOLW", LookupOptions{IgnoreCase: true, FoldDiacritics: true})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+
+	translations, err = manager.GetTranslationsToPolish("TURTLE", LookupOptions{IgnoreCase: true})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+}
+
+func TestGetTranslationsFilteredByGrammar(t *testing.T) {
+	defer clearTestDB(manager.db)
+	verb := model.PartOfSpeechVerb
+	noun := model.PartOfSpeechNoun
+	perfective := model.AspectPerfective
+	imperfective := model.AspectImperfective
+	manager.AddTranslation(model.TranslationInput{PolishWord: "bieg", EnglishWord: "run", PartOfSpeech: &noun})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "biegać", EnglishWord: "run", PartOfSpeech: &verb, PolishAspect: &imperfective})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "pobiec", EnglishWord: "run", PartOfSpeech: &verb, PolishAspect: &perfective})
+
+	translations, err := manager.GetTranslationsToPolish("run", LookupOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 3)
+
+	translations, err = manager.GetTranslationsToPolish("run", LookupOptions{PartOfSpeech: "VERB"})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+	manager.PopulateTranslationsWithAssociations(translations)
+	assert.Equal(t, "biegać", translations[0].PolishWord.Text)
+	assert.Equal(t, "IMPERFECTIVE", translations[0].PolishWord.Aspect)
+	assert.Equal(t, "VERB", translations[0].EnglishWord.PartOfSpeech)
+
+	translations, err = manager.GetTranslationsToEnglish("pobiec", LookupOptions{Aspect: "IMPERFECTIVE"})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 0)
+	translations, err = manager.GetTranslationsToEnglish("pobiec", LookupOptions{PartOfSpeech: "VERB", Aspect: "PERFECTIVE"})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1)
+}
+
+func TestFindTranslation(t *testing.T) {
+	defer clearTestDB(manager.db)
+	noun := model.PartOfSpeechNoun
+	translation, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "bieg", EnglishWord: "run", PartOfSpeech: &noun})
+
+	found, err := manager.FindTranslation(model.TranslationInput{PolishWord: "bieg", EnglishWord: "run", PartOfSpeech: &noun})
+	assert.NoError(t, err)
+	assert.Equal(t, translation.ID, found.ID)
+	_, err = manager.FindTranslation(model.TranslationInput{PolishWord: "bieg", EnglishWord: "run"})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
+
+func TestWithinTransactionRollsBackOnError(t *testing.T) {
+	defer clearTestDB(manager.db)
+	rollback := errors.New("rollback")
+
+	err := manager.WithinTransaction(func(store DictionaryStore) error {
+		if _, err := store.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"}); err != nil {
+			return err
+		}
+		if _, err := store.FindTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"}); err != nil {
+			return err
+		}
+		return rollback
+	})
+	assert.Equal(t, rollback, err)
+	words, _ := manager.GetPolishWords()
+	assert.Len(t, words, 0)
+
+	err = manager.WithinTransaction(func(store DictionaryStore) error {
+		_, err := store.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+		return err
+	})
+	assert.NoError(t, err)
+	translations, _ := manager.GetTranslations()
+	assert.Len(t, translations, 1)
+}
+
+func TestSetPolishWordFormsAndLookUpByForm(t *testing.T) {
+	defer clearTestDB(manager.db)
+	translation, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+
+	forms, err := manager.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{
+		{Text: "psa", GrammaticalCase: "GENITIVE", Number: "SINGULAR"},
+		{Text: "psa", GrammaticalCase: "ACCUSATIVE", Number: "SINGULAR"},
+		{Text: "psy", GrammaticalCase: "NOMINATIVE", Number: "PLURAL"},
+	}, false)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 3)
+
+	forms, err = manager.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{
+		{Text: "psy", GrammaticalCase: "NOMINATIVE", Number: "PLURAL"},
+		{Text: "Psem", GrammaticalCase: "INSTRUMENTAL", Number: "SINGULAR"},
+	}, false)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 4)
+
+	translations, err := manager.GetTranslationsToEnglish("psa", LookupOptions{IncludeForms: true})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1)
+	assert.Equal(t, translation.ID, translations[0].ID)
+	translations, _ = manager.GetTranslationsToEnglish("psem", LookupOptions{IncludeForms: true, IgnoreCase: true})
+	assert.Len(t, translations, 1)
+	translations, _ = manager.GetTranslationsToEnglish("psa", LookupOptions{})
+	assert.Len(t, translations, 0)
+	translations, _ = manager.GetTranslationsToEnglish("psa", LookupOptions{IncludeForms: true, PartOfSpeech: "VERB"})
+	assert.Len(t, translations, 0)
+
+	forms, err = manager.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{{Text: "psu", GrammaticalCase: "DATIVE"}}, true)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 1)
+	translations, _ = manager.GetTranslationsToEnglish("psa", LookupOptions{IncludeForms: true})
+	assert.Len(t, translations, 0)
+
+	_, err = manager.SetPolishWordForms(translation.PolishWordID+100, []dbModels.WordForm{{Text: "psu"}}, false)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+
+	err = manager.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.NoError(t, err)
+	forms, err = manager.GetWordFormsByPolishWordIds([]uint{translation.PolishWordID})
+	assert.NoError(t, err)
+	assert.Len(t, forms, 0)
+}
+
+func TestChangePolishWordTextUpdatesSearchKeys(t *testing.T) {
+	defer clearTestDB(manager.db)
+	translation, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "zolw", EnglishWord: "turtle"})
+
+	_, err := manager.ChangePolishWordText(translation.PolishWordID, "Żółw", nil)
+	assert.NoError(t, err)
+	translations, err := manager.GetTranslationsToEnglish("żółw", LookupOptions{IgnoreCase: true})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1)
+}
+
+func TestGetSuggestions(t *testing.T) {
+	defer clearTestDB(manager.db)
+	for _, word := range []string{"żółw", "kot", "kto", "koń", "książka", "zarezerwować"} {
+		manager.AddPolishWord(dbModels.PolishWord{Text: word})
+	}
+
+	suggestions, err := manager.GetSuggestions("zolw", model.LanguagePolish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "żółw", suggestions[0].Text)
+	assert.Equal(t, 0, suggestions[0].Distance)
+	assert.Equal(t, 1.0, suggestions[0].Score)
+
+	suggestions, err = manager.GetSuggestions("okt", model.LanguagePolish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "kot", suggestions[0].Text)
+
+	suggestions, err = manager.GetSuggestions("zarezrewowac", model.LanguagePolish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "zarezerwować", suggestions[0].Text)
+	assert.Equal(t, 1, suggestions[0].Distance)
+
+	suggestions, err = manager.GetSuggestions("kisązak", model.LanguagePolish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1, "two typos in a short word are found however few trigrams it shares")
+	assert.Equal(t, "książka", suggestions[0].Text)
+	assert.Equal(t, 2, suggestions[0].Distance)
+
+	suggestions, err = manager.GetSuggestions("kot", model.LanguagePolish, 2)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 2)
+	assert.Equal(t, "kot", suggestions[0].Text)
+
+	suggestions, err = manager.GetSuggestions("kot", model.LanguageEnglish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 0)
+
+	_, err = manager.GetSuggestions("kot", model.LanguagePolish, 0)
+	assert.Equal(t, customErrors.ErrInvalidLimit, err)
+}
+
+func TestSearchExamplesWithoutFullTextSearch(t *testing.T) {
+	if manager.db.Dialector.Name() == "postgres" {
+		t.Skip("Postgres searches with its text search configurations")
+	}
+	defer clearTestDB(manager.db)
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples: []*model.ExampleInput{
+			{Text: "Kot śpi na kanapie.", InPolish: true},
+			{Text: "Śpiący kot_1 mruczy.", InPolish: true},
+		},
+	})
+	manager.PopulateTranslationWithAssociations(translation)
+
+	page, err := manager.SearchExamples("ŚPI kan", nil, PageRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Kot "+HighlightStart+"śpi"+HighlightStop+" na "+HighlightStart+"kanapie"+HighlightStop+".", page.Items[0].Snippet)
+	assert.Equal(t, translation.ID, page.Items[0].TranslationID)
+
+	page, _ = manager.SearchExamples("pi", nil, PageRequest{})
+	assert.Empty(t, page.Items, "words have to start with the query")
+	page, _ = manager.SearchExamples("kot%", nil, PageRequest{})
+	assert.Equal(t, int64(2), page.TotalCount, "LIKE wildcards are no words")
+	page, _ = manager.SearchExamples("or", nil, PageRequest{})
+	assert.Equal(t, int64(0), page.TotalCount)
+
+	manager.ChangeExampleText(translation.Examples[0].ID, "Kot je.", nil)
+	page, _ = manager.SearchExamples("je", nil, PageRequest{})
+	assert.Len(t, page.Items, 1)
+	version := page.Items[0].Version
+	_, err = manager.ChangeExampleText(page.Items[0].ID, "Kot je rybę.", &version)
+	assert.NoError(t, err, "the version of a match can be expected by an update")
+	manager.DeleteRecordFromTable(&dbModels.Example{}, translation.Examples[0].ID)
+	page, _ = manager.SearchExamples("kot", nil, PageRequest{})
+	assert.Equal(t, int64(1), page.TotalCount)
+}
+
+func TestAutocomplete(t *testing.T) {
+	defer clearTestDB(manager.db)
+	manager.AddTranslation(model.TranslationInput{PolishWord: "żołnierz", EnglishWord: "soldier"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "turtle"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "tortoise"})
+	manager.AddTranslation(model.TranslationInput{PolishWord: "zostać", EnglishWord: "stay"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "zo_"})
+
+	entries, err := manager.Autocomplete("zo", model.LanguagePolish, model.AutocompleteRankingAlphabetical, 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "zo_", entries[0].Text)
+	assert.Equal(t, "żołnierz", entries[1].Text)
+	assert.Equal(t, "żółw", entries[2].Text)
+	assert.Equal(t, "zostać", entries[3].Text)
+
+	entries, err = manager.Autocomplete("zo_", model.LanguagePolish, model.AutocompleteRankingAlphabetical, 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = manager.Autocomplete("Zo", model.LanguagePolish, model.AutocompleteRankingTranslationCount, 1)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "żółw", entries[0].Text)
+	assert.Equal(t, int64(2), entries[0].TranslationCount)
+
+	manager.GetTranslationsToEnglish("zostać", LookupOptions{})
+	manager.GetTranslationsToEnglish("zostac", LookupOptions{FoldDiacritics: true})
+	entries, err = manager.Autocomplete("zo", model.LanguagePolish, model.AutocompleteRankingPopularity, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), entries[0].LookupCount, "lookups are counted in batches")
+	assert.NoError(t, manager.FlushLookups())
+	entries, err = manager.Autocomplete("zo", model.LanguagePolish, model.AutocompleteRankingPopularity, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "zostać", entries[0].Text)
+	assert.Equal(t, int64(2), entries[0].LookupCount)
+
+	_, err = manager.Autocomplete("zo", model.LanguagePolish, model.AutocompleteRankingAlphabetical, 101)
+	assert.Equal(t, customErrors.ErrInvalidLimit, err)
+}
+
+func TestSearchExamples(t *testing.T) {
+	defer clearTestDB(manager.db)
+	manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples: []*model.ExampleInput{
+			{Text: "Kot śpi na kanapie.", InPolish: true},
+			{Text: "The cat is sleeping on the sofa.", InPolish: false},
+			{Text: "Mój kot lubi mleko.", InPolish: true},
+		},
+	})
+	first := 1
+
+	page, err := manager.SearchExamples("kot", nil, PageRequest{First: &first})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.TotalCount)
+	assert.Len(t, page.Items, 1)
+	assert.True(t, page.HasNextPage)
+	assert.Contains(t, page.Items[0].Snippet, HighlightStart+"Kot"+HighlightStop)
+	assert.Equal(t, uint(1), page.Items[0].Version)
+
+	page, err = manager.SearchExamples("kot", nil, PageRequest{First: &first, After: &page.Items[0].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "Mój kot lubi mleko.", page.Items[0].Text)
+	assert.False(t, page.HasNextPage)
+	assert.True(t, page.HasPreviousPage)
+
+	english := model.LanguageEnglish
+	page, err = manager.SearchExamples("sofa", &english, PageRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.False(t, page.Items[0].InPolish)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("kot"), []rune("kot")))
+	assert.Equal(t, 1, editDistance([]rune("kot"), []rune("kto")))
+	assert.Equal(t, 1, editDistance([]rune("kot"), []rune("kon")))
+	assert.Equal(t, 2, editDistance([]rune("ksiazka"), []rune("ksika")))
+	assert.Equal(t, 3, editDistance([]rune(""), []rune("kot")))
+}
+
 func TestDeletePolishWord(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddPolishWord("praca")
-	manager.AddPolishWord("stół")
-	manager.AddPolishWord("książka")
+	manager.AddPolishWord(dbModels.PolishWord{Text: "praca"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "stół"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "książka"})
 	polishWords, _ := manager.GetPolishWords()
 	err := manager.DeleteRecordFromTable(dbModels.PolishWord{}, polishWords[0].ID)
 	assert.NoError(t, err)
@@ -422,9 +783,9 @@ func TestDeletePolishWord(t *testing.T) {
 func TestDeleteEnglishWord(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddEnglishWord("work")
-	manager.AddEnglishWord("table")
-	manager.AddEnglishWord("book")
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "work"})
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "table"})
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "book"})
 	englishWords, _ := manager.GetEnglishWords()
 	err := manager.DeleteRecordFromTable(dbModels.EnglishWord{}, englishWords[0].ID)
 	assert.NoError(t, err)
@@ -547,7 +908,7 @@ func TestChangeExampleText(t *testing.T) {
 	manager.PopulateTranslationWithAssociations(translation)
 	assert.Equal(t, "Dziecko je cukierka", translation.Examples[0].Text)
 	newText := "Dziecko chodzi do przedszkola"
-	example, err := manager.ChangeExampleText(translation.Examples[0].ID, newText)
+	example, err := manager.ChangeExampleText(translation.Examples[0].ID, newText, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, translation.Examples[0].ID, example.ID)
 	assert.NotEqual(t, translation.Examples[0].Text, example.Text)
@@ -569,7 +930,7 @@ func TestChangeExampleTextViolatesUniqueConstraint(t *testing.T) {
 	newExample := model.ExampleInput{Text: "Dziecko chodzi do przedszkola", InPolish: true}
 	manager.AddExampleToTranslation(&newExample, translation.ID)
 	manager.PopulateTranslationWithAssociations(translation)
-	_, err := manager.ChangeExampleText(translation.Examples[1].ID, "Dziecko je cukierka")
+	_, err := manager.ChangeExampleText(translation.Examples[1].ID, "Dziecko je cukierka", nil)
 	assert.Error(t, err)
 	assert.Equal(t, customErrors.ErrExampleAlreadyExists, err)
 }
@@ -577,11 +938,116 @@ func TestChangeExampleTextViolatesUniqueConstraint(t *testing.T) {
 func TestChangeExampleTextOnNonExistingExample(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	_, err := manager.ChangeExampleText(555, "Dziecko je cukierka")
+	_, err := manager.ChangeExampleText(555, "Dziecko je cukierka", nil)
 	assert.Error(t, err)
 	assert.Equal(t, customErrors.ErrExampleNotFound, err)
 }
 
+func TestChangeTextWithExpectedVersion(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kto",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	manager.PopulateTranslationWithAssociations(translation)
+	assert.Equal(t, uint(1), translation.PolishWord.Version)
+
+	read := translation.PolishWord.Version
+	word, err := manager.ChangePolishWordText(translation.PolishWordID, "kot", &read)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), word.Version)
+	assert.Equal(t, "kot", word.SearchKey)
+	_, err = manager.ChangePolishWordText(translation.PolishWordID, "kotek", &read)
+	assert.Equal(t, customErrors.ErrVersionConflict, err)
+	word, _ = manager.GetPolishWordById(translation.PolishWordID)
+	assert.Equal(t, "kot", word.Text)
+	assert.Equal(t, uint(2), word.Version)
+
+	englishWord, err := manager.ChangeEnglishWordText(translation.EnglishWordID, "Cat", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), englishWord.Version)
+	stale := uint(1)
+	_, err = manager.ChangeEnglishWordText(translation.EnglishWordID, "kitten", &stale)
+	assert.Equal(t, customErrors.ErrVersionConflict, err)
+
+	example, err := manager.ChangeExampleText(translation.Examples[0].ID, "Ala ma kotka", &stale)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), example.Version)
+	_, err = manager.ChangeExampleText(555, "Ala ma kotka", &stale)
+	assert.Equal(t, customErrors.ErrExampleNotFound, err)
+}
+
+func TestChangePolishWordTextWhileConcurentRequests(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	word, _ := manager.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+	read := word.Version
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	concurrency := 100
+	changed, conflicts := 0, 0
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := manager.ChangePolishWordText(word.ID, fmt.Sprintf("kot %v", i), &read)
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				changed++
+			case customErrors.ErrVersionConflict:
+				conflicts++
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, changed, "Only one editor can change the version it read")
+	assert.Equal(t, concurrency-1, conflicts, "Every other editor gets a conflict")
+	word, err := manager.GetPolishWordById(word.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), word.Version)
+}
+
+func TestChangeExampleTextWithoutExpectedVersionWhileConcurentRequests(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "chleb",
+		EnglishWord: "bread",
+		Examples:    []*model.ExampleInput{{Text: "test", InPolish: false}},
+	})
+	manager.PopulateTranslationWithAssociations(translation)
+	exampleID := translation.Examples[0].ID
+
+	var wg sync.WaitGroup
+	concurrency := 100
+	errs := make([]error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = manager.ChangeExampleText(exampleID, fmt.Sprintf("test %v", i), nil)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err, "Changes without an expected version never conflict")
+	}
+	example, err := manager.GetExampleById(exampleID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(concurrency+1), example.Version, "Every change is counted")
+}
+
 func TestPreventDuplicatePolishWordsWhileCreating(t *testing.T) {
 	defer clearTestDB(manager.db)
 
@@ -592,7 +1058,7 @@ func TestPreventDuplicatePolishWordsWhileCreating(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = manager.AddPolishWord("balon")
+			_, _ = manager.AddPolishWord(dbModels.PolishWord{Text: "balon"})
 		}()
 	}
 
@@ -606,9 +1072,9 @@ func TestPreventDuplicatePolishWordsWhileCreating(t *testing.T) {
 func TestChangePolishWordTextCorrect(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	originalWord, _ := manager.AddPolishWord("książka")
+	originalWord, _ := manager.AddPolishWord(dbModels.PolishWord{Text: "książka"})
 	polishWords, _ := manager.GetPolishWords()
-	word, err := manager.ChangePolishWordText(polishWords[0].ID, "stół")
+	word, err := manager.ChangePolishWordText(polishWords[0].ID, "stół", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, originalWord.ID, word.ID)
 	assert.NotEqual(t, originalWord.Text, word.Text)
@@ -617,11 +1083,11 @@ func TestChangePolishWordTextCorrect(t *testing.T) {
 func TestPreventDuplicatePolishWordsWhileUpdating(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddPolishWord("książka")
-	manager.AddPolishWord("miecz")
+	manager.AddPolishWord(dbModels.PolishWord{Text: "książka"})
+	manager.AddPolishWord(dbModels.PolishWord{Text: "miecz"})
 	polishWords, _ := manager.GetPolishWords()
 	assert.Len(t, polishWords, 2)
-	word, err := manager.ChangePolishWordText(polishWords[0].ID, polishWords[1].Text)
+	word, err := manager.ChangePolishWordText(polishWords[0].ID, polishWords[1].Text, nil)
 	assert.Nil(t, word)
 	assert.Equal(t, customErrors.ErrPolishWordAlreadyExists, err)
 	polishWords, _ = manager.GetPolishWords()
@@ -631,7 +1097,7 @@ func TestPreventDuplicatePolishWordsWhileUpdating(t *testing.T) {
 func TestChangePolishWordOnNonExistingPolishWord(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	word, err := manager.ChangePolishWordText(555, "przykład")
+	word, err := manager.ChangePolishWordText(555, "przykład", nil)
 	assert.Error(t, err)
 	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
 	assert.Nil(t, word)
@@ -647,7 +1113,7 @@ func TestPreventDuplicateEnglishWordsWhileCreating(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, _ = manager.AddEnglishWord("baloon")
+			_, _ = manager.AddEnglishWord(dbModels.EnglishWord{Text: "baloon"})
 		}()
 	}
 
@@ -661,9 +1127,9 @@ func TestPreventDuplicateEnglishWordsWhileCreating(t *testing.T) {
 func TestChangeEnglishWordTextCorrect(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	originalWord, _ := manager.AddEnglishWord("book")
+	originalWord, _ := manager.AddEnglishWord(dbModels.EnglishWord{Text: "book"})
 	englishWords, _ := manager.GetEnglishWords()
-	word, err := manager.ChangeEnglishWordText(englishWords[0].ID, "table")
+	word, err := manager.ChangeEnglishWordText(englishWords[0].ID, "table", nil)
 	assert.NoError(t, err)
 	assert.Equal(t, originalWord.ID, word.ID)
 	assert.NotEqual(t, originalWord.Text, word.Text)
@@ -672,11 +1138,11 @@ func TestChangeEnglishWordTextCorrect(t *testing.T) {
 func TestPreventDuplicateEnglishWordsWhileUpdating(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	manager.AddEnglishWord("book")
-	manager.AddEnglishWord("sword")
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "book"})
+	manager.AddEnglishWord(dbModels.EnglishWord{Text: "sword"})
 	englishWords, _ := manager.GetEnglishWords()
 	assert.Len(t, englishWords, 2)
-	word, err := manager.ChangeEnglishWordText(englishWords[0].ID, englishWords[1].Text)
+	word, err := manager.ChangeEnglishWordText(englishWords[0].ID, englishWords[1].Text, nil)
 	assert.Nil(t, word)
 	assert.Equal(t, customErrors.ErrEnglishWordAlreadyExists, err)
 	englishWords, _ = manager.GetEnglishWords()
@@ -686,7 +1152,7 @@ func TestPreventDuplicateEnglishWordsWhileUpdating(t *testing.T) {
 func TestChangeEnglishWordOnNonExistingEnglishWord(t *testing.T) {
 	defer clearTestDB(manager.db)
 
-	word, err := manager.ChangeEnglishWordText(555, "example")
+	word, err := manager.ChangeEnglishWordText(555, "example", nil)
 	assert.Error(t, err)
 	assert.Equal(t, customErrors.ErrEnglishWordNotFound, err)
 	assert.Nil(t, word)
@@ -767,9 +1233,60 @@ func TestAddEveryExampleToTranslationWhileConcurentRequests(t *testing.T) {
 	assert.Len(t, translation.Examples, 100, "Each thread adds its unique example to translation")
 }
 
+func TestSeedRevisionsWhileConcurentChanges(t *testing.T) {
+	defer clearTestDB(manager.db)
+	word, _ := manager.AddPolishWord(dbModels.PolishWord{Text: "kto"})
+
+	var wg sync.WaitGroup
+	concurrency := 10
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// The history seeds the revision of the word as it was before its
+			// first change, once the word is locked.
+			_ = manager.WithinTransaction(func(store DictionaryStore) error {
+				if err := store.LockRecord(&dbModels.PolishWord{}, word.ID); err != nil {
+					return err
+				}
+				revisions, err := store.GetPolishWordRevisions(word.ID)
+				if err != nil {
+					return err
+				}
+				if len(revisions) == 0 {
+					before, err := store.GetPolishWordById(word.ID)
+					if err != nil {
+						return err
+					}
+					if err := store.AddRevision(&dbModels.PolishWordRevision{PolishWordID: word.ID, Text: before.Text}); err != nil {
+						return err
+					}
+				}
+				changed, err := store.ChangePolishWordText(word.ID, fmt.Sprintf("kot %v", i), nil)
+				if err != nil {
+					return err
+				}
+				return store.AddRevision(&dbModels.PolishWordRevision{PolishWordID: word.ID, Text: changed.Text})
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	revisions, err := manager.GetPolishWordRevisions(word.ID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, concurrency+1, "Only the first change seeds a revision")
+	assert.Equal(t, "kto", revisions[0].Text)
+	for i, revision := range revisions[1:] {
+		assert.NotEqual(t, "kto", revision.Text)
+		assert.Equal(t, uint(i+2), revision.Version)
+	}
+}
+
 func TestGetPolishWordByIdCorrect(t *testing.T) {
 	defer clearTestDB(manager.db)
-	polishWord, _ := manager.AddPolishWord("słowo")
+	polishWord, _ := manager.AddPolishWord(dbModels.PolishWord{Text: "słowo"})
 	word, err := manager.GetPolishWordById(polishWord.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, polishWord, word)
@@ -785,7 +1302,7 @@ func TestGetPolishWordByIdWordNotExists(t *testing.T) {
 
 func TestGetEnglishWordByIdCorrect(t *testing.T) {
 	defer clearTestDB(manager.db)
-	englishWord, _ := manager.AddEnglishWord("word")
+	englishWord, _ := manager.AddEnglishWord(dbModels.EnglishWord{Text: "word"})
 	word, err := manager.GetEnglishWordById(englishWord.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, englishWord, word)
@@ -823,3 +1340,506 @@ func TestGetTranslationByIdTranslationNotExists(t *testing.T) {
 	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
 	assert.Nil(t, translation)
 }
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestGetPolishWordsPageForward(t *testing.T) {
+	defer clearTestDB(manager.db)
+	for _, word := range []string{"jeden", "dwa", "trzy", "cztery", "pięć"} {
+		manager.AddPolishWord(dbModels.PolishWord{Text: word})
+	}
+
+	page, err := manager.GetPolishWordsPage(PageRequest{First: intPtr(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), page.TotalCount)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "jeden", page.Items[0].Text)
+	assert.True(t, page.HasNextPage)
+	assert.False(t, page.HasPreviousPage)
+
+	page, err = manager.GetPolishWordsPage(PageRequest{First: intPtr(2), After: &page.Items[1].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "trzy", page.Items[0].Text)
+	assert.Equal(t, "cztery", page.Items[1].Text)
+	assert.True(t, page.HasNextPage)
+	assert.True(t, page.HasPreviousPage)
+
+	page, err = manager.GetPolishWordsPage(PageRequest{First: intPtr(2), After: &page.Items[1].ID})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "pięć", page.Items[0].Text)
+	assert.False(t, page.HasNextPage)
+}
+
+func TestGetTranslationsPageBackward(t *testing.T) {
+	defer clearTestDB(manager.db)
+	for _, pair := range [][2]string{{"kot", "cat"}, {"pies", "dog"}, {"koń", "horse"}, {"mysz", "mouse"}} {
+		manager.AddTranslation(model.TranslationInput{PolishWord: pair[0], EnglishWord: pair[1]})
+	}
+	translations, _ := manager.GetTranslations()
+
+	page, err := manager.GetTranslationsPage(PageRequest{Last: intPtr(2), Before: &translations[3].ID})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, translations[1].ID, page.Items[0].ID)
+	assert.Equal(t, translations[2].ID, page.Items[1].ID)
+	assert.True(t, page.HasPreviousPage)
+	assert.True(t, page.HasNextPage)
+	assert.Equal(t, int64(4), page.TotalCount)
+}
+
+func TestGetEnglishWordsPageRejectsTooLargePage(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	page, err := manager.GetEnglishWordsPage(PageRequest{First: intPtr(MaxPageSize + 1)})
+	assert.Nil(t, page)
+	assert.Equal(t, customErrors.ErrInvalidPageSize, err)
+}
+
+func TestPopulateTranslationsWithAssociationsUsesConstantQueries(t *testing.T) {
+	defer clearTestDB(manager.db)
+	for i := 0; i < 30; i++ {
+		manager.AddTranslation(model.TranslationInput{
+			PolishWord:  fmt.Sprintf("słowo %v", i),
+			EnglishWord: fmt.Sprintf("word %v", i),
+			Examples: []*model.ExampleInput{
+				{Text: fmt.Sprintf("przykład %v", i), InPolish: true},
+				{Text: fmt.Sprintf("example %v", i), InPolish: false},
+			},
+		})
+	}
+	translations, _ := manager.GetTranslations()
+
+	queries := 0
+	callbackName := "test:count_queries"
+	db.Callback().Query().After("gorm:query").Register(callbackName, func(*gorm.DB) { queries++ })
+	defer db.Callback().Query().Remove(callbackName)
+
+	err := manager.PopulateTranslationsWithAssociations(translations)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, queries, "one query for translations and one per association")
+	assert.Equal(t, "słowo 29", translations[29].PolishWord.Text)
+	assert.Equal(t, "word 29", translations[29].EnglishWord.Text)
+	assert.Len(t, translations[29].Examples, 2)
+}
+
+func TestPopulateTranslationsWithAssociationsMissingTranslation(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	err := manager.PopulateTranslationsWithAssociations([]*dbModels.Translation{{ID: 555}})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
+
+func TestDueCardsAndReviews(t *testing.T) {
+	defer clearTestDB(manager.db)
+	cat, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	dog, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	directions := []string{string(model.StudyDirectionPolishToEnglish), string(model.StudyDirectionEnglishToPolish)}
+
+	cards, err := manager.GetDueCards("ala", directions, now, 3)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 3)
+	assert.Zero(t, cards[0].ID)
+	assert.Equal(t, cat.ID, cards[1].TranslationID)
+	assert.Equal(t, directions[1], cards[1].Direction)
+	assert.Equal(t, dog.ID, cards[2].TranslationID)
+	assert.Equal(t, dog.PolishWordID, cards[2].Translation.PolishWordID)
+
+	card, err := manager.ReviewCard("ala", cat.ID, directions[0], 4, now)
+	assert.NoError(t, err)
+	assert.NotZero(t, card.ID)
+	assert.Equal(t, cat.EnglishWordID, card.Translation.EnglishWordID)
+	assert.True(t, now.Add(24*time.Hour).Equal(card.DueAt))
+	manager.ReviewCard("ala", dog.ID, directions[0], 1, now.Add(-48*time.Hour))
+
+	cards, _ = manager.GetDueCards("ala", directions[:1], now, 10)
+	assert.Len(t, cards, 1)
+	assert.Equal(t, dog.ID, cards[0].TranslationID)
+	assert.Equal(t, 1, cards[0].Lapses)
+
+	card, _ = manager.ReviewCard("ala", cat.ID, directions[0], 5, now.Add(24*time.Hour))
+	assert.Equal(t, 6, card.IntervalDays)
+	assert.Equal(t, 2, card.Repetitions)
+
+	cards, _ = manager.GetDueCards("ala", directions[:1], now.Add(8*24*time.Hour), 10)
+	assert.Len(t, cards, 2)
+	assert.Equal(t, dog.ID, cards[0].TranslationID)
+
+	_, err = manager.ReviewCard("ala", 999, directions[0], 4, now)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+	_, err = manager.ReviewCard("ala", cat.ID, "SIDEWAYS", 4, now)
+	assert.Equal(t, customErrors.ErrUnknownDirection, err)
+	_, err = manager.GetDueCards("ala", directions, now, 0)
+	assert.Equal(t, customErrors.ErrInvalidLimit, err)
+}
+
+func TestSetTranslationTags(t *testing.T) {
+	defer clearTestDB(manager.db)
+	cat, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	dog, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+
+	tags, err := manager.SetTranslationTags(cat.ID, []string{"Zwierzęta", " lekcja 1", "zwierzęta", ""})
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "lekcja 1", tags[0].Name)
+	assert.Equal(t, "zwierzęta", tags[1].Name)
+	manager.SetTranslationTags(dog.ID, []string{"zwierzęta"})
+
+	translations, err := manager.GetTranslationsByTag("ZWIERZĘTA")
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+	assert.Equal(t, cat.ID, translations[0].ID)
+
+	tags, err = manager.SetTranslationTags(cat.ID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+	tags, _ = manager.GetTagsByTranslationIds([]uint{cat.ID, dog.ID})
+	assert.Len(t, tags, 1)
+	assert.Equal(t, dog.ID, tags[0].TranslationID)
+
+	_, err = manager.SetTranslationTags(999, []string{"x"})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
+
+func TestSampleTranslationsAndQuizWords(t *testing.T) {
+	defer clearTestDB(manager.db)
+	noun, verb := model.PartOfSpeechNoun, model.PartOfSpeechVerb
+	var ids []uint
+	for _, input := range []model.TranslationInput{
+		{PolishWord: "kot", EnglishWord: "cat", PartOfSpeech: &noun},
+		{PolishWord: "pies", EnglishWord: "dog", PartOfSpeech: &noun},
+		{PolishWord: "koń", EnglishWord: "horse", PartOfSpeech: &noun},
+		{PolishWord: "biegać", EnglishWord: "run", PartOfSpeech: &verb},
+		{PolishWord: "zamek", EnglishWord: "castle", PartOfSpeech: &noun},
+		{PolishWord: "mysz", EnglishWord: "mouse", PartOfSpeech: &noun},
+	} {
+		translation, _ := manager.AddTranslation(input)
+		ids = append(ids, translation.ID)
+	}
+	manager.SetTranslationTags(ids[0], []string{"zwierzęta"})
+	manager.SetTranslationTags(ids[1], []string{"zwierzęta"})
+	manager.DeleteRecordFromTable(&dbModels.Translation{}, ids[5])
+
+	sorted := slices.Clone(ids[:5])
+	slices.SortFunc(sorted, func(a, b uint) int { return cmp.Compare(SampleKey(a, 7), SampleKey(b, 7)) })
+	translations, err := manager.SampleTranslations("", 3, 7)
+	assert.NoError(t, err)
+	assert.Len(t, translations, 3)
+	for i, translation := range translations {
+		assert.Equal(t, sorted[i], translation.ID, "the database samples like SampleKey")
+	}
+
+	translations, err = manager.SampleTranslations("Zwierzęta", 10, 7)
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+
+	words, err := manager.GetQuizWords(model.LanguageEnglish, 7, 10)
+	assert.NoError(t, err)
+	assert.Len(t, words, 6, "words outlive their translations")
+	for i := 1; i < len(words); i++ {
+		assert.Less(t, SampleKey(words[i-1].ID, 7), SampleKey(words[i].ID, 7), "the database samples like SampleKey")
+	}
+
+	SortQuizWords(words, string(verb), 3, 7)
+	assert.Equal(t, "run", words[0].Text)
+	assert.Equal(t, "castle", words[5].Text)
+
+	words, err = manager.GetQuizWords(model.LanguageEnglish, 7, 2)
+	assert.NoError(t, err)
+	assert.Len(t, words, 2)
+
+	_, err = manager.GetQuizWords("klingon", 7, 3)
+	assert.Equal(t, customErrors.ErrUnknownLanguage, err)
+}
+
+func TestUsersAndAPIKeys(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	user, err := manager.AddUser(dbModels.User{Username: "ala", PasswordHash: "hash"})
+	assert.NoError(t, err)
+	assert.NotZero(t, user.ID)
+	_, err = manager.AddUser(dbModels.User{Username: "ala", PasswordHash: "other"})
+	assert.Equal(t, customErrors.ErrUserAlreadyExists, err)
+
+	found, err := manager.GetUserByUsername("ala")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+	_, err = manager.GetUserByUsername("ola")
+	assert.Equal(t, customErrors.ErrUserNotFound, err)
+	assert.Equal(t, "READER", found.Role)
+	promoted, err := manager.SetUserRole(user.ID, "ADMIN")
+	assert.NoError(t, err)
+	assert.Equal(t, "ADMIN", promoted.Role)
+	_, err = manager.SetUserRole(999, "ADMIN")
+	assert.Equal(t, customErrors.ErrUserNotFound, err)
+
+	key, err := manager.AddAPIKey(dbModels.APIKey{UserID: user.ID, Name: "script", Prefix: "dgo_abc", Hash: "123"})
+	assert.NoError(t, err)
+	_, err = manager.AddAPIKey(dbModels.APIKey{UserID: 999, Name: "script", Prefix: "dgo_abc", Hash: "456"})
+	assert.Equal(t, customErrors.ErrUserNotFound, err)
+
+	byHash, err := manager.GetAPIKeyByHash("123")
+	assert.NoError(t, err)
+	assert.Equal(t, "ala", byHash.User.Username)
+	keys, _ := manager.GetAPIKeysByUserId(user.ID)
+	assert.Len(t, keys, 1)
+
+	assert.Equal(t, customErrors.ErrAPIKeyNotFound, manager.DeleteAPIKey(user.ID+1, key.ID))
+	assert.NoError(t, manager.DeleteAPIKey(user.ID, key.ID))
+	_, err = manager.GetAPIKeyByHash("123")
+	assert.Equal(t, customErrors.ErrAPIKeyNotFound, err)
+}
+
+func TestAuditLog(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := `{"text":"kto"}`
+	for i, entityType := range []string{"POLISH_WORD", "POLISH_WORD", "EXAMPLE"} {
+		_, err := manager.AddAuditEntry(dbModels.AuditEntry{
+			Actor:      "ala",
+			OccurredAt: start.Add(time.Duration(i) * time.Hour),
+			Operation:  "updatePolishWordText",
+			EntityType: entityType,
+			EntityID:   1,
+			Before:     &before,
+		})
+		assert.NoError(t, err)
+	}
+
+	page, err := manager.GetAuditLogPage(AuditFilter{EntityType: "POLISH_WORD"}, PageRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.TotalCount)
+	assert.Equal(t, before, *page.Items[0].Before)
+	assert.Nil(t, page.Items[0].After)
+
+	since := start.Add(time.Hour)
+	id := uint(1)
+	page, _ = manager.GetAuditLogPage(AuditFilter{EntityID: &id, Since: &since}, PageRequest{})
+	assert.Equal(t, int64(2), page.TotalCount)
+	assert.Equal(t, "EXAMPLE", page.Items[1].EntityType)
+
+	// Entries written in a transaction that fails are rolled back with it.
+	failure := errors.New("failure")
+	err = manager.WithinTransaction(func(store DictionaryStore) error {
+		store.AddAuditEntry(dbModels.AuditEntry{Actor: "ala", OccurredAt: start, Operation: "x", EntityType: "EXAMPLE", EntityID: 2})
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	page, _ = manager.GetAuditLogPage(AuditFilter{}, PageRequest{})
+	assert.Equal(t, int64(3), page.TotalCount)
+}
+
+func TestRevisions(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	recordedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, text := range []string{"kto", "kot"} {
+		revision := &dbModels.PolishWordRevision{PolishWordID: 1, Text: text, Revision: dbModels.Revision{Actor: "ala", RecordedAt: recordedAt}}
+		assert.NoError(t, manager.AddRevision(revision))
+	}
+	other := &dbModels.PolishWordRevision{PolishWordID: 2, Text: "pies", Revision: dbModels.Revision{RecordedAt: recordedAt}}
+	assert.NoError(t, manager.AddRevision(other))
+	assert.Equal(t, uint(1), other.Version)
+
+	revisions, err := manager.GetPolishWordRevisions(1)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, uint(2), revisions[1].Version)
+	assert.Equal(t, "kot", revisions[1].Text)
+
+	deleted := &dbModels.ExampleRevision{
+		ExampleID:     3,
+		TranslationID: 1,
+		Text:          "Ala ma kota",
+		Revision:      dbModels.Revision{Deleted: true, DeletedWith: "POLISH_WORD:1:3", RecordedAt: recordedAt},
+	}
+	assert.NoError(t, manager.AddRevision(deleted))
+	examples, err := manager.GetExampleRevisionsByDeletion("POLISH_WORD:1:3")
+	assert.NoError(t, err)
+	assert.Len(t, examples, 1)
+	assert.True(t, examples[0].Deleted)
+	assert.Equal(t, "Ala ma kota", examples[0].Text)
+
+	assert.Error(t, manager.AddRevision(dbModels.PolishWordRevision{}))
+}
+
+func TestAddRevisionWhileConcurentRequests(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	word, _ := manager.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+
+	var wg sync.WaitGroup
+	concurrency := 50
+	errs := make([]error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = manager.AddRevision(&dbModels.PolishWordRevision{
+				PolishWordID: word.ID,
+				Text:         fmt.Sprintf("kot %v", i),
+				Revision:     dbModels.Revision{RecordedAt: time.Now()},
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	revisions, _ := manager.GetPolishWordRevisions(word.ID)
+	assert.Len(t, revisions, concurrency)
+	versions := map[uint]bool{}
+	for _, revision := range revisions {
+		versions[revision.Version] = true
+	}
+	assert.Len(t, versions, concurrency, "Every revision gets its own version")
+}
+
+func TestRestoreRecord(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	manager.PopulateTranslationWithAssociations(translation)
+	example := translation.Examples[0]
+	assert.NoError(t, manager.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID))
+
+	// Recreated under their IDs, references are checked first.
+	err := manager.RestoreRecord(&dbModels.Translation{ID: translation.ID, PolishWordID: translation.PolishWordID, EnglishWordID: translation.EnglishWordID})
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+	assert.NoError(t, manager.RestoreRecord(&dbModels.PolishWord{ID: translation.PolishWordID, Text: "Kot", Gender: "MASCULINE_ANIMATE"}))
+	assert.NoError(t, manager.RestoreRecord(&dbModels.Translation{ID: translation.ID, PolishWordID: translation.PolishWordID, EnglishWordID: translation.EnglishWordID}))
+	assert.NoError(t, manager.RestoreRecord(&dbModels.Example{ID: example.ID, TranslationID: translation.ID, Text: example.Text, InPolish: true}))
+
+	restored := &dbModels.Translation{ID: translation.ID}
+	assert.NoError(t, manager.PopulateTranslationWithAssociations(restored))
+	assert.Equal(t, "Kot", restored.PolishWord.Text)
+	assert.Equal(t, "kot", restored.PolishWord.SearchKey)
+	assert.Equal(t, "MASCULINE_ANIMATE", restored.PolishWord.Gender)
+	assert.Equal(t, uint(2), restored.PolishWord.Version)
+	assert.Len(t, restored.Examples, 1)
+	assert.Equal(t, example.ID, restored.Examples[0].ID)
+
+	// Existing records are updated.
+	assert.NoError(t, manager.RestoreRecord(&dbModels.Example{ID: example.ID, TranslationID: translation.ID, Text: "Ala ma kotka"}))
+	stored, _ := manager.GetExampleById(example.ID)
+	assert.Equal(t, "Ala ma kotka", stored.Text)
+	assert.False(t, stored.InPolish)
+	assert.Equal(t, uint(3), stored.Version)
+
+	manager.AddPolishWord(dbModels.PolishWord{Text: "pies"})
+	err = manager.RestoreRecord(&dbModels.PolishWord{ID: translation.PolishWordID, Text: "pies"})
+	assert.Equal(t, customErrors.ErrPolishWordAlreadyExists, err)
+
+	// New records keep getting fresh IDs.
+	added, err := manager.AddTranslation(model.TranslationInput{PolishWord: "kotek", EnglishWord: "kitten"})
+	assert.NoError(t, err)
+	assert.Greater(t, added.ID, translation.ID)
+}
+
+func TestTrash(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples: []*model.ExampleInput{
+			{Text: "Ala ma kota", InPolish: true},
+			{Text: "I have a cat", InPolish: false},
+		},
+	})
+	manager.SetTranslationTags(translation.ID, []string{"zwierzęta"})
+	kitten, _ := manager.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "kitten"})
+	// Deleted on its own, so restoring the word must not bring it back.
+	assert.NoError(t, manager.DeleteRecordFromTable(dbModels.Translation{}, kitten.ID))
+	assert.NoError(t, manager.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID))
+
+	_, err := manager.GetPolishWordById(translation.PolishWordID)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+	translations, _ := manager.GetTranslationsToPolish("cat", LookupOptions{})
+	assert.Empty(t, translations)
+	entries, _ := manager.Autocomplete("ko", model.LanguagePolish, model.AutocompleteRankingAlphabetical, 10)
+	assert.Empty(t, entries)
+	entries, _ = manager.Autocomplete("ca", model.LanguageEnglish, model.AutocompleteRankingTranslationCount, 10)
+	assert.Equal(t, int64(0), entries[0].TranslationCount)
+	tags, _ := manager.GetTagsByTranslationIds([]uint{translation.ID})
+	assert.Empty(t, tags)
+
+	trash, err := manager.GetTrash()
+	assert.NoError(t, err)
+	assert.Len(t, trash.PolishWords, 1)
+	assert.Empty(t, trash.EnglishWords)
+	assert.Len(t, trash.Translations, 2)
+	assert.Equal(t, translation.ID, trash.Translations[0].ID)
+	assert.Equal(t, "kot", trash.Translations[0].PolishWord.Text)
+	assert.Equal(t, "cat", trash.Translations[0].EnglishWord.Text)
+	assert.Len(t, trash.Examples, 2)
+	assert.Equal(t, trash.PolishWords[0].DeletedAt, trash.Examples[0].DeletedAt)
+
+	restored, err := manager.RestoreFromTrash(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.NoError(t, err)
+	assert.Equal(t, "kot", restored.(*dbModels.PolishWord).Text)
+	assert.False(t, restored.(*dbModels.PolishWord).DeletedAt.Valid)
+	populated := &dbModels.Translation{ID: translation.ID}
+	assert.NoError(t, manager.PopulateTranslationWithAssociations(populated))
+	assert.Len(t, populated.Examples, 2)
+	tags, _ = manager.GetTagsByTranslationIds([]uint{translation.ID})
+	assert.Len(t, tags, 1)
+	_, err = manager.GetTranslationById(kitten.ID)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+
+	_, err = manager.RestoreFromTrash(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.Equal(t, customErrors.ErrNotInTrash, err)
+
+	// A deleted entry can be added again, which keeps it in the trash.
+	manager.DeleteRecordFromTable(dbModels.Example{}, populated.Examples[0].ID)
+	_, err = manager.AddExampleToTranslation(&model.ExampleInput{Text: "Ala ma kota", InPolish: true}, translation.ID)
+	assert.NoError(t, err)
+	_, err = manager.RestoreFromTrash(dbModels.Example{}, populated.Examples[0].ID)
+	assert.Equal(t, customErrors.ErrExampleAlreadyExists, err)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	defer clearTestDB(manager.db)
+
+	translation, _ := manager.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	manager.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{{Text: "kota"}}, false)
+	manager.AddPolishWord(dbModels.PolishWord{Text: "pies"})
+	assert.NoError(t, manager.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID))
+
+	past := time.Now().Add(-time.Hour)
+	purged, err := manager.PurgeTrash(&past)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	purged, err = manager.PurgeTrash(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	trash, _ := manager.GetTrash()
+	assert.Empty(t, trash.PolishWords)
+	assert.Empty(t, trash.Translations)
+	var forms int64
+	manager.db.Model(&dbModels.WordForm{}).Count(&forms)
+	assert.Equal(t, int64(0), forms)
+	_, err = manager.RestoreFromTrash(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.Equal(t, customErrors.ErrNotInTrash, err)
+
+	words, _ := manager.GetPolishWords()
+	assert.Len(t, words, 1)
+	englishWords, _ := manager.GetEnglishWords()
+	assert.Len(t, englishWords, 1)
+}
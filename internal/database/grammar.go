@@ -0,0 +1,51 @@
+package database
+
+import (
+	"github.com/realagmag/dictionaryGO/graph/model"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// TranslationWords returns the words a translation input refers to. The part
+// of speech applies to both of them, gender and aspect to the Polish one.
+func TranslationWords(translationInput model.TranslationInput) (dbModels.PolishWord, dbModels.EnglishWord) {
+	partOfSpeech := EnumValue(translationInput.PartOfSpeech)
+	return dbModels.PolishWord{
+		Text:         translationInput.PolishWord,
+		PartOfSpeech: partOfSpeech,
+		Gender:       EnumValue(translationInput.PolishGender),
+		Aspect:       EnumValue(translationInput.PolishAspect),
+	}, dbModels.EnglishWord{
+		Text:         translationInput.EnglishWord,
+		PartOfSpeech: partOfSpeech,
+	}
+}
+
+// ValidateGrammar checks that word has a gender only when it is a noun and an
+// aspect only when it is a verb.
+func ValidateGrammar(word dbModels.PolishWord) error {
+	if word.Gender != "" && word.PartOfSpeech != string(model.PartOfSpeechNoun) {
+		return customErrors.ErrGenderWithoutNoun
+	}
+	if word.Aspect != "" && word.PartOfSpeech != string(model.PartOfSpeechVerb) {
+		return customErrors.ErrAspectWithoutVerb
+	}
+	return nil
+}
+
+// MatchGrammar checks that word, added again as stored, gives no gender or
+// aspect other than the stored ones.
+func MatchGrammar(stored, word dbModels.PolishWord) error {
+	if (word.Gender != "" && word.Gender != stored.Gender) || (word.Aspect != "" && word.Aspect != stored.Aspect) {
+		return customErrors.ErrGrammarMismatch
+	}
+	return nil
+}
+
+// EnumValue is the stored form of an optional GraphQL enum, empty if unset.
+func EnumValue[T ~string](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return string(*value)
+}
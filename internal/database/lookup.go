@@ -0,0 +1,210 @@
+package database
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// LookupOptions relax how a looked up word is compared with the stored ones.
+// The zero value asks for an exact match. Non-empty grammatical values only
+// keep the words having them, Gender and Aspect apply to Polish words only.
+// IncludeForms also finds Polish words through their inflected forms.
+type LookupOptions struct {
+	IgnoreCase     bool
+	FoldDiacritics bool
+	IncludeForms   bool
+	PartOfSpeech   string
+	Gender         string
+	Aspect         string
+}
+
+// Matches reports whether the stored text is found when looking up query.
+func (options LookupOptions) Matches(text, query string) bool {
+	switch {
+	case options.FoldDiacritics && options.IgnoreCase:
+		return normalize.FoldedKey(text) == normalize.FoldedKey(query)
+	case options.FoldDiacritics:
+		return normalize.Fold(normalize.Text(text)) == normalize.Fold(normalize.Text(query))
+	case options.IgnoreCase:
+		return normalize.Key(text) == normalize.Key(query)
+	default:
+		return text == query
+	}
+}
+
+// MatchesPolishWord reports whether word is found when looking up query.
+func (options LookupOptions) MatchesPolishWord(word dbModels.PolishWord, query string) bool {
+	return options.Matches(word.Text, query) &&
+		matchesGrammar(options.PartOfSpeech, word.PartOfSpeech) &&
+		matchesGrammar(options.Gender, word.Gender) &&
+		matchesGrammar(options.Aspect, word.Aspect)
+}
+
+// MatchesEnglishWord reports whether word is found when looking up query.
+func (options LookupOptions) MatchesEnglishWord(word dbModels.EnglishWord, query string) bool {
+	return options.Matches(word.Text, query) && matchesGrammar(options.PartOfSpeech, word.PartOfSpeech)
+}
+
+// MatchesPolishForm reports whether word is found through its form when
+// looking up query.
+func (options LookupOptions) MatchesPolishForm(word dbModels.PolishWord, form dbModels.WordForm, query string) bool {
+	return options.IncludeForms && form.PolishWordID == word.ID &&
+		options.MatchesPolishWord(dbModels.PolishWord{
+			Text:         form.Text,
+			PartOfSpeech: word.PartOfSpeech,
+			Gender:       word.Gender,
+			Aspect:       word.Aspect,
+		}, query)
+}
+
+func matchesGrammar(wanted, value string) bool {
+	return wanted == "" || wanted == value
+}
+
+// whereGrammar keeps the words having the requested grammatical values.
+func (options LookupOptions) whereGrammar(query *gorm.DB) *gorm.DB {
+	for column, value := range map[string]string{
+		"part_of_speech": options.PartOfSpeech,
+		"gender":         options.Gender,
+		"aspect":         options.Aspect,
+	} {
+		if value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	return query
+}
+
+// whereKey narrows query to the rows whose text may match, using the indexed
+// key columns. Case-sensitive folding has no column of its own, its
+// candidates are checked with Matches afterwards.
+func (options LookupOptions) whereKey(query *gorm.DB, word string) *gorm.DB {
+	switch {
+	case options.FoldDiacritics:
+		return query.Where("folded_key = ?", normalize.FoldedKey(word))
+	case options.IgnoreCase:
+		return query.Where("search_key = ?", normalize.Key(word))
+	default:
+		return query.Where("text = ?", word)
+	}
+}
+
+// lookupWordIds returns the IDs of the words of model's table matching word.
+func lookupWordIds(db *gorm.DB, model interface{}, word string, options LookupOptions) ([]uint, error) {
+	var candidates []struct {
+		ID   uint
+		Text string
+	}
+	query := options.whereKey(options.whereGrammar(db.Model(model)), word)
+	if err := query.Select("id", "text").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	ids := []uint{}
+	for _, candidate := range candidates {
+		if options.Matches(candidate.Text, word) {
+			ids = append(ids, candidate.ID)
+		}
+	}
+	return ids, nil
+}
+
+// lookupPolishWordIds returns the IDs of the Polish words matching word,
+// including the ones with a matching form when options ask for it.
+func lookupPolishWordIds(db *gorm.DB, word string, options LookupOptions) ([]uint, error) {
+	ids, err := lookupWordIds(db, &dbModels.PolishWord{}, word, options)
+	if err != nil || !options.IncludeForms {
+		return ids, err
+	}
+	var forms []struct {
+		PolishWordID uint
+		Text         string
+	}
+	words := options.whereGrammar(db.Model(&dbModels.PolishWord{})).Select("id")
+	query := options.whereKey(db.Model(&dbModels.WordForm{}), word).Where("polish_word_id IN (?)", words)
+	if err := query.Select("polish_word_id", "text").Find(&forms).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	for _, form := range forms {
+		if !seen[form.PolishWordID] && options.Matches(form.Text, word) {
+			seen[form.PolishWordID] = true
+			ids = append(ids, form.PolishWordID)
+		}
+	}
+	return ids, nil
+}
+
+// lookupFlushInterval is how long lookups are counted in memory before the
+// counts are written.
+const lookupFlushInterval = time.Second
+
+// lookupCounter counts the lookups of words in memory and adds them to
+// lookup_count in batches, so looking up a word does not write to it.
+type lookupCounter struct {
+	db      *gorm.DB
+	mu      sync.Mutex
+	pending map[string]map[uint]uint
+	timer   *time.Timer
+}
+
+func newLookupCounter(db *gorm.DB) *lookupCounter {
+	return &lookupCounter{db: db, pending: map[string]map[uint]uint{}}
+}
+
+// add counts a lookup of the words of table, to be written within
+// lookupFlushInterval.
+func (counter *lookupCounter) add(table string, ids []uint) {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	counts := counter.pending[table]
+	if counts == nil {
+		counts = map[uint]uint{}
+		counter.pending[table] = counts
+	}
+	for _, id := range ids {
+		counts[id]++
+	}
+	if counter.timer == nil {
+		counter.timer = time.AfterFunc(lookupFlushInterval, func() {
+			if err := counter.flush(); err != nil {
+				log.Printf("failed to count lookups: %v", err)
+			}
+		})
+	}
+}
+
+// flush writes the pending counts, one update per table and count.
+func (counter *lookupCounter) flush() error {
+	counter.mu.Lock()
+	pending := counter.pending
+	counter.pending = map[string]map[uint]uint{}
+	if counter.timer != nil {
+		counter.timer.Stop()
+		counter.timer = nil
+	}
+	counter.mu.Unlock()
+	for table, counts := range pending {
+		byCount := map[uint][]uint{}
+		for id, count := range counts {
+			byCount[count] = append(byCount[count], id)
+		}
+		for count, ids := range byCount {
+			slices.Sort(ids)
+			err := counter.db.Table(table).Where("id IN ?", ids).
+				UpdateColumn("lookup_count", gorm.Expr("lookup_count + ?", count)).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
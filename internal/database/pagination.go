@@ -0,0 +1,176 @@
+package database
+
+import (
+	"sort"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+
+	// Bounds of the limit argument of the queries returning the best matches
+	// only, like suggestions.
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// PageRequest selects a window of records ordered by ID, following the Relay
+// connection arguments. After and Before are exclusive ID bounds, First takes
+// records from the start of the window and Last from its end. Without First
+// and Last the first DefaultPageSize records are returned.
+type PageRequest struct {
+	First  *int
+	Last   *int
+	After  *uint
+	Before *uint
+}
+
+type Page[T any] struct {
+	Items           []*T
+	HasNextPage     bool
+	HasPreviousPage bool
+	TotalCount      int64
+}
+
+func (page PageRequest) validate() error {
+	for _, size := range []*int{page.First, page.Last} {
+		if size != nil && (*size < 0 || *size > MaxPageSize) {
+			return customErrors.ErrInvalidPageSize
+		}
+	}
+	return nil
+}
+
+func ValidateLimit(limit int) error {
+	if limit < 1 || limit > MaxLimit {
+		return customErrors.ErrInvalidLimit
+	}
+	return nil
+}
+
+// forward reports whether records are taken from the start of the window.
+func (page PageRequest) forward() bool {
+	return page.First != nil || page.Last == nil
+}
+
+func (page PageRequest) limit() int {
+	switch {
+	case page.First != nil:
+		return *page.First
+	case page.Last != nil:
+		return *page.Last
+	default:
+		return DefaultPageSize
+	}
+}
+
+// trim applies Last to a window already cut down by First, as the Relay
+// specification asks when both are given.
+func trim[T any](items []*T, page PageRequest, result *Page[T]) []*T {
+	if page.First != nil && page.Last != nil && len(items) > *page.Last {
+		result.HasPreviousPage = true
+		return items[len(items)-*page.Last:]
+	}
+	return items
+}
+
+// paginate runs the keyset query for page on the table behind query. It
+// needs a constant number of queries regardless of the table size.
+func paginate[T any](query *gorm.DB, page PageRequest) (*Page[T], error) {
+	if err := page.validate(); err != nil {
+		return nil, err
+	}
+	result := &Page[T]{}
+	if err := query.Session(&gorm.Session{}).Model(new(T)).Count(&result.TotalCount).Error; err != nil {
+		return nil, err
+	}
+
+	window := query.Session(&gorm.Session{})
+	if page.After != nil {
+		window = window.Where("id > ?", *page.After)
+	}
+	if page.Before != nil {
+		window = window.Where("id < ?", *page.Before)
+	}
+	order := "id ASC"
+	if !page.forward() {
+		order = "id DESC"
+	}
+	var items []*T
+	if err := window.Order(order).Limit(page.limit() + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	hasMore := len(items) > page.limit()
+	if hasMore {
+		items = items[:page.limit()]
+	}
+
+	// Records beyond the cursors exist independently of the window size, ask
+	// for a single one of them.
+	var outside []*T
+	if page.forward() {
+		result.HasNextPage = hasMore
+		if page.After != nil {
+			if err := query.Session(&gorm.Session{}).Where("id <= ?", *page.After).Limit(1).Find(&outside).Error; err != nil {
+				return nil, err
+			}
+			result.HasPreviousPage = len(outside) > 0
+		}
+	} else {
+		result.HasPreviousPage = hasMore
+		if page.Before != nil {
+			if err := query.Session(&gorm.Session{}).Where("id >= ?", *page.Before).Limit(1).Find(&outside).Error; err != nil {
+				return nil, err
+			}
+			result.HasNextPage = len(outside) > 0
+		}
+		reverse(items)
+	}
+	result.Items = trim(items, page, result)
+	return result, nil
+}
+
+// PaginateSlice applies page to records held in memory. It mirrors the keyset
+// pagination of DBManager for stores without a database.
+func PaginateSlice[T any](records []*T, id func(*T) uint, page PageRequest) (*Page[T], error) {
+	if err := page.validate(); err != nil {
+		return nil, err
+	}
+	sorted := append([]*T(nil), records...)
+	sort.Slice(sorted, func(i, j int) bool { return id(sorted[i]) < id(sorted[j]) })
+
+	result := &Page[T]{TotalCount: int64(len(sorted))}
+	var window []*T
+	for _, record := range sorted {
+		switch {
+		case page.After != nil && id(record) <= *page.After:
+			result.HasPreviousPage = result.HasPreviousPage || page.forward()
+		case page.Before != nil && id(record) >= *page.Before:
+			result.HasNextPage = result.HasNextPage || !page.forward()
+		default:
+			window = append(window, record)
+		}
+	}
+
+	limit := page.limit()
+	if len(window) > limit {
+		if page.forward() {
+			result.HasNextPage = true
+			window = window[:limit]
+		} else {
+			result.HasPreviousPage = true
+			window = window[len(window)-limit:]
+		}
+	}
+	result.Items = trim(window, page, result)
+	return result, nil
+}
+
+func reverse[T any](items []*T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+)
+
+// QuizWord is a word a quiz may offer as a choice.
+type QuizWord struct {
+	ID           uint
+	Text         string
+	PartOfSpeech string
+}
+
+// sampleModulus is the prime sample keys are computed modulo, small enough
+// for their products to fit in 64 bits.
+const sampleModulus = 2147483647
+
+// sampleFactors derives the factors of the sample keys from seed, mixed so
+// that close seeds sample differently.
+func sampleFactors(seed uint64) (int64, int64) {
+	seed += 0x9e3779b97f4a7c15
+	seed = (seed ^ seed>>30) * 0xbf58476d1ce4e5b9
+	seed = (seed ^ seed>>27) * 0x94d049bb133111eb
+	seed ^= seed >> 31
+	return int64(seed%(sampleModulus-1)) + 1, int64(seed>>32%(sampleModulus-1)) + 1
+}
+
+// SampleKey orders the records with the ID pseudo-randomly for seed. The
+// databases compute the same key, so every store samples the same records.
+func SampleKey(id uint, seed uint64) int64 {
+	a, b := sampleFactors(seed)
+	return int64(id) * a % sampleModulus * ((int64(id) + b) % sampleModulus) % sampleModulus
+}
+
+func sampleOrder(seed uint64) string {
+	a, b := sampleFactors(seed)
+	return fmt.Sprintf("id * %d %% %d * ((id + %d) %% %d) %% %d, id", a, sampleModulus, b, sampleModulus, sampleModulus)
+}
+
+// SortQuizWords orders words for a question about a word: the ones with
+// partOfSpeech first, then the ones closest to length characters, ties
+// ordered pseudo-randomly by seed.
+func SortQuizWords(words []*QuizWord, partOfSpeech string, length int, seed uint64) {
+	rank := func(word *QuizWord) int {
+		if word.PartOfSpeech != partOfSpeech {
+			return 1
+		}
+		return 0
+	}
+	distance := func(word *QuizWord) int {
+		return max(utf8.RuneCountInString(word.Text)-length, length-utf8.RuneCountInString(word.Text))
+	}
+	slices.SortFunc(words, func(a, b *QuizWord) int {
+		return cmp.Or(
+			cmp.Compare(rank(a), rank(b)),
+			cmp.Compare(distance(a), distance(b)),
+			cmp.Compare(SampleKey(a.ID, seed), SampleKey(b.ID, seed)),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+}
+
+func (manager *DBManager) SampleTranslations(tag string, limit int, seed uint64) ([]*dbModels.Translation, error) {
+	query := manager.db.Model(&dbModels.Translation{})
+	if tag != "" {
+		query = query.Where("id IN (?)", manager.db.Model(&dbModels.TranslationTag{}).Select("translation_id").Where("name = ?", normalize.Tag(tag)))
+	}
+	translations := []*dbModels.Translation{}
+	if err := query.Order(sampleOrder(seed)).Limit(limit).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (manager *DBManager) GetQuizWords(language model.Language, seed uint64, limit int) ([]*QuizWord, error) {
+	var table interface{}
+	switch language {
+	case model.LanguagePolish:
+		table = &dbModels.PolishWord{}
+	case model.LanguageEnglish:
+		table = &dbModels.EnglishWord{}
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+	words := []*QuizWord{}
+	if err := manager.db.Model(table).
+		Select("id", "text", "part_of_speech").
+		Order(sampleOrder(seed)).
+		Limit(limit).
+		Find(&words).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"fmt"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (manager *DBManager) AddRevision(revision interface{}) error {
+	var (
+		column   string
+		entityID uint
+		entity   interface{}
+		unique   constraint
+	)
+	switch revision := revision.(type) {
+	case *dbModels.PolishWordRevision:
+		column, entityID, entity, unique = "polish_word_id", revision.PolishWordID, &dbModels.PolishWord{}, polishWordRevisionUnique
+	case *dbModels.EnglishWordRevision:
+		column, entityID, entity, unique = "english_word_id", revision.EnglishWordID, &dbModels.EnglishWord{}, englishWordRevisionUnique
+	case *dbModels.TranslationRevision:
+		column, entityID, entity, unique = "translation_id", revision.TranslationID, &dbModels.Translation{}, translationRevisionUnique
+	case *dbModels.ExampleRevision:
+		column, entityID, entity, unique = "example_id", revision.ExampleID, &dbModels.Example{}, exampleRevisionUnique
+	default:
+		return fmt.Errorf("database: unsupported revision %T", revision)
+	}
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		// Concurrent revisions of the entity would read the same latest
+		// version, locking it makes them wait for each other.
+		if err := (&DBManager{db: tx, lookups: manager.lookups}).LockRecord(entity, entityID); err != nil {
+			return err
+		}
+		var latest uint
+		err := tx.Model(revision).Where(column+" = ?", entityID).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		meta := revision.(interface{ Meta() *dbModels.Revision }).Meta()
+		meta.Version = latest + 1
+		return tx.Create(revision).Error
+	})
+	// Only a purged entity is not locked
+	if err != nil && unique.violatedBy(err) {
+		return customErrors.ErrVersionConflict
+	}
+	return err
+}
+
+func (manager *DBManager) GetPolishWordRevisions(polishWordID uint) ([]*dbModels.PolishWordRevision, error) {
+	return findRevisions[dbModels.PolishWordRevision](manager, "polish_word_id = ?", polishWordID)
+}
+
+func (manager *DBManager) GetEnglishWordRevisions(englishWordID uint) ([]*dbModels.EnglishWordRevision, error) {
+	return findRevisions[dbModels.EnglishWordRevision](manager, "english_word_id = ?", englishWordID)
+}
+
+func (manager *DBManager) GetTranslationRevisions(translationID uint) ([]*dbModels.TranslationRevision, error) {
+	return findRevisions[dbModels.TranslationRevision](manager, "translation_id = ?", translationID)
+}
+
+func (manager *DBManager) GetExampleRevisions(exampleID uint) ([]*dbModels.ExampleRevision, error) {
+	return findRevisions[dbModels.ExampleRevision](manager, "example_id = ?", exampleID)
+}
+
+func (manager *DBManager) GetTranslationRevisionsByDeletion(deletedWith string) ([]*dbModels.TranslationRevision, error) {
+	return findRevisions[dbModels.TranslationRevision](manager, "deleted_with = ?", deletedWith)
+}
+
+func (manager *DBManager) GetExampleRevisionsByDeletion(deletedWith string) ([]*dbModels.ExampleRevision, error) {
+	return findRevisions[dbModels.ExampleRevision](manager, "deleted_with = ?", deletedWith)
+}
+
+func findRevisions[T any](manager *DBManager, condition string, value any) ([]*T, error) {
+	revisions := []*T{}
+	if err := manager.db.Where(condition, value).Order("id").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (manager *DBManager) RestoreRecord(record interface{}) error {
+	switch record := record.(type) {
+	case *dbModels.PolishWord:
+		err := manager.restore(record, record.ID, &record.Version, "text", "part_of_speech", "gender", "aspect", "search_key", "folded_key")
+		if err != nil && polishWordTextUnique.violatedBy(err) {
+			return customErrors.ErrPolishWordAlreadyExists
+		}
+		return err
+	case *dbModels.EnglishWord:
+		err := manager.restore(record, record.ID, &record.Version, "text", "part_of_speech", "search_key", "folded_key")
+		if err != nil && englishWordTextUnique.violatedBy(err) {
+			return customErrors.ErrEnglishWordAlreadyExists
+		}
+		return err
+	case *dbModels.Translation:
+		if _, err := manager.GetPolishWordById(record.PolishWordID); err != nil {
+			return err
+		}
+		if _, err := manager.GetEnglishWordById(record.EnglishWordID); err != nil {
+			return err
+		}
+		err := manager.restore(record, record.ID, nil, "polish_word_id", "english_word_id")
+		if err != nil && translationWordsUnique.violatedBy(err) {
+			return customErrors.ErrTranslationAlreadyExists
+		}
+		return err
+	case *dbModels.Example:
+		if _, err := manager.GetTranslationById(record.TranslationID); err != nil {
+			return err
+		}
+		err := manager.restore(record, record.ID, &record.Version, "translation_id", "text", "in_polish", "search_words")
+		if err != nil && exampleTextUnique.violatedBy(err) {
+			return customErrors.ErrExampleAlreadyExists
+		}
+		return err
+	default:
+		return fmt.Errorf("database: unsupported record %T", record)
+	}
+}
+
+// restore updates the columns of record, taking it out of the trash, or
+// creates record with its ID when there is no record with it. version is the
+// Version of a record that has one, set to the one following the stored.
+func (manager *DBManager) restore(record interface{}, id uint, version *uint, columns ...string) error {
+	var count int64
+	if err := manager.db.Unscoped().Model(record).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		if version != nil {
+			*version = 1
+		}
+		return manager.db.Omit(clause.Associations).Create(record).Error
+	}
+	columns = append(columns, "deleted_at")
+	if version != nil {
+		var stored uint
+		if err := manager.db.Unscoped().Model(record).Where("id = ?", id).Select("version").Scan(&stored).Error; err != nil {
+			return err
+		}
+		*version = stored + 1
+		columns = append(columns, "version")
+	}
+	return manager.db.Unscoped().Model(record).Select(columns).Updates(record).Error
+}
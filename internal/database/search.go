@@ -0,0 +1,235 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// Markers wrapped around the matched words of a snippet.
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// ExampleMatch is an example found by a full-text search. Snippet is its text
+// with the matched words highlighted, Rank grows with the relevance.
+type ExampleMatch struct {
+	ID            uint
+	TranslationID uint
+	Text          string
+	InPolish      bool
+	Version       uint
+	Snippet       string
+	Rank          float64
+}
+
+// searchConfigs maps the example languages to the Postgres text search
+// configurations their search_vector is built with.
+var searchConfigs = []struct {
+	inPolish bool
+	filter   string
+	config   string
+}{
+	{true, "in_polish", "polish"},
+	{false, "NOT in_polish", "english"},
+}
+
+const exampleSearchConfig = "CASE WHEN in_polish THEN 'polish'::regconfig ELSE 'english'::regconfig END"
+
+// searchExamplesPostgres runs the search on the search_vector index. Each
+// language is matched with its own configuration, so the condition is split
+// per language instead of computing the configuration per row. Matches are
+// ordered by ID rather than rank, the cursors of the page are IDs.
+func searchExamplesPostgres(db *gorm.DB, query string, language *model.Language, page PageRequest) (*Page[ExampleMatch], error) {
+	if err := page.validate(); err != nil {
+		return nil, err
+	}
+	conditions := []string{}
+	args := []interface{}{}
+	for _, search := range searchConfigs {
+		if language != nil && (*language == model.LanguagePolish) != search.inPolish {
+			continue
+		}
+		conditions = append(conditions, "("+search.filter+" AND search_vector @@ websearch_to_tsquery('"+search.config+"', ?))")
+		args = append(args, query)
+	}
+	matching := db.Session(&gorm.Session{}).Model(&ExampleMatch{}).Table("examples").
+		Where("deleted_at IS NULL").
+		Where("("+strings.Join(conditions, " OR ")+")", args...)
+
+	result := &Page[ExampleMatch]{}
+	if err := matching.Session(&gorm.Session{}).Count(&result.TotalCount).Error; err != nil {
+		return nil, err
+	}
+	window := matching.Session(&gorm.Session{})
+	if page.After != nil {
+		window = window.Where("id > ?", *page.After)
+		var outside []*ExampleMatch
+		if err := matching.Session(&gorm.Session{}).Select("id").Where("id <= ?", *page.After).Limit(1).Find(&outside).Error; err != nil {
+			return nil, err
+		}
+		result.HasPreviousPage = len(outside) > 0
+	}
+	tsquery := "websearch_to_tsquery(" + exampleSearchConfig + ", ?)"
+	var items []*ExampleMatch
+	if err := window.
+		Select("id, translation_id, text, in_polish, version, "+
+			"ts_headline("+exampleSearchConfig+", text, "+tsquery+", ?) AS snippet, "+
+			"ts_rank(search_vector, "+tsquery+") AS rank",
+			query, "StartSel="+HighlightStart+", StopSel="+HighlightStop, query).
+		Order("id").
+		Limit(page.limit() + 1).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) > page.limit() {
+		result.HasNextPage = true
+		items = items[:page.limit()]
+	}
+	result.Items = items
+	return result, nil
+}
+
+// searchExampleWords is the search of databases without full-text search.
+// It finds the examples MatchExample matches with a LIKE on their word keys
+// per word of query, and highlights only the page it returns.
+func searchExampleWords(db *gorm.DB, query string, language *model.Language, page PageRequest) (*Page[ExampleMatch], error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return PaginateSlice([]*ExampleMatch{}, func(match *ExampleMatch) uint { return match.ID }, page)
+	}
+	matching := db.Model(&dbModels.Example{})
+	if language != nil {
+		matching = matching.Where("in_polish = ?", *language == model.LanguagePolish)
+	}
+	for _, term := range terms {
+		matching = matching.Where(`search_words LIKE ? ESCAPE '\'`, "% "+likeEscaper.Replace(term)+"%")
+	}
+	examples, err := paginate[dbModels.Example](matching, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Page[ExampleMatch]{
+		Items:           make([]*ExampleMatch, len(examples.Items)),
+		HasNextPage:     examples.HasNextPage,
+		HasPreviousPage: examples.HasPreviousPage,
+		TotalCount:      examples.TotalCount,
+	}
+	for i, example := range examples.Items {
+		match, ok := MatchExample(example.Text, query)
+		if !ok {
+			// Only word keys saved under other rules disagree with MatchExample
+			match = &ExampleMatch{Text: example.Text, Snippet: example.Text}
+		}
+		match.ID, match.TranslationID, match.InPolish, match.Version = example.ID, example.TranslationID, example.InPolish, example.Version
+		result.Items[i] = match
+	}
+	return result, nil
+}
+
+// searchTerms returns the keys of the words of query the search matches.
+func searchTerms(query string) []string {
+	terms := []string{}
+	for _, term := range splitWords(query) {
+		key := normalize.Key(query[term.start:term.end])
+		// websearch_to_tsquery operators, there is no support for them here
+		if key == "or" {
+			continue
+		}
+		terms = append(terms, key)
+	}
+	return terms
+}
+
+// MatchExample is the full-text search used where Postgres is not available.
+// Every word of query has to start a word of text, ignoring case, which
+// roughly stands in for the stemming of the text search configurations.
+func MatchExample(text, query string) (*ExampleMatch, bool) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, false
+	}
+
+	words := splitWords(text)
+	matched := make([]bool, len(words))
+	matchedCount := 0
+	for _, term := range terms {
+		found := false
+		for i, word := range words {
+			if strings.HasPrefix(normalize.Key(text[word.start:word.end]), term) {
+				if !matched[i] {
+					matchedCount++
+				}
+				matched[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	var snippet strings.Builder
+	last := 0
+	for i, word := range words {
+		if !matched[i] {
+			continue
+		}
+		snippet.WriteString(text[last:word.start])
+		snippet.WriteString(HighlightStart + text[word.start:word.end] + HighlightStop)
+		last = word.end
+	}
+	snippet.WriteString(text[last:])
+	return &ExampleMatch{
+		Text:    text,
+		Snippet: snippet.String(),
+		Rank:    float64(matchedCount) / float64(len(words)),
+	}, true
+}
+
+// SearchExampleSlice runs MatchExample on every example of language, nil
+// meaning both, and pages the matches by ID.
+func SearchExampleSlice(examples []*ExampleMatch, query string, language *model.Language, page PageRequest) (*Page[ExampleMatch], error) {
+	matches := []*ExampleMatch{}
+	for _, example := range examples {
+		if language != nil && (*language == model.LanguagePolish) != example.InPolish {
+			continue
+		}
+		if match, ok := MatchExample(example.Text, query); ok {
+			match.ID = example.ID
+			match.TranslationID = example.TranslationID
+			match.InPolish = example.InPolish
+			match.Version = example.Version
+			matches = append(matches, match)
+		}
+	}
+	return PaginateSlice(matches, func(match *ExampleMatch) uint { return match.ID }, page)
+}
+
+type span struct {
+	start, end int
+}
+
+func splitWords(text string) []span {
+	spans := []span{}
+	start := -1
+	for i, r := range text {
+		isWordRune := normalize.IsWordRune(r)
+		switch {
+		case isWordRune && start < 0:
+			start = i
+		case !isWordRune && start >= 0:
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// DictionaryStore is the storage contract the GraphQL layer depends on.
+// Implementations must return the sentinel errors from internal/errors so
+// callers can react to them regardless of the backend.
+type DictionaryStore interface {
+	// AddPolishWord and AddEnglishWord return the stored word with the same
+	// text and part of speech when there is one, the ID of word is ignored.
+	// AddPolishWord, and AddTranslation for its Polish word, fail with
+	// ErrGenderWithoutNoun or ErrAspectWithoutVerb for grammar the part of
+	// speech does not have, and with ErrGrammarMismatch when the stored word
+	// has another gender or aspect than the one given.
+	AddPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error)
+	AddEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error)
+	GetPolishWords() ([]*dbModels.PolishWord, error)
+	GetEnglishWords() ([]*dbModels.EnglishWord, error)
+	GetPolishWordsPage(page PageRequest) (*Page[dbModels.PolishWord], error)
+	GetEnglishWordsPage(page PageRequest) (*Page[dbModels.EnglishWord], error)
+
+	// SetPolishWordForms adds forms to the Polish word, skipping the ones it
+	// already has, after removing its current forms when replace is set. It
+	// returns all forms of the word.
+	SetPolishWordForms(polishWordID uint, forms []dbModels.WordForm, replace bool) ([]*dbModels.WordForm, error)
+
+	AddTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error)
+	AddExampleToTranslation(example *model.ExampleInput, translationID uint) (*dbModels.Example, error)
+	PopulateTranslationWithAssociations(translation *dbModels.Translation) error
+	PopulateTranslationsWithAssociations(translations []*dbModels.Translation) error
+	GetTranslations() ([]*dbModels.Translation, error)
+	GetTranslationsPage(page PageRequest) (*Page[dbModels.Translation], error)
+	// FindTranslation returns the stored translation AddTranslation would
+	// reuse for translationInput, or ErrTranslationNotFound.
+	FindTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error)
+	// FindPolishWord, FindEnglishWord and FindExample return the stored entry
+	// AddPolishWord, AddEnglishWord and AddExampleToTranslation would reuse,
+	// or ErrPolishWordNotFound, ErrEnglishWordNotFound and ErrExampleNotFound.
+	FindPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error)
+	FindEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error)
+	FindExample(translationID uint, text string) (*dbModels.Example, error)
+	// SetTranslationTags replaces the tags of the translation, names are
+	// normalized and blank or repeated ones skipped. It returns the tags of
+	// the translation ordered by name.
+	SetTranslationTags(translationID uint, tags []string) ([]*dbModels.TranslationTag, error)
+	// GetTranslationsByTag returns the translations tagged tag ordered by ID.
+	GetTranslationsByTag(tag string) ([]*dbModels.Translation, error)
+	// GetTranslationsByPolishWordIds and GetTranslationsByEnglishWordIds
+	// return the translations of the given words ordered by ID.
+	GetTranslationsByPolishWordIds(ids []uint) ([]*dbModels.Translation, error)
+	GetTranslationsByEnglishWordIds(ids []uint) ([]*dbModels.Translation, error)
+	// GetTranslationsToEnglish and GetTranslationsToPolish count a lookup of
+	// the matched words. The count is the one write of these reads and may
+	// be made after they return, DBManager adds the counts in batches within
+	// a second, so the LookupCount of autocompletion can lag behind.
+	GetTranslationsToEnglish(wordInPolish string, options LookupOptions) ([]*dbModels.Translation, error)
+	GetTranslationsToPolish(wordInEnglish string, options LookupOptions) ([]*dbModels.Translation, error)
+	GetSuggestions(word string, language model.Language, limit int) ([]*Suggestion, error)
+	// SearchExamples pages the examples matching query by ID like the other
+	// pages, their Rank tells the relevance without ordering them.
+	SearchExamples(query string, language *model.Language, page PageRequest) (*Page[ExampleMatch], error)
+	Autocomplete(prefix string, language model.Language, ranking model.AutocompleteRanking, limit int) ([]*WordEntry, error)
+	// SampleTranslations returns up to limit translations, only the ones
+	// tagged tag unless it is empty, picked pseudo-randomly by seed. The same
+	// seed picks the same translations from the same data.
+	SampleTranslations(tag string, limit int, seed uint64) ([]*dbModels.Translation, error)
+	// GetQuizWords returns up to limit words of language, picked
+	// pseudo-randomly by seed like SampleTranslations. Quizzes rank them for
+	// each question with SortQuizWords.
+	GetQuizWords(language model.Language, seed uint64, limit int) ([]*QuizWord, error)
+
+	// GetDueCards returns at most limit cards of learner in the given
+	// directions to review at now: the cards due by then, earliest first,
+	// followed by new cards for the translations never reviewed in a
+	// direction, ordered by translation and direction. New cards have no ID.
+	// Cards come with their Translation.
+	GetDueCards(learner string, directions []string, now time.Time, limit int) ([]*dbModels.ReviewCard, error)
+	// ReviewCard schedules the card of learner for the translation and
+	// direction after an answer graded grade at now, creating the card on its
+	// first review. It returns the card with its Translation.
+	ReviewCard(learner string, translationID uint, direction string, grade int, now time.Time) (*dbModels.ReviewCard, error)
+
+	// AddUser stores user, returning ErrUserAlreadyExists when its username
+	// is taken.
+	AddUser(user dbModels.User) (*dbModels.User, error)
+	GetUserById(id uint) (*dbModels.User, error)
+	GetUserByUsername(username string) (*dbModels.User, error)
+	SetUserRole(id uint, role string) (*dbModels.User, error)
+	AddAPIKey(key dbModels.APIKey) (*dbModels.APIKey, error)
+	// GetAPIKeyByHash returns the key with the hash and its User.
+	GetAPIKeyByHash(hash string) (*dbModels.APIKey, error)
+	GetAPIKeysByUserId(userID uint) ([]*dbModels.APIKey, error)
+	// DeleteAPIKey removes a key of the user, keys of other users are not
+	// found.
+	DeleteAPIKey(userID uint, id uint) error
+
+	// AddAuditEntry appends entry to the audit log, GetAuditLogPage returns
+	// the entries matching filter ordered by ID, oldest first.
+	AddAuditEntry(entry dbModels.AuditEntry) (*dbModels.AuditEntry, error)
+	GetAuditLogPage(filter AuditFilter, page PageRequest) (*Page[dbModels.AuditEntry], error)
+
+	// AddRevision stores a revision of a word, translation or example as the
+	// next version of its entity, setting its Version. The GetXRevisions
+	// methods return the revisions of an entity, or the ones with the given
+	// DeletedWith, in the order they were added.
+	AddRevision(revision interface{}) error
+	GetPolishWordRevisions(polishWordID uint) ([]*dbModels.PolishWordRevision, error)
+	GetEnglishWordRevisions(englishWordID uint) ([]*dbModels.EnglishWordRevision, error)
+	GetTranslationRevisions(translationID uint) ([]*dbModels.TranslationRevision, error)
+	GetExampleRevisions(exampleID uint) ([]*dbModels.ExampleRevision, error)
+	GetTranslationRevisionsByDeletion(deletedWith string) ([]*dbModels.TranslationRevision, error)
+	GetExampleRevisionsByDeletion(deletedWith string) ([]*dbModels.ExampleRevision, error)
+	// RestoreRecord stores a word, translation or example under its ID,
+	// recreating it when it was deleted. It returns the errors AddTranslation
+	// and the ChangeXText methods return for missing or taken references.
+	RestoreRecord(record interface{}) error
+
+	// DeleteRecordFromTable moves a word, translation or example to the trash
+	// together with the translations and examples beneath it. The other
+	// methods leave the trash out, except GetTrash. RestoreFromTrash takes a
+	// record out of the trash with what its deletion moved there and returns
+	// it, or ErrNotInTrash. PurgeTrash deletes what is in the trash for good,
+	// what was deleted before olderThan when given, and returns how many
+	// entries it deleted.
+	DeleteRecordFromTable(table interface{}, id uint) error
+	GetTrash() (*Trash, error)
+	RestoreFromTrash(table interface{}, id uint) (interface{}, error)
+	PurgeTrash(olderThan *time.Time) (int64, error)
+
+	// WithinTransaction runs fn against a store whose changes are kept only
+	// when fn returns nil. fn must not use the receiver itself meanwhile.
+	WithinTransaction(fn func(store DictionaryStore) error) error
+	// LockRecord locks a word, translation or example, deleted or not, until
+	// the transaction of the store ends, so the transactions reading it
+	// before changing it wait for each other. Missing records are skipped.
+	LockRecord(table interface{}, id uint) error
+
+	// The Change methods increment the version of the entry. They fail with
+	// ErrVersionConflict when expectedVersion is set and the entry has another
+	// one, concurrent changes without it all succeed one after another.
+	ChangeExampleText(id uint, text string, expectedVersion *uint) (*dbModels.Example, error)
+	ChangePolishWordText(id uint, text string, expectedVersion *uint) (*dbModels.PolishWord, error)
+	ChangeEnglishWordText(id uint, text string, expectedVersion *uint) (*dbModels.EnglishWord, error)
+
+	GetPolishWordById(id uint) (*dbModels.PolishWord, error)
+	GetEnglishWordById(id uint) (*dbModels.EnglishWord, error)
+	GetExampleById(id uint) (*dbModels.Example, error)
+	GetTranslationById(id uint) (*dbModels.Translation, error)
+
+	// Batch lookups for the GraphQL dataloaders. Missing IDs are skipped, the
+	// order of the result is unspecified.
+	GetPolishWordsByIds(ids []uint) ([]*dbModels.PolishWord, error)
+	GetEnglishWordsByIds(ids []uint) ([]*dbModels.EnglishWord, error)
+	GetExamplesByTranslationIds(ids []uint) ([]*dbModels.Example, error)
+	GetWordFormsByPolishWordIds(ids []uint) ([]*dbModels.WordForm, error)
+	GetTagsByTranslationIds(ids []uint) ([]*dbModels.TranslationTag, error)
+}
+
+var _ DictionaryStore = (*DBManager)(nil)
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"slices"
+	"time"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/srs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ValidateStudy checks the arguments shared by GetDueCards and ReviewCard.
+func ValidateStudy(learner string, directions []string) error {
+	if learner == "" {
+		return customErrors.ErrLearnerRequired
+	}
+	for _, direction := range directions {
+		if err := srs.ValidateDirection(direction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewCards builds the new cards of learner, unreviewed lists the IDs of the
+// translations never reviewed in each direction. Cards are ordered by
+// translation and then by the order of directions, at most limit of them.
+func NewCards(learner string, directions []string, unreviewed map[string][]uint, now time.Time, limit int) []*dbModels.ReviewCard {
+	var ids []uint
+	for _, direction := range directions {
+		ids = append(ids, unreviewed[direction]...)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	cards := []*dbModels.ReviewCard{}
+	for _, id := range ids {
+		for _, direction := range directions {
+			if len(cards) == limit {
+				return cards
+			}
+			if slices.Contains(unreviewed[direction], id) {
+				card := srs.NewCard(learner, id, direction, now)
+				cards = append(cards, &card)
+			}
+		}
+	}
+	return cards
+}
+
+func (manager *DBManager) GetDueCards(learner string, directions []string, now time.Time, limit int) ([]*dbModels.ReviewCard, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	if err := ValidateStudy(learner, directions); err != nil {
+		return nil, err
+	}
+
+	cards := []*dbModels.ReviewCard{}
+	if err := manager.db.Preload("Translation").
+		Where("learner = ? AND direction IN ? AND due_at <= ?", learner, directions, now.UTC()).
+		Where("translation_id IN (?)", manager.db.Model(&dbModels.Translation{}).Select("id")).
+		Order("due_at, id").Limit(limit).Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	if len(cards) == limit {
+		return cards, nil
+	}
+
+	unreviewed := make(map[string][]uint, len(directions))
+	var translations []*dbModels.Translation
+	for _, direction := range directions {
+		var batch []*dbModels.Translation
+		if err := manager.db.
+			Where("NOT EXISTS (SELECT 1 FROM review_cards WHERE review_cards.translation_id = translations.id AND learner = ? AND direction = ?)", learner, direction).
+			Order("id").Limit(limit - len(cards)).Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		for _, translation := range batch {
+			unreviewed[direction] = append(unreviewed[direction], translation.ID)
+		}
+		translations = append(translations, batch...)
+	}
+	newCards := NewCards(learner, directions, unreviewed, now, limit-len(cards))
+	for _, card := range newCards {
+		index := slices.IndexFunc(translations, func(translation *dbModels.Translation) bool {
+			return translation.ID == card.TranslationID
+		})
+		card.Translation = *translations[index]
+	}
+	return append(cards, newCards...), nil
+}
+
+func (manager *DBManager) ReviewCard(learner string, translationID uint, direction string, grade int, now time.Time) (*dbModels.ReviewCard, error) {
+	if err := ValidateStudy(learner, []string{direction}); err != nil {
+		return nil, err
+	}
+	if err := srs.ValidateGrade(grade); err != nil {
+		return nil, err
+	}
+
+	var card dbModels.ReviewCard
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		var translation dbModels.Translation
+		if err := tx.Limit(1).Find(&translation, translationID).Error; err != nil {
+			return err
+		}
+		if translation.ID == 0 {
+			return customErrors.ErrTranslationNotFound
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("learner = ? AND translation_id = ? AND direction = ?", learner, translationID, direction).
+			Limit(1).Find(&card).Error; err != nil {
+			return err
+		}
+		if card.ID == 0 {
+			card = srs.NewCard(learner, translationID, direction, now)
+		}
+		if err := srs.Review(&card, grade, now); err != nil {
+			return err
+		}
+		if err := tx.Omit("Translation").Save(&card).Error; err != nil {
+			return err
+		}
+		card.Translation = translation
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// Words up to this length may contain a single typo.
+const shortWordLength = 4
+
+// Suggestion is a stored word close to a looked up one. Distance is the
+// number of edits between their folded keys, Score scales it to 0..1 where 1
+// means the words only differ in case or diacritics.
+type Suggestion struct {
+	ID       uint
+	Text     string
+	Distance int
+	Score    float64
+}
+
+// maxSuggestionDistance is how many typos a word of length runes may contain
+// and still be suggested.
+func maxSuggestionDistance(length int) int {
+	switch {
+	case length <= shortWordLength:
+		return 1
+	case length <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// RankSuggestions keeps the candidates close enough to word and orders them
+// from the closest one, returning at most limit of them.
+func RankSuggestions(word string, candidates []Suggestion, limit int) ([]*Suggestion, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	key := []rune(normalize.FoldedKey(word))
+	maxDistance := maxSuggestionDistance(len(key))
+
+	suggestions := []*Suggestion{}
+	for _, candidate := range candidates {
+		candidateKey := []rune(normalize.FoldedKey(candidate.Text))
+		distance := editDistance(key, candidateKey)
+		if distance > maxDistance {
+			continue
+		}
+		suggestion := candidate
+		suggestion.Distance = distance
+		suggestion.Score = 1 - float64(distance)/float64(max(len(key), len(candidateKey), 1))
+		suggestions = append(suggestions, &suggestion)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// editDistance is the optimal string alignment distance: insertions,
+// deletions, substitutions and transpositions of adjacent letters each count
+// as one edit.
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+// trigramsPerEdit is how many trigrams of a word an edit changes at most, a
+// transposition of adjacent letters changes four.
+const trigramsPerEdit = 4
+
+// similarityThreshold is the lowest pg_trgm similarity a word of length runes
+// may have with one maxDistance edits away. pg_trgm counts length+1 trigrams
+// in a word, the edits change some of them in one word and as many in the
+// other. Zero means the trigrams cannot tell such words apart.
+func similarityThreshold(length, maxDistance int) float64 {
+	trigrams, changed := length+1, trigramsPerEdit*maxDistance
+	if trigrams <= changed {
+		return 0
+	}
+	return float64(trigrams-changed) / float64(trigrams+changed)
+}
+
+// suggestionCandidates loads the words of model's table that may be close to
+// word. Only the length of the folded key bounds the search, on Postgres the
+// trigram index narrows it down further for longer words. Its threshold is
+// lowered from the default of pg_trgm, which misses words with a few typos.
+func suggestionCandidates(db *gorm.DB, model interface{}, word string) ([]Suggestion, error) {
+	key := normalize.FoldedKey(word)
+	length := utf8.RuneCountInString(key)
+	maxDistance := maxSuggestionDistance(length)
+
+	var candidates []Suggestion
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(model).Select("id", "text").
+			Where("length(folded_key) BETWEEN ? AND ?", length-maxDistance, length+maxDistance)
+		if threshold := similarityThreshold(length, maxDistance); tx.Dialector.Name() == "postgres" && threshold > 0 {
+			// % keeps the words more similar than the threshold only
+			threshold = math.Floor(threshold*1000)/1000 - 0.001
+			if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", strconv.FormatFloat(threshold, 'f', 3, 64)).Error; err != nil {
+				return err
+			}
+			query = query.Where("folded_key % ?", key)
+		}
+		return query.Find(&candidates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"slices"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// TagNames normalizes tags, dropping blank and repeated ones, and sorts them.
+func TagNames(tags []string) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if name := normalize.Tag(tag); name != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func (manager *DBManager) SetTranslationTags(translationID uint, tags []string) ([]*dbModels.TranslationTag, error) {
+	var result []*dbModels.TranslationTag
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		var translation dbModels.Translation
+		if err := tx.Limit(1).Find(&translation, translationID).Error; err != nil {
+			return err
+		}
+		if translation.ID == 0 {
+			return customErrors.ErrTranslationNotFound
+		}
+		if err := tx.Where("translation_id = ?", translationID).Delete(&dbModels.TranslationTag{}).Error; err != nil {
+			return err
+		}
+		result = []*dbModels.TranslationTag{}
+		for _, name := range TagNames(tags) {
+			result = append(result, &dbModels.TranslationTag{TranslationID: translationID, Name: name})
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return tx.Create(&result).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (manager *DBManager) GetTranslationsByTag(tag string) ([]*dbModels.Translation, error) {
+	var translations []*dbModels.Translation
+	if err := manager.db.
+		Where("id IN (?)", manager.db.Model(&dbModels.TranslationTag{}).Select("translation_id").Where("name = ?", normalize.Tag(tag))).
+		Order("id").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (manager *DBManager) GetTagsByTranslationIds(ids []uint) ([]*dbModels.TranslationTag, error) {
+	var tags []*dbModels.TranslationTag
+	if err := manager.db.Where("translation_id IN ?", ids).
+		Where("translation_id IN (?)", manager.db.Model(&dbModels.Translation{}).Select("id")).
+		Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
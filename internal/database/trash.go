@@ -0,0 +1,203 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"gorm.io/gorm"
+)
+
+// Trash holds the deleted words, translations and examples, each the most
+// recently deleted first. Translations come with their words, which may be
+// deleted as well.
+type Trash struct {
+	PolishWords  []*dbModels.PolishWord
+	EnglishWords []*dbModels.EnglishWord
+	Translations []*dbModels.Translation
+	Examples     []*dbModels.Example
+}
+
+// trashRecord returns a new record of a table DeleteRecordFromTable accepts,
+// with the condition selecting the translations deleted along with the
+// record, empty for examples.
+func trashRecord(table interface{}) (interface{}, string, error) {
+	switch table.(type) {
+	case dbModels.PolishWord, *dbModels.PolishWord:
+		return &dbModels.PolishWord{}, "polish_word_id = ?", nil
+	case dbModels.EnglishWord, *dbModels.EnglishWord:
+		return &dbModels.EnglishWord{}, "english_word_id = ?", nil
+	case dbModels.Translation, *dbModels.Translation:
+		return &dbModels.Translation{}, "id = ?", nil
+	case dbModels.Example, *dbModels.Example:
+		return &dbModels.Example{}, "", nil
+	default:
+		return nil, "", fmt.Errorf("database: unsupported table %T", table)
+	}
+}
+
+// DeleteRecordFromTable moves the record to the trash along with the
+// translations and examples beneath it, giving them all the same DeletedAt,
+// which tells RestoreFromTrash what the deletion took along.
+func (manager *DBManager) DeleteRecordFromTable(table interface{}, id uint) error {
+	record, translations, err := trashRecord(table)
+	if err != nil {
+		return err
+	}
+	deletedAt := time.Now().UTC()
+	return manager.db.Transaction(func(tx *gorm.DB) error {
+		if translations != "" {
+			along := tx.Model(&dbModels.Translation{}).Select("id").Where(translations, id)
+			if err := tx.Model(&dbModels.Example{}).Where("translation_id IN (?)", along).
+				UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&dbModels.Translation{}).Where(translations, id).
+				UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(record).Where("id = ?", id).UpdateColumn("deleted_at", deletedAt).Error
+	})
+}
+
+func (manager *DBManager) GetTrash() (*Trash, error) {
+	trash := &Trash{}
+	deleted := func() *gorm.DB {
+		return manager.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id")
+	}
+	if err := deleted().Find(&trash.PolishWords).Error; err != nil {
+		return nil, err
+	}
+	if err := deleted().Find(&trash.EnglishWords).Error; err != nil {
+		return nil, err
+	}
+	if err := deleted().Preload("PolishWord", unscoped).Preload("EnglishWord", unscoped).
+		Find(&trash.Translations).Error; err != nil {
+		return nil, err
+	}
+	if err := deleted().Find(&trash.Examples).Error; err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
+
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func deletedAtOf(record interface{}) gorm.DeletedAt {
+	switch record := record.(type) {
+	case *dbModels.PolishWord:
+		return record.DeletedAt
+	case *dbModels.EnglishWord:
+		return record.DeletedAt
+	case *dbModels.Translation:
+		return record.DeletedAt
+	case *dbModels.Example:
+		return record.DeletedAt
+	default:
+		return gorm.DeletedAt{}
+	}
+}
+
+// RestoreFromTrash takes the record out of the trash along with the
+// translations and examples its deletion moved there. Translations whose
+// other word is still deleted stay in the trash.
+func (manager *DBManager) RestoreFromTrash(table interface{}, id uint) (interface{}, error) {
+	record, translations, err := trashRecord(table)
+	if err != nil {
+		return nil, err
+	}
+	err = manager.db.Transaction(func(tx *gorm.DB) error {
+		txManager := &DBManager{db: tx, lookups: manager.lookups}
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").Limit(1).Find(record, id).Error; err != nil {
+			return err
+		}
+		deletedAt := deletedAtOf(record)
+		if !deletedAt.Valid {
+			return customErrors.ErrNotInTrash
+		}
+		switch record := record.(type) {
+		case *dbModels.Translation:
+			if _, err := txManager.GetPolishWordById(record.PolishWordID); err != nil {
+				return err
+			}
+			if _, err := txManager.GetEnglishWordById(record.EnglishWordID); err != nil {
+				return err
+			}
+		case *dbModels.Example:
+			if _, err := txManager.GetTranslationById(record.TranslationID); err != nil {
+				return err
+			}
+		}
+
+		err := tx.Unscoped().Model(record).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error
+		switch {
+		case err == nil:
+		case polishWordTextUnique.violatedBy(err):
+			return customErrors.ErrPolishWordAlreadyExists
+		case englishWordTextUnique.violatedBy(err):
+			return customErrors.ErrEnglishWordAlreadyExists
+		case translationWordsUnique.violatedBy(err):
+			return customErrors.ErrTranslationAlreadyExists
+		case exampleTextUnique.violatedBy(err):
+			return customErrors.ErrExampleAlreadyExists
+		default:
+			return err
+		}
+		if translations != "" {
+			if err := tx.Unscoped().Model(&dbModels.Translation{}).
+				Where(translations, id).
+				Where("deleted_at = ?", deletedAt).
+				Where("polish_word_id IN (?)", tx.Model(&dbModels.PolishWord{}).Select("id")).
+				Where("english_word_id IN (?)", tx.Model(&dbModels.EnglishWord{}).Select("id")).
+				UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&dbModels.Example{}).
+				Where("deleted_at = ?", deletedAt).
+				Where("translation_id IN (?)", tx.Model(&dbModels.Translation{}).Select("id").Where(translations, id)).
+				UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.First(record, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// PurgeTrash deletes the examples first and the words last, so the foreign
+// keys cascade to nothing in the trash and every entry purged is counted.
+// Entries are never deleted after the ones beneath them, which keeps a cut
+// at olderThan from leaving any behind.
+func (manager *DBManager) PurgeTrash(olderThan *time.Time) (int64, error) {
+	var purged int64
+	err := manager.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []interface{}{
+			&dbModels.Example{},
+			&dbModels.Translation{},
+			&dbModels.EnglishWord{},
+			&dbModels.PolishWord{},
+		} {
+			query := tx.Unscoped().Where("deleted_at IS NOT NULL")
+			if olderThan != nil {
+				query = query.Where("deleted_at < ?", olderThan.UTC())
+			}
+			result := query.Delete(table)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
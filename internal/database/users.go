@@ -0,0 +1,91 @@
+package database
+
+import (
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+func (manager *DBManager) AddUser(user dbModels.User) (*dbModels.User, error) {
+	user.ID = 0
+	if err := manager.db.Create(&user).Error; err != nil {
+		if usernameUnique.violatedBy(err) {
+			return nil, customErrors.ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (manager *DBManager) GetUserById(id uint) (*dbModels.User, error) {
+	var user dbModels.User
+	if err := manager.db.Limit(1).Find(&user, id).Error; err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, customErrors.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (manager *DBManager) GetUserByUsername(username string) (*dbModels.User, error) {
+	var user dbModels.User
+	if err := manager.db.Where("username = ?", username).Limit(1).Find(&user).Error; err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, customErrors.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (manager *DBManager) SetUserRole(id uint, role string) (*dbModels.User, error) {
+	user, err := manager.GetUserById(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.db.Model(user).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (manager *DBManager) AddAPIKey(key dbModels.APIKey) (*dbModels.APIKey, error) {
+	key.ID = 0
+	if _, err := manager.GetUserById(key.UserID); err != nil {
+		return nil, err
+	}
+	if err := manager.db.Omit("User").Create(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (manager *DBManager) GetAPIKeyByHash(hash string) (*dbModels.APIKey, error) {
+	var key dbModels.APIKey
+	if err := manager.db.Preload("User").Where("hash = ?", hash).Limit(1).Find(&key).Error; err != nil {
+		return nil, err
+	}
+	if key.ID == 0 {
+		return nil, customErrors.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (manager *DBManager) GetAPIKeysByUserId(userID uint) ([]*dbModels.APIKey, error) {
+	keys := []*dbModels.APIKey{}
+	if err := manager.db.Where("user_id = ?", userID).Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (manager *DBManager) DeleteAPIKey(userID uint, id uint) error {
+	result := manager.db.Where("user_id = ?", userID).Delete(&dbModels.APIKey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrAPIKeyNotFound
+	}
+	return nil
+}
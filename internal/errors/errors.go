@@ -10,4 +10,34 @@ var (
 	ErrExampleAlreadyExists     = errors.New("example with this text already exists")
 	ErrPolishWordAlreadyExists  = errors.New("polish word with this text already exists")
 	ErrEnglishWordAlreadyExists = errors.New("english word with this text already exists")
+	ErrInvalidCursor            = errors.New("invalid pagination cursor")
+	ErrInvalidPageSize          = errors.New("first and last must be between 0 and 1000")
+	ErrInvalidLimit             = errors.New("limit must be between 1 and 100")
+	ErrUnknownLanguage          = errors.New("unknown language")
+	ErrInvalidBatchSize         = errors.New("batch size must not be negative")
+	ErrCheckpointMismatch       = errors.New("checkpoint does not belong to this file, restart the import")
+	ErrInvalidGrade             = errors.New("grade must be between 0 and 5")
+	ErrUnknownDirection         = errors.New("unknown study direction")
+	ErrLearnerRequired          = errors.New("learner is required, send the X-Learner header")
+	ErrInvalidQuizSize          = errors.New("quiz size must be between 1 and 100")
+	ErrInvalidChoices           = errors.New("choices must be between 2 and 10")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrUserAlreadyExists        = errors.New("user with this username already exists")
+	ErrAPIKeyNotFound           = errors.New("api key not found")
+	ErrInvalidCredentials       = errors.New("invalid username or password")
+	ErrInvalidToken             = errors.New("invalid or expired token")
+	ErrUnauthenticated          = errors.New("authentication required")
+	ErrAuthenticationDisabled   = errors.New("authentication is not configured")
+	ErrInvalidUsername          = errors.New("username must not be empty")
+	ErrPasswordTooShort         = errors.New("password must have at least 8 characters")
+	ErrUnknownRole              = errors.New("unknown role, use READER, EDITOR or ADMIN")
+	ErrForbidden                = errors.New("your role does not allow this operation")
+	ErrTranslationAlreadyExists = errors.New("translation between these words already exists")
+	ErrRevisionNotFound         = errors.New("revision not found")
+	ErrUnknownEntity            = errors.New("unknown entity, use POLISH_WORD, ENGLISH_WORD, TRANSLATION or EXAMPLE")
+	ErrNotInTrash               = errors.New("entry is not in the trash")
+	ErrVersionConflict          = errors.New("entry was changed since it was read, reload it and try again")
+	ErrGenderWithoutNoun        = errors.New("only nouns have a gender")
+	ErrAspectWithoutVerb        = errors.New("only verbs have an aspect")
+	ErrGrammarMismatch          = errors.New("polish word already exists with another gender or aspect")
 )
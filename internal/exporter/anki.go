@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// FormatAnki is a TSV file for Anki's text import. It is not a general
+// dump, see ExportAnki.
+const FormatAnki Format = "ANKI"
+
+// AnkiDirection selects which side of a translation is asked on cards.
+type AnkiDirection string
+
+const (
+	PolishToEnglish AnkiDirection = "PL_EN"
+	EnglishToPolish AnkiDirection = "EN_PL"
+	BothDirections  AnkiDirection = "BOTH"
+)
+
+// ParseAnkiDirection accepts a direction in any case, with "-" for "_".
+func ParseAnkiDirection(name string) (AnkiDirection, error) {
+	switch direction := AnkiDirection(strings.ReplaceAll(strings.ToUpper(name), "-", "_")); direction {
+	case PolishToEnglish, EnglishToPolish, BothDirections:
+		return direction, nil
+	default:
+		return "", fmt.Errorf("unknown anki direction %q", name)
+	}
+}
+
+const (
+	DefaultAnkiDeck     = "dictionaryGO"
+	DefaultAnkiNoteType = "Basic"
+
+	ankiGUIDPrefix = "dictionaryGO-"
+)
+
+// AnkiOptions configure an Anki export. TranslationIDs selects the exported
+// translations, all of them when empty. NoteType names a note type with the
+// fields Front and Back, Anki calls the built-in one differently in other
+// languages.
+type AnkiOptions struct {
+	Direction      AnkiDirection
+	Deck           string
+	NoteType       string
+	TranslationIDs []uint
+}
+
+// checkAnkiHeader rejects a value of a header line with control characters,
+// a line break would end the header and start a note or another header.
+func checkAnkiHeader(name, value string) error {
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return fmt.Errorf("anki %s %q contains control characters", name, value)
+	}
+	return nil
+}
+
+// ExportAnki writes translations as notes for Anki's text import, the asked
+// word on the front and its translation with the examples on the back. Each
+// direction is a note of its own, BothDirections writes two per translation.
+// Note GUIDs derive from the translation ID and the direction, so importing
+// a later export updates the cards instead of adding new ones. It returns
+// the number of notes written.
+func ExportAnki(store database.DictionaryStore, w io.Writer, options AnkiOptions) (int, error) {
+	directions := []AnkiDirection{options.Direction}
+	switch options.Direction {
+	case PolishToEnglish, EnglishToPolish:
+	case BothDirections:
+		directions = []AnkiDirection{PolishToEnglish, EnglishToPolish}
+	default:
+		return 0, fmt.Errorf("unknown anki direction %q", options.Direction)
+	}
+	deck, noteType := options.Deck, options.NoteType
+	if deck == "" {
+		deck = DefaultAnkiDeck
+	}
+	if noteType == "" {
+		noteType = DefaultAnkiNoteType
+	}
+	if err := checkAnkiHeader("deck", deck); err != nil {
+		return 0, err
+	}
+	if err := checkAnkiHeader("note type", noteType); err != nil {
+		return 0, err
+	}
+
+	buffered := bufio.NewWriter(w)
+	// Anki reads these headers since version 2.1.55.
+	for _, header := range []string{
+		"#separator:tab",
+		"#html:true",
+		"#notetype:" + noteType,
+		"#deck:" + deck,
+		"#guid column:1",
+		"#columns:GUID\tFront\tBack",
+	} {
+		if _, err := fmt.Fprintln(buffered, header); err != nil {
+			return 0, err
+		}
+	}
+	tsv := csv.NewWriter(buffered)
+	tsv.Comma = '\t'
+
+	count := 0
+	err := eachBatch(store, options.TranslationIDs, func(translations []*dbModels.Translation) error {
+		for _, translation := range translations {
+			for _, direction := range directions {
+				if err := tsv.Write(ankiNote(translation, direction)); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	tsv.Flush()
+	if err := tsv.Error(); err != nil {
+		return count, err
+	}
+	return count, buffered.Flush()
+}
+
+// AnkiGUID is the GUID of the note asking translationID in direction.
+func AnkiGUID(translationID uint, direction AnkiDirection) string {
+	return ankiGUIDPrefix + strconv.FormatUint(uint64(translationID), 10) + "-" + strings.ToLower(string(direction))
+}
+
+func ankiNote(translation *dbModels.Translation, direction AnkiDirection) []string {
+	front, back := translation.PolishWord.Text, translation.EnglishWord.Text
+	if direction == EnglishToPolish {
+		front, back = back, front
+	}
+	answer := html.EscapeString(back)
+	if len(translation.Examples) > 0 {
+		var examples strings.Builder
+		examples.WriteString("<ul>")
+		for _, example := range translation.Examples {
+			examples.WriteString("<li>" + html.EscapeString(example.Text) + "</li>")
+		}
+		examples.WriteString("</ul>")
+		answer += examples.String()
+	}
+	return []string{AnkiGUID(translation.ID, direction), html.EscapeString(front), answer}
+}
@@ -0,0 +1,309 @@
+// Package exporter streams the whole dictionary as JSON, JSONL or CSV.
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/importer"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+type Format string
+
+const (
+	FormatJSON  Format = "JSON"
+	FormatJSONL Format = "JSONL"
+	FormatCSV   Format = "CSV"
+)
+
+// ParseFormat accepts a format name in any case.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToUpper(name)); format {
+	case FormatJSON, FormatJSONL, FormatCSV, FormatAnki:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", name)
+	}
+}
+
+// FormatFromFilename picks the format of the file extension, JSON if unknown.
+func FormatFromFilename(name string) Format {
+	if format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(name), ".")); err == nil {
+		return format
+	}
+	return FormatJSON
+}
+
+// ContentType is the media type of an export in format.
+func (format Format) ContentType() string {
+	switch format {
+	case FormatJSONL:
+		return "application/jsonl"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatAnki:
+		return "text/tab-separated-values; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// Extension is the file extension of an export in format, without the dot.
+func (format Format) Extension() string {
+	if format == FormatAnki {
+		return "txt"
+	}
+	return strings.ToLower(string(format))
+}
+
+// batchSize is the number of translations held in memory at once.
+const batchSize = database.MaxPageSize
+
+// Export writes every translation of store with its words and examples to w,
+// ordered by ID so exports of the same data are identical. Translations are
+// read in batches, the dictionary is never loaded at once. It returns the
+// number of translations written.
+func Export(store database.DictionaryStore, w io.Writer, format Format) (int, error) {
+	buffered := bufio.NewWriter(w)
+	var encoder encoder
+	switch format {
+	case FormatJSON:
+		encoder = &jsonEncoder{w: buffered}
+	case FormatJSONL:
+		encoder = &jsonlEncoder{w: buffered}
+	case FormatCSV:
+		encoder = &csvEncoder{w: csv.NewWriter(buffered)}
+	case FormatAnki:
+		return 0, errors.New("anki exports take options, use ExportAnki")
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	if err := encoder.begin(); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := eachBatch(store, nil, func(translations []*dbModels.Translation) error {
+		for _, translation := range translations {
+			if err := encoder.write(newRecord(translation)); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := encoder.end(); err != nil {
+		return count, err
+	}
+	return count, buffered.Flush()
+}
+
+// eachBatch passes the translations with the given IDs, or all of them when
+// ids is empty, to fn in batches ordered by ID, with their associations.
+func eachBatch(store database.DictionaryStore, ids []uint, fn func([]*dbModels.Translation) error) error {
+	if len(ids) > 0 {
+		return eachBatchOf(store, ids, fn)
+	}
+	size := batchSize
+	page := database.PageRequest{First: &size}
+	for {
+		batch, err := store.GetTranslationsPage(page)
+		if err != nil {
+			return err
+		}
+		if err := store.PopulateTranslationsWithAssociations(batch.Items); err != nil {
+			return err
+		}
+		if err := fn(batch.Items); err != nil {
+			return err
+		}
+		if !batch.HasNextPage {
+			return nil
+		}
+		last := batch.Items[len(batch.Items)-1].ID
+		page.After = &last
+	}
+}
+
+func eachBatchOf(store database.DictionaryStore, ids []uint, fn func([]*dbModels.Translation) error) error {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	for start := 0; start < len(ids); start += batchSize {
+		chunk := ids[start:min(start+batchSize, len(ids))]
+		translations := make([]*dbModels.Translation, len(chunk))
+		for i, id := range chunk {
+			translations[i] = &dbModels.Translation{ID: id}
+		}
+		if err := store.PopulateTranslationsWithAssociations(translations); err != nil {
+			return err
+		}
+		if err := fn(translations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record is the exported form of a translation. Grammatical values are the
+// GraphQL enum names and left out when unspecified.
+type record struct {
+	ID          uint          `json:"id"`
+	PolishWord  polishWord    `json:"polishWord"`
+	EnglishWord englishWord   `json:"englishWord"`
+	Examples    []exampleItem `json:"examples"`
+}
+
+type polishWord struct {
+	ID           uint   `json:"id"`
+	Text         string `json:"text"`
+	PartOfSpeech string `json:"partOfSpeech,omitempty"`
+	Gender       string `json:"gender,omitempty"`
+	Aspect       string `json:"aspect,omitempty"`
+}
+
+type englishWord struct {
+	ID           uint   `json:"id"`
+	Text         string `json:"text"`
+	PartOfSpeech string `json:"partOfSpeech,omitempty"`
+}
+
+type exampleItem struct {
+	ID       uint   `json:"id"`
+	Text     string `json:"text"`
+	InPolish bool   `json:"inPolish"`
+}
+
+func newRecord(translation *dbModels.Translation) record {
+	examples := make([]exampleItem, len(translation.Examples))
+	for i, example := range translation.Examples {
+		examples[i] = exampleItem{ID: example.ID, Text: example.Text, InPolish: example.InPolish}
+	}
+	return record{
+		ID: translation.ID,
+		PolishWord: polishWord{
+			ID:           translation.PolishWord.ID,
+			Text:         translation.PolishWord.Text,
+			PartOfSpeech: translation.PolishWord.PartOfSpeech,
+			Gender:       translation.PolishWord.Gender,
+			Aspect:       translation.PolishWord.Aspect,
+		},
+		EnglishWord: englishWord{
+			ID:           translation.EnglishWord.ID,
+			Text:         translation.EnglishWord.Text,
+			PartOfSpeech: translation.EnglishWord.PartOfSpeech,
+		},
+		Examples: examples,
+	}
+}
+
+type encoder interface {
+	begin() error
+	write(record record) error
+	end() error
+}
+
+// jsonEncoder writes an indented array with a translation per element, so
+// exports diff line by line.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) write(record record) error {
+	data, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	separator := ",\n  "
+	if e.count == 0 {
+		separator = "\n  "
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+type jsonlEncoder struct {
+	w io.Writer
+}
+
+func (e *jsonlEncoder) begin() error { return nil }
+
+func (e *jsonlEncoder) write(record record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+func (e *jsonlEncoder) end() error { return nil }
+
+// csvColumns are named like the columns of the importer, so an export can be
+// imported again.
+var csvColumns = []string{
+	"id", "polish", "english", "part_of_speech", "polish_gender", "polish_aspect",
+	"polish_examples", "english_examples",
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write(csvColumns)
+}
+
+func (e *csvEncoder) write(record record) error {
+	var polishExamples, englishExamples []string
+	for _, example := range record.Examples {
+		if example.InPolish {
+			polishExamples = append(polishExamples, example.Text)
+		} else {
+			englishExamples = append(englishExamples, example.Text)
+		}
+	}
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.PolishWord.Text,
+		record.EnglishWord.Text,
+		record.PolishWord.PartOfSpeech,
+		record.PolishWord.Gender,
+		record.PolishWord.Aspect,
+		importer.JoinExamples(polishExamples),
+		importer.JoinExamples(englishExamples),
+	})
+}
+
+func (e *csvEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/importer"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore() *memstore.Store {
+	store := memstore.NewStore()
+	noun := model.PartOfSpeechNoun
+	masculine := model.GenderMasculineAnimate
+	store.AddTranslation(model.TranslationInput{
+		PolishWord:   "pies",
+		EnglishWord:  "dog",
+		PartOfSpeech: &noun,
+		PolishGender: &masculine,
+		Examples:     []*model.ExampleInput{{Text: "Mam psa", InPolish: true}, {Text: "I have a dog"}},
+	})
+	store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	return store
+}
+
+func TestExportJSON(t *testing.T) {
+	var out bytes.Buffer
+	count, err := Export(newTestStore(), &out, FormatJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	var records []record
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, "pies", records[0].PolishWord.Text)
+	assert.Equal(t, "MASCULINE_ANIMATE", records[0].PolishWord.Gender)
+	assert.Equal(t, "NOUN", records[0].EnglishWord.PartOfSpeech)
+	assert.Equal(t, []exampleItem{{ID: 1, Text: "Mam psa", InPolish: true}, {ID: 2, Text: "I have a dog"}}, records[0].Examples)
+	assert.NotContains(t, out.String(), `"aspect"`)
+}
+
+func TestExportEmptyJSON(t *testing.T) {
+	var out bytes.Buffer
+	_, err := Export(memstore.NewStore(), &out, FormatJSON)
+	assert.NoError(t, err)
+	var records []record
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &records))
+	assert.Len(t, records, 0)
+}
+
+func TestExportJSONL(t *testing.T) {
+	var out bytes.Buffer
+	_, err := Export(newTestStore(), &out, FormatJSONL)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, `{"id":2,"polishWord":{"id":2,"text":"kot"},"englishWord":{"id":2,"text":"cat"},"examples":[]}`, lines[1])
+}
+
+func TestExportCSV(t *testing.T) {
+	var out bytes.Buffer
+	_, err := Export(newTestStore(), &out, FormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,polish,english,part_of_speech,polish_gender,polish_aspect,polish_examples,english_examples\n"+
+		"1,pies,dog,NOUN,MASCULINE_ANIMATE,,Mam psa,I have a dog\n"+
+		"2,kot,cat,,,,,\n", out.String())
+}
+
+func TestExportCSVEscapesExampleSeparator(t *testing.T) {
+	store := memstore.NewStore()
+	examples := []*model.ExampleInput{{Text: "Wybierz tak | nie", InPolish: true}, {Text: `C:\psy\| to katalog`, InPolish: true}}
+	store.AddTranslation(model.TranslationInput{PolishWord: "albo", EnglishWord: "or", Examples: examples})
+
+	var out bytes.Buffer
+	_, err := Export(store, &out, FormatCSV)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `Wybierz tak \| nie | C:\\psy\\\| to katalog`)
+
+	rows, err := importer.ReadRows(&out, importer.FormatCSV)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, examples, rows[0].Input.Examples)
+}
+
+func TestExportReadsEveryBatch(t *testing.T) {
+	store := memstore.NewStore()
+	for i := 0; i < batchSize+1; i++ {
+		store.AddTranslation(model.TranslationInput{PolishWord: fmt.Sprint("słowo", i), EnglishWord: fmt.Sprint("word", i)})
+	}
+	var out bytes.Buffer
+	count, err := Export(store, &out, FormatJSONL)
+	assert.NoError(t, err)
+	assert.Equal(t, batchSize+1, count)
+	assert.Equal(t, batchSize+1, strings.Count(out.String(), "\n"))
+}
+
+func TestHandler(t *testing.T) {
+	handler := Handler(newTestStore())
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=csv", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="dictionary.csv"`, response.Header().Get("Content-Disposition"))
+	assert.Contains(t, response.Body.String(), "2,kot,cat")
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestExportAnki(t *testing.T) {
+	var out bytes.Buffer
+	count, err := ExportAnki(newTestStore(), &out, AnkiOptions{Direction: BothDirections, Deck: "Polski"})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, "#separator:tab\n#html:true\n#notetype:Basic\n#deck:Polski\n#guid column:1\n#columns:GUID\tFront\tBack\n"+
+		"dictionaryGO-1-pl_en\tpies\tdog<ul><li>Mam psa</li><li>I have a dog</li></ul>\n"+
+		"dictionaryGO-1-en_pl\tdog\tpies<ul><li>Mam psa</li><li>I have a dog</li></ul>\n"+
+		"dictionaryGO-2-pl_en\tkot\tcat\n"+
+		"dictionaryGO-2-en_pl\tcat\tkot\n", out.String())
+}
+
+func TestExportAnkiSelectedTranslations(t *testing.T) {
+	store := newTestStore()
+	store.AddTranslation(model.TranslationInput{PolishWord: "<b>koń</b>", EnglishWord: "horse"})
+
+	var out bytes.Buffer
+	count, err := ExportAnki(store, &out, AnkiOptions{Direction: EnglishToPolish, TranslationIDs: []uint{3, 2, 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, strings.HasSuffix(out.String(), "dictionaryGO-2-en_pl\tcat\tkot\ndictionaryGO-3-en_pl\thorse\t&lt;b&gt;koń&lt;/b&gt;\n"))
+
+	_, err = ExportAnki(store, &out, AnkiOptions{Direction: EnglishToPolish, TranslationIDs: []uint{7}})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
+
+func TestExportAnkiRejectsControlCharactersInHeaders(t *testing.T) {
+	var out bytes.Buffer
+	_, err := ExportAnki(newTestStore(), &out, AnkiOptions{Direction: BothDirections, Deck: "Polski\n#notetype:Cloze"})
+	assert.Error(t, err)
+	_, err = ExportAnki(newTestStore(), &out, AnkiOptions{Direction: BothDirections, NoteType: "Basic\r"})
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestParseAnkiDirection(t *testing.T) {
+	direction, err := ParseAnkiDirection("pl-en")
+	assert.NoError(t, err)
+	assert.Equal(t, PolishToEnglish, direction)
+	_, err = ParseAnkiDirection("de-en")
+	assert.Error(t, err)
+}
+
+func TestHandlerAnki(t *testing.T) {
+	handler := Handler(newTestStore())
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=anki&direction=en-pl&ids=2", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `attachment; filename="dictionary.txt"`, response.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasSuffix(response.Body.String(), "#columns:GUID\tFront\tBack\ndictionaryGO-2-en_pl\tcat\tkot\n"))
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=anki&ids=9", nil))
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=anki&ids=x", nil))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/export?format=anki&deck=Polski%0A%23notetype:Cloze", nil))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
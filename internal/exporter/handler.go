@@ -0,0 +1,115 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+)
+
+// Handler serves the export of store as a download. The format query
+// parameter selects JSON (the default), JSONL, CSV or ANKI. Anki exports
+// read direction (BOTH by default), deck, note_type and ids, a comma
+// separated list of translation IDs.
+func Handler(store database.DictionaryStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		format := FormatJSON
+		if name := query.Get("format"); name != "" {
+			var err error
+			if format, err = ParseFormat(name); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		export := func(w io.Writer) (int, error) { return Export(store, w, format) }
+		if format == FormatAnki {
+			options, err := ankiOptionsFromQuery(query)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			export = func(w io.Writer) (int, error) { return ExportAnki(store, w, options) }
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="dictionary.%s"`, format.Extension()))
+		// The status is sent with the first bytes, a failure after them can
+		// only cut the download short.
+		tracked := &trackingWriter{w: w}
+		if _, err := export(tracked); err != nil {
+			log.Printf("export failed: %v", err)
+			if !tracked.written {
+				w.Header().Del("Content-Disposition")
+				if errors.Is(err, customErrors.ErrTranslationNotFound) {
+					http.Error(w, err.Error(), http.StatusNotFound)
+				} else {
+					http.Error(w, "export failed", http.StatusInternalServerError)
+				}
+			}
+		}
+	})
+}
+
+func ankiOptionsFromQuery(query url.Values) (AnkiOptions, error) {
+	options := AnkiOptions{
+		Direction: BothDirections,
+		Deck:      query.Get("deck"),
+		NoteType:  query.Get("note_type"),
+	}
+	if name := query.Get("direction"); name != "" {
+		direction, err := ParseAnkiDirection(name)
+		if err != nil {
+			return options, err
+		}
+		options.Direction = direction
+	}
+	if err := checkAnkiHeader("deck", options.Deck); err != nil {
+		return options, err
+	}
+	if err := checkAnkiHeader("note type", options.NoteType); err != nil {
+		return options, err
+	}
+	ids, err := ParseIDs(query.Get("ids"))
+	options.TranslationIDs = ids
+	return options, err
+}
+
+// ParseIDs reads a comma separated list of IDs, empty for an empty list.
+func ParseIDs(list string) ([]uint, error) {
+	var ids []uint
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(field, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid translation id %q", field)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// trackingWriter records whether anything was written to w.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
@@ -0,0 +1,265 @@
+// Package history keeps every version of the words, translations and
+// examples, so a change or a deletion can be undone. Like the audit log,
+// revisions are written in the transaction of the change they record.
+//
+// Entities get their first revision on their first change made through a
+// Store, which records the entity as it was before the change as well.
+package history
+
+import (
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// Entity types, named like the RevisionEntity enum.
+const (
+	EntityPolishWord  = "POLISH_WORD"
+	EntityEnglishWord = "ENGLISH_WORD"
+	EntityTranslation = "TRANSLATION"
+	EntityExample     = "EXAMPLE"
+)
+
+// Store adds a revision of every word, translation and example changed
+// through it, made by actor. Reads pass through to the wrapped store.
+type Store struct {
+	database.DictionaryStore
+	actor string
+	now   func() time.Time
+}
+
+var _ database.DictionaryStore = (*Store)(nil)
+
+// NewStore wraps store, dating the revisions it adds by now.
+func NewStore(store database.DictionaryStore, actor string, now func() time.Time) *Store {
+	return &Store{DictionaryStore: store, actor: actor, now: now}
+}
+
+// change runs fn in a transaction of the wrapped store, tracking the
+// entities before it and the ones fn reports after it.
+func (s *Store) change(before []ref, fn func(store database.DictionaryStore) ([]ref, error)) error {
+	return s.DictionaryStore.WithinTransaction(func(store database.DictionaryStore) error {
+		for _, entity := range before {
+			if err := s.track(store, entity); err != nil {
+				return err
+			}
+		}
+		after, err := fn(store)
+		if err != nil {
+			return err
+		}
+		for _, entity := range after {
+			if err := s.track(store, entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// track adds the entity as it is now as its next revision, unless its latest
+// revision holds it already. Missing entities are skipped. The entity stays
+// locked until the transaction ends, so concurrent changes compare with the
+// revisions the previous one added.
+func (s *Store) track(store database.DictionaryStore, entity ref) error {
+	if err := store.LockRecord(entity.table(), entity.id); err != nil {
+		return err
+	}
+	next, err := current(store, entity)
+	if err != nil || next == nil {
+		return err
+	}
+	recorded, err := revisions(store, entity)
+	if err != nil {
+		return err
+	}
+	if n := len(recorded); n > 0 && !recorded[n-1].Meta().Deleted && values(recorded[n-1]) == values(next) {
+		return nil
+	}
+	return s.add(store, next)
+}
+
+func (s *Store) add(store database.DictionaryStore, revision revision) error {
+	meta := revision.Meta()
+	meta.Actor, meta.RecordedAt = s.actor, s.now()
+	return store.AddRevision(revision)
+}
+
+// WithinTransaction keeps tracking the changes fn makes.
+func (s *Store) WithinTransaction(fn func(store database.DictionaryStore) error) error {
+	return s.DictionaryStore.WithinTransaction(func(store database.DictionaryStore) error {
+		return fn(&Store{DictionaryStore: store, actor: s.actor, now: s.now})
+	})
+}
+
+func (s *Store) AddPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	var added *dbModels.PolishWord
+	err := s.change(nil, func(store database.DictionaryStore) (after []ref, err error) {
+		if added, err = store.AddPolishWord(word); err != nil {
+			return nil, err
+		}
+		return []ref{{EntityPolishWord, added.ID}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) AddEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
+	var added *dbModels.EnglishWord
+	err := s.change(nil, func(store database.DictionaryStore) (after []ref, err error) {
+		if added, err = store.AddEnglishWord(word); err != nil {
+			return nil, err
+		}
+		return []ref{{EntityEnglishWord, added.ID}}, nil
+	})
+	return added, err
+}
+
+// AddTranslation tracks the translation with its words and examples, any of
+// which it may have created.
+func (s *Store) AddTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
+	var added *dbModels.Translation
+	err := s.change(nil, func(store database.DictionaryStore) (after []ref, err error) {
+		if added, err = store.AddTranslation(translationInput); err != nil {
+			return nil, err
+		}
+		examples, err := store.GetExamplesByTranslationIds([]uint{added.ID})
+		if err != nil {
+			return nil, err
+		}
+		after = []ref{
+			{EntityPolishWord, added.PolishWordID},
+			{EntityEnglishWord, added.EnglishWordID},
+			{EntityTranslation, added.ID},
+		}
+		for _, example := range examples {
+			after = append(after, ref{EntityExample, example.ID})
+		}
+		return after, nil
+	})
+	return added, err
+}
+
+func (s *Store) AddExampleToTranslation(example *model.ExampleInput, translationID uint) (*dbModels.Example, error) {
+	var added *dbModels.Example
+	err := s.change(nil, func(store database.DictionaryStore) (after []ref, err error) {
+		if added, err = store.AddExampleToTranslation(example, translationID); err != nil {
+			return nil, err
+		}
+		return []ref{{EntityExample, added.ID}}, nil
+	})
+	return added, err
+}
+
+func (s *Store) ChangeExampleText(id uint, text string, expectedVersion *uint) (*dbModels.Example, error) {
+	var updated *dbModels.Example
+	entity := ref{EntityExample, id}
+	err := s.change([]ref{entity}, func(store database.DictionaryStore) (after []ref, err error) {
+		updated, err = store.ChangeExampleText(id, text, expectedVersion)
+		return []ref{entity}, err
+	})
+	return updated, err
+}
+
+func (s *Store) ChangePolishWordText(id uint, text string, expectedVersion *uint) (*dbModels.PolishWord, error) {
+	var updated *dbModels.PolishWord
+	entity := ref{EntityPolishWord, id}
+	err := s.change([]ref{entity}, func(store database.DictionaryStore) (after []ref, err error) {
+		updated, err = store.ChangePolishWordText(id, text, expectedVersion)
+		return []ref{entity}, err
+	})
+	return updated, err
+}
+
+func (s *Store) ChangeEnglishWordText(id uint, text string, expectedVersion *uint) (*dbModels.EnglishWord, error) {
+	var updated *dbModels.EnglishWord
+	entity := ref{EntityEnglishWord, id}
+	err := s.change([]ref{entity}, func(store database.DictionaryStore) (after []ref, err error) {
+		updated, err = store.ChangeEnglishWordText(id, text, expectedVersion)
+		return []ref{entity}, err
+	})
+	return updated, err
+}
+
+func (s *Store) RestoreRecord(record interface{}) error {
+	entity, err := refOfRecord(record)
+	if err != nil {
+		return err
+	}
+	return s.change(nil, func(store database.DictionaryStore) ([]ref, error) {
+		return []ref{entity}, store.RestoreRecord(record)
+	})
+}
+
+// RestoreFromTrash tracks the entity with the translations and examples
+// restored along with it.
+func (s *Store) RestoreFromTrash(table interface{}, id uint) (interface{}, error) {
+	entity, err := refOfTable(table, id)
+	if err != nil {
+		return nil, err
+	}
+	var restored interface{}
+	err = s.change(nil, func(store database.DictionaryStore) (after []ref, err error) {
+		if restored, err = store.RestoreFromTrash(table, id); err != nil {
+			return nil, err
+		}
+		along, err := deletedAlong(store, entity)
+		return append([]ref{entity}, along...), err
+	})
+	return restored, err
+}
+
+// DeleteRecordFromTable adds a deleted revision of the entity and of the
+// translations and examples its deletion cascades to, naming the deletion of
+// the entity as their DeletedWith.
+func (s *Store) DeleteRecordFromTable(table interface{}, id uint) error {
+	entity, err := refOfTable(table, id)
+	if err != nil {
+		return err
+	}
+	return s.DictionaryStore.WithinTransaction(func(store database.DictionaryStore) error {
+		if err := s.track(store, entity); err != nil {
+			return err
+		}
+		deleted, err := current(store, entity)
+		if err != nil {
+			return err
+		}
+		if deleted == nil {
+			return store.DeleteRecordFromTable(table, id)
+		}
+		along, err := deletedAlong(store, entity)
+		if err != nil {
+			return err
+		}
+		var cascaded []revision
+		for _, entity := range along {
+			if err := s.track(store, entity); err != nil {
+				return err
+			}
+			revision, err := current(store, entity)
+			if err != nil {
+				return err
+			}
+			if revision != nil {
+				cascaded = append(cascaded, revision)
+			}
+		}
+		if err := store.DeleteRecordFromTable(table, id); err != nil {
+			return err
+		}
+		deleted.Meta().Deleted = true
+		if err := s.add(store, deleted); err != nil {
+			return err
+		}
+		for _, revision := range cascaded {
+			meta := revision.Meta()
+			meta.Deleted, meta.DeletedWith = true, deletionKey(entity, deleted.Meta().Version)
+			if err := s.add(store, revision); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
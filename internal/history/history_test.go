@@ -0,0 +1,160 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newStore() (*memstore.Store, *Store) {
+	inner := memstore.NewStore()
+	return inner, NewStore(inner, "ala", func() time.Time { return now })
+}
+
+func TestChangesAddRevisions(t *testing.T) {
+	inner, store := newStore()
+
+	// Words stored before the history existed get the revision of their
+	// current state first.
+	word, _ := inner.AddPolishWord(dbModels.PolishWord{Text: "kto", PartOfSpeech: "NOUN"})
+	_, err := store.ChangePolishWordText(word.ID, "kot", nil)
+	assert.NoError(t, err)
+	_, err = store.ChangePolishWordText(word.ID, "kot", nil)
+	assert.NoError(t, err)
+	_, err = store.ChangePolishWordText(999, "pies", nil)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+
+	revisions, err := inner.GetPolishWordRevisions(word.ID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, uint(1), revisions[0].Version)
+	assert.Equal(t, "kto", revisions[0].Text)
+	assert.Equal(t, uint(2), revisions[1].Version)
+	assert.Equal(t, "kot", revisions[1].Text)
+	assert.Equal(t, "NOUN", revisions[1].PartOfSpeech)
+	assert.Equal(t, "ala", revisions[1].Actor)
+	assert.Equal(t, now, revisions[1].RecordedAt)
+	assert.False(t, revisions[1].Deleted)
+}
+
+func TestRestoreChangedEntity(t *testing.T) {
+	inner, store := newStore()
+
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	examples, _ := inner.GetExamplesByTranslationIds([]uint{translation.ID})
+	example := examples[0]
+	store.ChangeExampleText(example.ID, "Ala ma psa", nil)
+
+	restored, err := Restore(store, EntityExample, example.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ala ma kota", restored.(*dbModels.Example).Text)
+	assert.Equal(t, uint(3), restored.(*dbModels.Example).Version)
+	stored, _ := inner.GetExampleById(example.ID)
+	assert.Equal(t, "Ala ma kota", stored.Text)
+
+	revisions, _ := inner.GetExampleRevisions(example.ID)
+	assert.Len(t, revisions, 3)
+	assert.Equal(t, "Ala ma kota", revisions[2].Text)
+
+	_, err = Restore(store, EntityExample, example.ID, 4)
+	assert.Equal(t, customErrors.ErrRevisionNotFound, err)
+}
+
+func TestRestoreRecreatesWhatTheDeletionRemoved(t *testing.T) {
+	inner, store := newStore()
+
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples: []*model.ExampleInput{
+			{Text: "Ala ma kota", InPolish: true},
+			{Text: "I have a cat", InPolish: false},
+		},
+	})
+	other, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kocur", EnglishWord: "cat"})
+	// Deleted on its own, so restoring the word must not bring it back.
+	store.DeleteRecordFromTable(dbModels.Translation{}, other.ID)
+	assert.NoError(t, store.DeleteRecordFromTable(dbModels.EnglishWord{}, translation.EnglishWordID))
+
+	wordRevisions, _ := inner.GetEnglishWordRevisions(translation.EnglishWordID)
+	assert.Len(t, wordRevisions, 2)
+	assert.True(t, wordRevisions[1].Deleted)
+	assert.Empty(t, wordRevisions[1].DeletedWith)
+	deletedWith, _ := inner.GetTranslationRevisionsByDeletion("ENGLISH_WORD:1:2")
+	assert.Len(t, deletedWith, 1)
+	assert.Equal(t, translation.ID, deletedWith[0].TranslationID)
+	assert.True(t, deletedWith[0].Deleted)
+
+	restored, err := Restore(store, EntityEnglishWord, translation.EnglishWordID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "cat", restored.(*dbModels.EnglishWord).Text)
+
+	restoredTranslation := &dbModels.Translation{ID: translation.ID}
+	assert.NoError(t, inner.PopulateTranslationWithAssociations(restoredTranslation))
+	assert.Equal(t, "kot", restoredTranslation.PolishWord.Text)
+	assert.Equal(t, "cat", restoredTranslation.EnglishWord.Text)
+	assert.Len(t, restoredTranslation.Examples, 2)
+	_, err = inner.GetTranslationById(other.ID)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+
+	translationRevisions, _ := inner.GetTranslationRevisions(translation.ID)
+	assert.Len(t, translationRevisions, 3)
+	assert.False(t, translationRevisions[2].Deleted)
+}
+
+func TestRestoreSkipsTranslationsWithDeletedWords(t *testing.T) {
+	inner, store := newStore()
+
+	translation, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	store.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+	store.DeleteRecordFromTable(dbModels.EnglishWord{}, translation.EnglishWordID)
+
+	_, err := Restore(store, EntityPolishWord, translation.PolishWordID, 1)
+	assert.NoError(t, err)
+	_, err = inner.GetTranslationById(translation.ID)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+
+	_, err = Restore(store, EntityTranslation, translation.ID, 2)
+	assert.Equal(t, customErrors.ErrEnglishWordNotFound, err)
+	Restore(store, EntityEnglishWord, translation.EnglishWordID, 1)
+	_, err = Restore(store, EntityTranslation, translation.ID, 2)
+	assert.NoError(t, err)
+	_, err = inner.GetTranslationById(translation.ID)
+	assert.NoError(t, err)
+}
+
+func TestRestoreFromTrashAddsRevisions(t *testing.T) {
+	inner, store := newStore()
+
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	store.DeleteRecordFromTable(dbModels.EnglishWord{}, translation.EnglishWordID)
+
+	restored, err := store.RestoreFromTrash(dbModels.EnglishWord{}, translation.EnglishWordID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cat", restored.(*dbModels.EnglishWord).Text)
+
+	wordRevisions, _ := inner.GetEnglishWordRevisions(translation.EnglishWordID)
+	assert.Len(t, wordRevisions, 3)
+	assert.False(t, wordRevisions[2].Deleted)
+	translationRevisions, _ := inner.GetTranslationRevisions(translation.ID)
+	assert.Len(t, translationRevisions, 3)
+	assert.False(t, translationRevisions[2].Deleted)
+	examples, _ := inner.GetExamplesByTranslationIds([]uint{translation.ID})
+	exampleRevisions, _ := inner.GetExampleRevisions(examples[0].ID)
+	assert.Len(t, exampleRevisions, 3)
+}
@@ -0,0 +1,80 @@
+package history
+
+import (
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+)
+
+// Restore brings the entity back to version, which becomes its next
+// revision. A deleted entity is recreated with its ID, along with the
+// translations and examples its deletion removed, except the ones whose
+// words or translation are gone by now. It returns the restored entity as a
+// *dbModels.PolishWord, *dbModels.EnglishWord, *dbModels.Translation or
+// *dbModels.Example.
+//
+// store should be a Store, or wrap one, for the restore to be recorded.
+func Restore(store database.DictionaryStore, entityType string, id uint, version uint) (interface{}, error) {
+	var restored interface{}
+	err := store.WithinTransaction(func(store database.DictionaryStore) (err error) {
+		restored, err = restore(store, ref{entityType, id}, version)
+		return err
+	})
+	return restored, err
+}
+
+func restore(store database.DictionaryStore, entity ref, version uint) (interface{}, error) {
+	recorded, err := revisions(store, entity)
+	if err != nil {
+		return nil, err
+	}
+	var target revision
+	for _, revision := range recorded {
+		if revision.Meta().Version == version {
+			target = revision
+		}
+	}
+	if target == nil {
+		return nil, customErrors.ErrRevisionNotFound
+	}
+	restored := record(target)
+	if err := store.RestoreRecord(restored); err != nil {
+		return nil, err
+	}
+	if latest := recorded[len(recorded)-1].Meta(); latest.Deleted {
+		if err := restoreDeletedWith(store, deletionKey(entity, latest.Version)); err != nil {
+			return nil, err
+		}
+	}
+	return restored, nil
+}
+
+// restoreDeletedWith recreates the translations, then the examples, deleted
+// along by deletion that were not restored since.
+func restoreDeletedWith(store database.DictionaryStore, deletion string) error {
+	translations, err := asRevisions(store.GetTranslationRevisionsByDeletion(deletion))
+	if err != nil {
+		return err
+	}
+	examples, err := asRevisions(store.GetExampleRevisionsByDeletion(deletion))
+	if err != nil {
+		return err
+	}
+	for _, deleted := range append(translations, examples...) {
+		restored := record(deleted)
+		entity, err := refOfRecord(restored)
+		if err != nil {
+			return err
+		}
+		recorded, err := revisions(store, entity)
+		if err != nil {
+			return err
+		}
+		if recorded[len(recorded)-1].Meta().Version != deleted.Meta().Version {
+			continue
+		}
+		if err := store.RestoreRecord(restored); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
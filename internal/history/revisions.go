@@ -0,0 +1,260 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// revision is implemented by the revision models through the embedded
+// dbModels.Revision.
+type revision interface {
+	Meta() *dbModels.Revision
+}
+
+// ref names an entity with a history.
+type ref struct {
+	entityType string
+	id         uint
+}
+
+// deletionKey names the deletion recorded as the given version of entity, it
+// is the DeletedWith of the revisions of the entities deleted along.
+func deletionKey(entity ref, version uint) string {
+	return fmt.Sprintf("%s:%d:%d", entity.entityType, entity.id, version)
+}
+
+// refOfTable returns the entity DeleteRecordFromTable deletes from table.
+func refOfTable(table interface{}, id uint) (ref, error) {
+	switch table.(type) {
+	case dbModels.PolishWord, *dbModels.PolishWord:
+		return ref{EntityPolishWord, id}, nil
+	case dbModels.EnglishWord, *dbModels.EnglishWord:
+		return ref{EntityEnglishWord, id}, nil
+	case dbModels.Translation, *dbModels.Translation:
+		return ref{EntityTranslation, id}, nil
+	case dbModels.Example, *dbModels.Example:
+		return ref{EntityExample, id}, nil
+	default:
+		return ref{}, fmt.Errorf("history: unsupported table %T", table)
+	}
+}
+
+// refOfRecord returns the entity RestoreRecord stores record as.
+func refOfRecord(record interface{}) (ref, error) {
+	switch record := record.(type) {
+	case *dbModels.PolishWord:
+		return ref{EntityPolishWord, record.ID}, nil
+	case *dbModels.EnglishWord:
+		return ref{EntityEnglishWord, record.ID}, nil
+	case *dbModels.Translation:
+		return ref{EntityTranslation, record.ID}, nil
+	case *dbModels.Example:
+		return ref{EntityExample, record.ID}, nil
+	default:
+		return ref{}, fmt.Errorf("history: unsupported record %T", record)
+	}
+}
+
+// table returns the table the entity is stored in.
+func (entity ref) table() interface{} {
+	switch entity.entityType {
+	case EntityPolishWord:
+		return &dbModels.PolishWord{}
+	case EntityEnglishWord:
+		return &dbModels.EnglishWord{}
+	case EntityTranslation:
+		return &dbModels.Translation{}
+	default:
+		return &dbModels.Example{}
+	}
+}
+
+// current returns the entity as it is now as a new revision, nil when it is
+// missing.
+func current(store database.DictionaryStore, entity ref) (revision, error) {
+	var (
+		next revision
+		err  error
+	)
+	switch entity.entityType {
+	case EntityPolishWord:
+		var word *dbModels.PolishWord
+		if word, err = store.GetPolishWordById(entity.id); err == nil {
+			next = &dbModels.PolishWordRevision{
+				PolishWordID: word.ID,
+				Text:         word.Text,
+				PartOfSpeech: word.PartOfSpeech,
+				Gender:       word.Gender,
+				Aspect:       word.Aspect,
+			}
+		}
+	case EntityEnglishWord:
+		var word *dbModels.EnglishWord
+		if word, err = store.GetEnglishWordById(entity.id); err == nil {
+			next = &dbModels.EnglishWordRevision{EnglishWordID: word.ID, Text: word.Text, PartOfSpeech: word.PartOfSpeech}
+		}
+	case EntityTranslation:
+		var translation *dbModels.Translation
+		if translation, err = store.GetTranslationById(entity.id); err == nil {
+			next = &dbModels.TranslationRevision{
+				TranslationID: translation.ID,
+				PolishWordID:  translation.PolishWordID,
+				EnglishWordID: translation.EnglishWordID,
+			}
+		}
+	case EntityExample:
+		var example *dbModels.Example
+		if example, err = store.GetExampleById(entity.id); err == nil {
+			next = &dbModels.ExampleRevision{
+				ExampleID:     example.ID,
+				TranslationID: example.TranslationID,
+				Text:          example.Text,
+				InPolish:      example.InPolish,
+			}
+		}
+	default:
+		return nil, customErrors.ErrUnknownEntity
+	}
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return next, err
+}
+
+// revisions returns the revisions of entity ordered by version.
+func revisions(store database.DictionaryStore, entity ref) ([]revision, error) {
+	switch entity.entityType {
+	case EntityPolishWord:
+		return asRevisions(store.GetPolishWordRevisions(entity.id))
+	case EntityEnglishWord:
+		return asRevisions(store.GetEnglishWordRevisions(entity.id))
+	case EntityTranslation:
+		return asRevisions(store.GetTranslationRevisions(entity.id))
+	case EntityExample:
+		return asRevisions(store.GetExampleRevisions(entity.id))
+	default:
+		return nil, customErrors.ErrUnknownEntity
+	}
+}
+
+func asRevisions[R revision](typed []R, err error) ([]revision, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make([]revision, len(typed))
+	for i, revision := range typed {
+		result[i] = revision
+	}
+	return result, nil
+}
+
+// values returns what revision holds of its entity, comparable with the
+// values of other revisions of the same type.
+func values(revision revision) any {
+	switch revision := revision.(type) {
+	case *dbModels.PolishWordRevision:
+		values := *revision
+		values.ID, values.Revision = 0, dbModels.Revision{}
+		return values
+	case *dbModels.EnglishWordRevision:
+		values := *revision
+		values.ID, values.Revision = 0, dbModels.Revision{}
+		return values
+	case *dbModels.TranslationRevision:
+		values := *revision
+		values.ID, values.Revision = 0, dbModels.Revision{}
+		return values
+	case *dbModels.ExampleRevision:
+		values := *revision
+		values.ID, values.Revision = 0, dbModels.Revision{}
+		return values
+	default:
+		return revision
+	}
+}
+
+// record returns the entity revision holds, for RestoreRecord.
+func record(revision revision) interface{} {
+	switch revision := revision.(type) {
+	case *dbModels.PolishWordRevision:
+		return &dbModels.PolishWord{
+			ID:           revision.PolishWordID,
+			Text:         revision.Text,
+			PartOfSpeech: revision.PartOfSpeech,
+			Gender:       revision.Gender,
+			Aspect:       revision.Aspect,
+		}
+	case *dbModels.EnglishWordRevision:
+		return &dbModels.EnglishWord{ID: revision.EnglishWordID, Text: revision.Text, PartOfSpeech: revision.PartOfSpeech}
+	case *dbModels.TranslationRevision:
+		return &dbModels.Translation{
+			ID:            revision.TranslationID,
+			PolishWordID:  revision.PolishWordID,
+			EnglishWordID: revision.EnglishWordID,
+		}
+	case *dbModels.ExampleRevision:
+		return &dbModels.Example{
+			ID:            revision.ExampleID,
+			TranslationID: revision.TranslationID,
+			Text:          revision.Text,
+			InPolish:      revision.InPolish,
+		}
+	default:
+		return nil
+	}
+}
+
+// deletedAlong returns the entities deleting entity removes with it.
+func deletedAlong(store database.DictionaryStore, entity ref) ([]ref, error) {
+	var (
+		translations []*dbModels.Translation
+		err          error
+	)
+	switch entity.entityType {
+	case EntityPolishWord:
+		translations, err = store.GetTranslationsByPolishWordIds([]uint{entity.id})
+	case EntityEnglishWord:
+		translations, err = store.GetTranslationsByEnglishWordIds([]uint{entity.id})
+	case EntityTranslation:
+		translations = []*dbModels.Translation{{ID: entity.id}}
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	along := []ref{}
+	ids := make([]uint, len(translations))
+	for i, translation := range translations {
+		if entity.entityType != EntityTranslation {
+			along = append(along, ref{EntityTranslation, translation.ID})
+		}
+		ids[i] = translation.ID
+	}
+	examples, err := store.GetExamplesByTranslationIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, example := range examples {
+		along = append(along, ref{EntityExample, example.ID})
+	}
+	return along, nil
+}
+
+func isNotFound(err error) bool {
+	for _, notFound := range []error{
+		customErrors.ErrPolishWordNotFound,
+		customErrors.ErrEnglishWordNotFound,
+		customErrors.ErrTranslationNotFound,
+		customErrors.ErrExampleNotFound,
+	} {
+		if errors.Is(err, notFound) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Package importer adds translations in bulk from CSV and TSV files.
+package importer
+
+import (
+	"errors"
+
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+)
+
+// Status is the outcome of importing a row, named like the GraphQL enum.
+type Status string
+
+const (
+	StatusCreated        Status = "CREATED"
+	StatusUpdated        Status = "UPDATED"
+	StatusAlreadyExisted Status = "ALREADY_EXISTED"
+	StatusFailed         Status = "FAILED"
+)
+
+// Options control how rows are written. BatchSize commits every BatchSize
+// rows in a transaction of their own, zero imports all rows in one. DryRun
+// imports all rows in one transaction and rolls it back.
+type Options struct {
+	DryRun    bool
+	BatchSize int
+}
+
+// RowResult reports the outcome of a row. TranslationID is zero for failed
+// rows and Reason empty for the other ones.
+type RowResult struct {
+	Line          int
+	PolishWord    string
+	EnglishWord   string
+	Status        Status
+	TranslationID uint
+	Reason        string
+}
+
+type Report struct {
+	DryRun         bool
+	Created        int
+	Updated        int
+	AlreadyExisted int
+	Failed         int
+	Rows           []RowResult
+}
+
+var errRollback = errors.New("importer: roll back dry run")
+
+// Import adds the translations of rows to store with the deduplication of
+// AddTranslation: existing words, translations and examples are reused, a
+// row adding examples to an existing translation is reported updated. A
+// failing row is reported and does not stop the others. When a transaction
+// cannot be committed the batches before it stay imported, and the report
+// of those batches is returned with the error.
+func Import(store database.DictionaryStore, rows []Row, options Options) (*Report, error) {
+	if options.BatchSize < 0 {
+		return nil, customErrors.ErrInvalidBatchSize
+	}
+	batchSize := options.BatchSize
+	if batchSize == 0 || options.DryRun {
+		batchSize = len(rows)
+	}
+
+	report := &Report{DryRun: options.DryRun, Rows: make([]RowResult, 0, len(rows))}
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+		var results []RowResult
+		err := store.WithinTransaction(func(tx database.DictionaryStore) error {
+			results = make([]RowResult, len(batch))
+			for i, row := range batch {
+				results[i] = importRow(tx, row)
+			}
+			if options.DryRun {
+				return errRollback
+			}
+			return nil
+		})
+		if err != nil && err != errRollback {
+			return report, err
+		}
+		for _, result := range results {
+			report.add(result)
+		}
+	}
+	return report, nil
+}
+
+func importRow(store database.DictionaryStore, row Row) RowResult {
+	result := RowResult{
+		Line:        row.Line,
+		PolishWord:  row.Input.PolishWord,
+		EnglishWord: row.Input.EnglishWord,
+		Status:      StatusCreated,
+	}
+	if row.Err != nil {
+		return result.failed(row.Err)
+	}
+	if existing, err := store.FindTranslation(row.Input); err == nil {
+		result.Status = StatusAlreadyExisted
+		// A translation gaining examples is updated rather than reused
+		for _, example := range row.Input.Examples {
+			if _, err := store.FindExample(existing.ID, example.Text); errors.Is(err, customErrors.ErrExampleNotFound) {
+				result.Status = StatusUpdated
+			} else if err != nil {
+				return result.failed(err)
+			}
+		}
+	} else if !errors.Is(err, customErrors.ErrTranslationNotFound) {
+		return result.failed(err)
+	}
+	translation, err := store.AddTranslation(row.Input)
+	if err != nil {
+		return result.failed(err)
+	}
+	result.TranslationID = translation.ID
+	return result
+}
+
+func (result RowResult) failed(err error) RowResult {
+	result.Status = StatusFailed
+	result.Reason = err.Error()
+	return result
+}
+
+func (report *Report) add(result RowResult) {
+	switch result.Status {
+	case StatusCreated:
+		report.Created++
+	case StatusUpdated:
+		report.Updated++
+	case StatusAlreadyExisted:
+		report.AlreadyExisted++
+	case StatusFailed:
+		report.Failed++
+	}
+	report.Rows = append(report.Rows, result)
+}
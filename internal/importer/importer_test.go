@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRowsWithHeader(t *testing.T) {
+	file := "\ufeffenglish,polish,examples\n" +
+		"dog,pies,\"Mam psa | Pies szczeka\"\n" +
+		"\n" +
+		"cat,,\n"
+
+	rows, err := ReadRows(strings.NewReader(file), FormatCSV)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "pies", rows[0].Input.PolishWord)
+	assert.Equal(t, "dog", rows[0].Input.EnglishWord)
+	assert.Equal(t, []*model.ExampleInput{{Text: "Mam psa", InPolish: true}, {Text: "Pies szczeka", InPolish: true}}, rows[0].Input.Examples)
+	assert.Nil(t, rows[0].Err)
+	assert.Equal(t, 4, rows[1].Line)
+	assert.EqualError(t, rows[1].Err, "polish word is empty")
+}
+
+func TestReadRowsTSVWithoutHeader(t *testing.T) {
+	rows, err := ReadRows(strings.NewReader("kot\tcat\t\tThe \"cat\" sleeps\n"), FormatTSV)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "kot", rows[0].Input.PolishWord)
+	assert.Equal(t, []*model.ExampleInput{{Text: `The "cat" sleeps`}}, rows[0].Input.Examples)
+}
+
+func TestReadRowsWithEscapedExampleSeparator(t *testing.T) {
+	rows, err := ReadRows(strings.NewReader("albo\tor\ttak \\| nie | C:\\psy\\\\ | C:\\koty\n"), FormatTSV)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ExampleInput{
+		{Text: "tak | nie", InPolish: true},
+		{Text: `C:\psy\`, InPolish: true},
+		{Text: `C:\koty`, InPolish: true},
+	}, rows[0].Input.Examples)
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	assert.Equal(t, FormatTSV, FormatFromFilename("words.TSV"))
+	assert.Equal(t, FormatCSV, FormatFromFilename("words.csv"))
+	assert.Equal(t, FormatCSV, FormatFromFilename("words"))
+}
+
+func TestImportReportsEveryRow(t *testing.T) {
+	store := memstore.NewStore()
+	store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	rows, _ := ReadRows(strings.NewReader("pies,dog\nkot,cat\n,bird\npies,dog\n"), FormatCSV)
+
+	report, err := Import(store, rows, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.AlreadyExisted)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, StatusCreated, report.Rows[0].Status)
+	assert.Equal(t, StatusFailed, report.Rows[2].Status)
+	assert.Equal(t, "polish word is empty", report.Rows[2].Reason)
+	assert.Equal(t, report.Rows[0].TranslationID, report.Rows[3].TranslationID)
+
+	translations, _ := store.GetTranslations()
+	assert.Len(t, translations, 2)
+}
+
+func TestImportReportsAddedExamplesAsUpdates(t *testing.T) {
+	store := memstore.NewStore()
+	store.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Kot śpi.", InPolish: true}},
+	})
+	rows, _ := ReadRows(strings.NewReader("kot,cat,Kot śpi.\nkot,cat,Kot je.\n"), FormatCSV)
+
+	for _, dryRun := range []bool{true, false} {
+		report, err := Import(store, rows, Options{DryRun: dryRun})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.AlreadyExisted)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, StatusUpdated, report.Rows[1].Status)
+	}
+}
+
+func TestImportDryRunDoesNotWrite(t *testing.T) {
+	store := memstore.NewStore()
+	rows, _ := ReadRows(strings.NewReader("pies,dog\npies,dog\n"), FormatCSV)
+
+	report, err := Import(store, rows, Options{DryRun: true, BatchSize: 1})
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.AlreadyExisted)
+
+	words, _ := store.GetPolishWords()
+	assert.Len(t, words, 0)
+}
+
+func TestImportInBatches(t *testing.T) {
+	store := memstore.NewStore()
+	rows, _ := ReadRows(strings.NewReader("pies,dog\nkot,cat\nkoń,horse\n"), FormatCSV)
+
+	report, err := Import(store, rows, Options{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Created)
+	translations, _ := store.GetTranslations()
+	assert.Len(t, translations, 3)
+
+	_, err = Import(store, rows, Options{BatchSize: -1})
+	assert.Equal(t, customErrors.ErrInvalidBatchSize, err)
+}
+
+// failingStore runs the first commits transactions and fails the later ones.
+type failingStore struct {
+	database.DictionaryStore
+	commits int
+}
+
+func (s *failingStore) WithinTransaction(fn func(store database.DictionaryStore) error) error {
+	if s.commits == 0 {
+		return errors.New("connection lost")
+	}
+	s.commits--
+	return s.DictionaryStore.WithinTransaction(fn)
+}
+
+func TestImportReportsCommittedBatchesOnError(t *testing.T) {
+	store := &failingStore{DictionaryStore: memstore.NewStore(), commits: 1}
+	rows, _ := ReadRows(strings.NewReader("pies,dog\nkot,cat\nkoń,horse\n"), FormatCSV)
+
+	report, err := Import(store, rows, Options{BatchSize: 2})
+	assert.EqualError(t, err, "connection lost")
+	assert.Equal(t, 2, report.Created)
+	assert.Len(t, report.Rows, 2)
+	assert.Equal(t, "kot", report.Rows[1].PolishWord)
+}
+
+func TestReadRowsWithGrammarColumns(t *testing.T) {
+	file := "polish,english,part_of_speech,polish_aspect\n" +
+		"biegać,run,verb,IMPERFECTIVE\n" +
+		"bieg,run,noun,round\n"
+
+	rows, err := ReadRows(strings.NewReader(file), FormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PartOfSpeechVerb, *rows[0].Input.PartOfSpeech)
+	assert.Equal(t, model.AspectImperfective, *rows[0].Input.PolishAspect)
+	assert.Nil(t, rows[0].Input.PolishGender)
+	assert.EqualError(t, rows[1].Err, `unknown aspect "round"`)
+}
@@ -0,0 +1,443 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// kaikkiEntry is the part of a Kaikki.org Wiktionary record the importer
+// reads, one record per line of the extract.
+type kaikkiEntry struct {
+	Word     string        `json:"word"`
+	Pos      string        `json:"pos"`
+	LangCode string        `json:"lang_code"`
+	Tags     []string      `json:"tags"`
+	Senses   []kaikkiSense `json:"senses"`
+	Forms    []kaikkiForm  `json:"forms"`
+}
+
+type kaikkiSense struct {
+	Glosses  []string          `json:"glosses"`
+	Tags     []string          `json:"tags"`
+	Examples []kaikkiExample   `json:"examples"`
+	FormOf   []json.RawMessage `json:"form_of"`
+	AltOf    []json.RawMessage `json:"alt_of"`
+}
+
+// kaikkiExample is a Polish usage example, older extracts name its English
+// translation "english".
+type kaikkiExample struct {
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+	English     string `json:"english"`
+}
+
+type kaikkiForm struct {
+	Form string   `json:"form"`
+	Tags []string `json:"tags"`
+}
+
+var kaikkiPartsOfSpeech = map[string]model.PartOfSpeech{
+	"noun":     model.PartOfSpeechNoun,
+	"verb":     model.PartOfSpeechVerb,
+	"adj":      model.PartOfSpeechAdjective,
+	"adv":      model.PartOfSpeechAdverb,
+	"pron":     model.PartOfSpeechPronoun,
+	"prep":     model.PartOfSpeechPreposition,
+	"conj":     model.PartOfSpeechConjunction,
+	"intj":     model.PartOfSpeechInterjection,
+	"num":      model.PartOfSpeechNumeral,
+	"particle": model.PartOfSpeechParticle,
+	"det":      model.PartOfSpeechDeterminer,
+}
+
+// Word parts and symbols are no dictionary words, other unknown parts of
+// speech like phrases are imported without one.
+var kaikkiSkippedPartsOfSpeech = map[string]bool{
+	"prefix": true, "suffix": true, "infix": true, "interfix": true, "affix": true,
+	"character": true, "symbol": true, "punct": true,
+}
+
+var kaikkiCases = map[string]model.GrammaticalCase{
+	"nominative":   model.GrammaticalCaseNominative,
+	"genitive":     model.GrammaticalCaseGenitive,
+	"dative":       model.GrammaticalCaseDative,
+	"accusative":   model.GrammaticalCaseAccusative,
+	"instrumental": model.GrammaticalCaseInstrumental,
+	"locative":     model.GrammaticalCaseLocative,
+	"vocative":     model.GrammaticalCaseVocative,
+}
+
+var kaikkiNumbers = map[string]model.GrammaticalNumber{
+	"singular": model.GrammaticalNumberSingular,
+	"plural":   model.GrammaticalNumberPlural,
+}
+
+var kaikkiPersons = map[string]model.Person{
+	"first-person":  model.PersonFirst,
+	"second-person": model.PersonSecond,
+	"third-person":  model.PersonThird,
+}
+
+// Tags of rows of the inflection tables that are no forms of the word.
+var kaikkiSkippedFormTags = map[string]bool{
+	"table-tags": true, "inflection-template": true, "class": true, "canonical": true, "romanization": true,
+}
+
+// maxGlossWords is the longest gloss taken as a translation, longer ones are
+// definitions rather than English words.
+const maxGlossWords = 4
+
+var parenthesized = regexp.MustCompile(`\([^()]*\)`)
+
+// kaikkiRecord is what a Kaikki entry adds to the dictionary.
+type kaikkiRecord struct {
+	translations []model.TranslationInput
+	forms        []dbModels.WordForm
+}
+
+// parseKaikkiEntry maps a Polish headword to a translation per English gloss
+// with the examples of its sense, and its inflected forms. ok is false for
+// entries with nothing to import.
+func parseKaikkiEntry(entry kaikkiEntry) (record kaikkiRecord, ok bool) {
+	if entry.Word == "" || (entry.LangCode != "" && entry.LangCode != "pl") || kaikkiSkippedPartsOfSpeech[entry.Pos] {
+		return record, false
+	}
+	var partOfSpeech *model.PartOfSpeech
+	if pos, known := kaikkiPartsOfSpeech[entry.Pos]; known {
+		partOfSpeech = &pos
+	}
+
+	seen := make(map[string]int)
+	for _, sense := range entry.Senses {
+		// Senses like "genitive singular of pies" belong to the lemma and
+		// are covered by its forms.
+		if len(sense.FormOf) > 0 || len(sense.AltOf) > 0 {
+			continue
+		}
+		gender, aspect := kaikkiGrammar(append(append([]string{}, entry.Tags...), sense.Tags...))
+		if partOfSpeech == nil || *partOfSpeech != model.PartOfSpeechNoun {
+			gender = nil
+		}
+		if partOfSpeech == nil || *partOfSpeech != model.PartOfSpeechVerb {
+			aspect = nil
+		}
+		examples := kaikkiExamples(sense.Examples)
+		for _, gloss := range sense.Glosses {
+			for _, english := range kaikkiTranslations(gloss, entry.Pos == "verb") {
+				if i, found := seen[english]; found {
+					record.translations[i].Examples = append(record.translations[i].Examples, examples...)
+					continue
+				}
+				seen[english] = len(record.translations)
+				record.translations = append(record.translations, model.TranslationInput{
+					PolishWord:   entry.Word,
+					EnglishWord:  english,
+					PartOfSpeech: partOfSpeech,
+					PolishGender: gender,
+					PolishAspect: aspect,
+					Examples:     slices.Clone(examples),
+				})
+			}
+		}
+	}
+	if len(record.translations) == 0 {
+		return record, false
+	}
+	record.forms = kaikkiForms(entry)
+	return record, true
+}
+
+// kaikkiTranslations splits a gloss like "dog, hound (animal)" into English
+// words, dropping explanations.
+func kaikkiTranslations(gloss string, verb bool) []string {
+	var translations []string
+	gloss = parenthesized.ReplaceAllString(gloss, "")
+	for _, part := range strings.FieldsFunc(gloss, func(r rune) bool { return r == ';' || r == ',' }) {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "."))
+		if verb {
+			part = strings.TrimPrefix(part, "to ")
+		}
+		if words := len(strings.Fields(part)); words == 0 || words > maxGlossWords {
+			continue
+		}
+		translations = append(translations, strings.Join(strings.Fields(part), " "))
+	}
+	return translations
+}
+
+func kaikkiGrammar(tags []string) (*model.Gender, *model.Aspect) {
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+	var gender model.Gender
+	switch {
+	case has["masculine"] && (has["personal"] || has["virile"]):
+		gender = model.GenderMasculinePersonal
+	case has["masculine"] && has["animate"]:
+		gender = model.GenderMasculineAnimate
+	case has["masculine"] && has["inanimate"]:
+		gender = model.GenderMasculineInanimate
+	case has["feminine"]:
+		gender = model.GenderFeminine
+	case has["neuter"]:
+		gender = model.GenderNeuter
+	}
+	var aspect model.Aspect
+	switch {
+	case has["imperfective"] && !has["perfective"]:
+		aspect = model.AspectImperfective
+	case has["perfective"] && !has["imperfective"]:
+		aspect = model.AspectPerfective
+	}
+	return enumOrNil(gender), enumOrNil(aspect)
+}
+
+func kaikkiExamples(examples []kaikkiExample) []*model.ExampleInput {
+	var inputs []*model.ExampleInput
+	for _, example := range examples {
+		if text := strings.TrimSpace(example.Text); text != "" {
+			inputs = append(inputs, &model.ExampleInput{Text: text, InPolish: true})
+		}
+		translation := example.Translation
+		if translation == "" {
+			translation = example.English
+		}
+		if translation = strings.TrimSpace(translation); translation != "" {
+			inputs = append(inputs, &model.ExampleInput{Text: translation, InPolish: false})
+		}
+	}
+	return inputs
+}
+
+// kaikkiForms keeps the inflected forms tagged with a case, number or
+// person, except the headword itself.
+func kaikkiForms(entry kaikkiEntry) []dbModels.WordForm {
+	var forms []dbModels.WordForm
+	seen := make(map[dbModels.WordForm]bool)
+forms:
+	for _, form := range entry.Forms {
+		text := strings.TrimSpace(form.Form)
+		if text == "" || text == entry.Word || text == "-" {
+			continue
+		}
+		wordForm := dbModels.WordForm{Text: text}
+		for _, tag := range form.Tags {
+			if kaikkiSkippedFormTags[tag] {
+				continue forms
+			}
+			if grammaticalCase, ok := kaikkiCases[tag]; ok {
+				wordForm.GrammaticalCase = string(grammaticalCase)
+			}
+			if number, ok := kaikkiNumbers[tag]; ok {
+				wordForm.Number = string(number)
+			}
+			if person, ok := kaikkiPersons[tag]; ok {
+				wordForm.Person = string(person)
+			}
+		}
+		if wordForm.GrammaticalCase == "" && wordForm.Number == "" && wordForm.Person == "" {
+			continue
+		}
+		if !seen[wordForm] {
+			seen[wordForm] = true
+			forms = append(forms, wordForm)
+		}
+	}
+	return forms
+}
+
+func enumOrNil[T ~string](value T) *T {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+const DefaultKaikkiBatchSize = 500
+
+// KaikkiOptions control a Kaikki import. Entries are committed BatchSize at
+// a time, after each batch the position in the file is saved to Checkpoint
+// so an interrupted import continues from there. Restart ignores a saved
+// position.
+type KaikkiOptions struct {
+	BatchSize  int
+	Checkpoint string
+	Restart    bool
+}
+
+// KaikkiReport counts what an import did. Lines counts the lines read in
+// this run, ResumedAt is the line it started after.
+type KaikkiReport struct {
+	ResumedAt    int
+	Lines        int
+	Entries      int
+	Skipped      int
+	Translations int
+	Forms        int
+}
+
+// kaikkiCheckpoint is the saved position of an import in its file. Size
+// tells whether it still belongs to the file.
+type kaikkiCheckpoint struct {
+	Size   int64 `json:"size"`
+	Offset int64 `json:"offset"`
+	Line   int   `json:"line"`
+}
+
+// ImportKaikki reads a Kaikki.org Wiktionary JSONL extract for Polish from
+// path and adds its headwords with their English glosses, examples and
+// inflected forms to store. Re-importing an entry changes nothing, as
+// translations, examples and forms are deduplicated. A finished import keeps
+// its checkpoint, running it again reads nothing unless Restart is set.
+func ImportKaikki(store database.DictionaryStore, path string, options KaikkiOptions) (*KaikkiReport, error) {
+	if options.BatchSize < 0 {
+		return nil, customErrors.ErrInvalidBatchSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = DefaultKaikkiBatchSize
+	}
+	if options.Checkpoint == "" {
+		options.Checkpoint = path + ".progress"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := kaikkiCheckpoint{Size: info.Size()}
+	if !options.Restart {
+		if checkpoint, err = loadCheckpoint(options.Checkpoint, info.Size()); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := file.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	report := &KaikkiReport{ResumedAt: checkpoint.Line}
+	reader := bufio.NewReader(file)
+	for done := false; !done; {
+		var batch []kaikkiRecord
+		var lines []int
+		for len(batch) < options.BatchSize {
+			line, err := reader.ReadBytes('\n')
+			if err == io.EOF {
+				done = true
+			} else if err != nil {
+				return report, err
+			}
+			if len(line) == 0 {
+				break
+			}
+			checkpoint.Offset += int64(len(line))
+			checkpoint.Line++
+			report.Lines++
+			if len(strings.TrimSpace(string(line))) == 0 {
+				continue
+			}
+			var entry kaikkiEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return report, fmt.Errorf("line %d: %w", checkpoint.Line, err)
+			}
+			report.Entries++
+			record, ok := parseKaikkiEntry(entry)
+			if !ok {
+				report.Skipped++
+				continue
+			}
+			batch = append(batch, record)
+			lines = append(lines, checkpoint.Line)
+		}
+
+		err := store.WithinTransaction(func(tx database.DictionaryStore) error {
+			for i, record := range batch {
+				if err := importKaikkiRecord(tx, record, report); err != nil {
+					return fmt.Errorf("line %d: %w", lines[i], err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		if err := saveCheckpoint(options.Checkpoint, checkpoint); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+func importKaikkiRecord(store database.DictionaryStore, record kaikkiRecord, report *KaikkiReport) error {
+	var polishWordID uint
+	for _, input := range record.translations {
+		translation, err := store.AddTranslation(input)
+		if errors.Is(err, customErrors.ErrGrammarMismatch) {
+			// Senses and homonyms may disagree, the grammar stored first stays
+			input.PolishGender, input.PolishAspect = nil, nil
+			translation, err = store.AddTranslation(input)
+		}
+		if err != nil {
+			return err
+		}
+		polishWordID = translation.PolishWordID
+		report.Translations++
+	}
+	if len(record.forms) > 0 {
+		if _, err := store.SetPolishWordForms(polishWordID, record.forms, false); err != nil {
+			return err
+		}
+		report.Forms += len(record.forms)
+	}
+	return nil
+}
+
+func loadCheckpoint(path string, size int64) (kaikkiCheckpoint, error) {
+	checkpoint := kaikkiCheckpoint{Size: size}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, err
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Size != size || checkpoint.Offset > size {
+		return checkpoint, customErrors.ErrCheckpointMismatch
+	}
+	return checkpoint, nil
+}
+
+// saveCheckpoint replaces the checkpoint at once, an interruption leaves
+// either the old or the new one.
+func saveCheckpoint(path string, checkpoint kaikkiCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temporary, path)
+}
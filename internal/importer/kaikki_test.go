@@ -0,0 +1,125 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+var kaikkiLines = []string{
+	`{"word": "pies", "pos": "noun", "lang_code": "pl", "senses": [` +
+		`{"glosses": ["dog (animal)"], "tags": ["masculine", "animate"], "examples": [{"text": "Pies szczeka.", "english": "The dog barks."}]},` +
+		`{"glosses": ["hound; dog"], "tags": ["masculine", "animate"]},` +
+		`{"glosses": ["a contemptible person who behaves badly towards others"]}],` +
+		`"forms": [{"form": "pies", "tags": ["canonical"]}, {"form": "psa", "tags": ["genitive", "singular"]},` +
+		`{"form": "psa", "tags": ["accusative", "singular"]}, {"form": "psy", "tags": ["nominative", "plural"]},` +
+		`{"form": "noun-table", "tags": ["table-tags"]}]}`,
+	`{"word": "psa", "pos": "noun", "lang_code": "pl", "senses": [{"glosses": ["genitive singular of pies"], "form_of": [{"word": "pies"}]}]}`,
+	`{"word": "biegać", "pos": "verb", "lang_code": "pl", "senses": [{"glosses": ["to run"], "tags": ["imperfective"],` +
+		`"examples": [{"text": "Lubię biegać.", "translation": "I like to run."}]}],` +
+		`"forms": [{"form": "biegam", "tags": ["first-person", "singular", "present"]}]}`,
+	`{"word": "-ość", "pos": "suffix", "lang_code": "pl", "senses": [{"glosses": ["-ness"]}]}`,
+	`{"word": "Hund", "pos": "noun", "lang_code": "de", "senses": [{"glosses": ["dog"]}]}`,
+}
+
+func writeKaikkiFile(t *testing.T, lines []string) string {
+	path := filepath.Join(t.TempDir(), "kaikki.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+	return path
+}
+
+func TestImportKaikki(t *testing.T) {
+	store := memstore.NewStore()
+	path := writeKaikkiFile(t, kaikkiLines)
+
+	report, err := ImportKaikki(store, path, KaikkiOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, KaikkiReport{Lines: 5, Entries: 5, Skipped: 3, Translations: 3, Forms: 4}, *report)
+
+	translations, _ := store.GetTranslationsToEnglish("pies", database.LookupOptions{})
+	assert.Len(t, translations, 2)
+	store.PopulateTranslationsWithAssociations(translations)
+	assert.Equal(t, "dog", translations[0].EnglishWord.Text)
+	assert.Equal(t, "hound", translations[1].EnglishWord.Text)
+	assert.Equal(t, "MASCULINE_ANIMATE", translations[0].PolishWord.Gender)
+	assert.Equal(t, "NOUN", translations[0].PolishWord.PartOfSpeech)
+	assert.Len(t, translations[0].Examples, 2)
+	assert.True(t, translations[0].Examples[0].InPolish)
+	assert.Equal(t, "The dog barks.", translations[0].Examples[1].Text)
+	assert.False(t, translations[0].Examples[1].InPolish)
+
+	translations, _ = store.GetTranslationsToEnglish("psy", database.LookupOptions{IncludeForms: true})
+	assert.Len(t, translations, 2)
+	translations, _ = store.GetTranslationsToPolish("run", database.LookupOptions{})
+	assert.Len(t, translations, 1)
+	store.PopulateTranslationWithAssociations(translations[0])
+	assert.Equal(t, "IMPERFECTIVE", translations[0].PolishWord.Aspect)
+	forms, _ := store.GetWordFormsByPolishWordIds([]uint{translations[0].PolishWordID})
+	assert.Equal(t, "biegam", forms[0].Text)
+	assert.Equal(t, string(model.PersonFirst), forms[0].Person)
+
+	englishWords, _ := store.GetEnglishWords()
+	assert.Len(t, englishWords, 3)
+}
+
+func TestImportKaikkiKeepsGrammarStoredFirst(t *testing.T) {
+	store := memstore.NewStore()
+	path := writeKaikkiFile(t, []string{
+		`{"word": "bal", "pos": "noun", "senses": [{"glosses": ["ball (dance)"], "tags": ["masculine", "inanimate"]}]}`,
+		`{"word": "bal", "pos": "noun", "senses": [{"glosses": ["bale"], "tags": ["feminine"]}]}`,
+		`{"word": "mały", "pos": "adj", "senses": [{"glosses": ["small"], "tags": ["masculine"]}]}`,
+	})
+
+	report, err := ImportKaikki(store, path, KaikkiOptions{BatchSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Translations)
+	polishWords, _ := store.GetPolishWords()
+	assert.Len(t, polishWords, 2)
+	assert.Equal(t, "MASCULINE_INANIMATE", polishWords[0].Gender)
+	assert.Empty(t, polishWords[1].Gender, "only nouns have a gender")
+}
+
+func TestImportKaikkiResumes(t *testing.T) {
+	store := memstore.NewStore()
+	path := writeKaikkiFile(t, append([]string{kaikkiLines[0], "{not json"}, kaikkiLines[2]))
+
+	report, err := ImportKaikki(store, path, KaikkiOptions{BatchSize: 1})
+	assert.ErrorContains(t, err, "line 2")
+	assert.Equal(t, 2, report.Translations)
+
+	// Fix the broken line, the file keeps its size.
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(data), "{not json", "         ", 1)), 0o644)
+	report, err = ImportKaikki(store, path, KaikkiOptions{BatchSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.ResumedAt)
+	assert.Equal(t, 2, report.Lines)
+	assert.Equal(t, 1, report.Translations)
+
+	report, err = ImportKaikki(store, path, KaikkiOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Lines)
+
+	os.WriteFile(path, []byte(kaikkiLines[0]+"\n"), 0o644)
+	_, err = ImportKaikki(store, path, KaikkiOptions{})
+	assert.Equal(t, customErrors.ErrCheckpointMismatch, err)
+	report, err = ImportKaikki(store, path, KaikkiOptions{Restart: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Lines)
+
+	translations, _ := store.GetTranslations()
+	assert.Len(t, translations, 3)
+}
+
+func TestKaikkiTranslations(t *testing.T) {
+	assert.Equal(t, []string{"dog", "hound"}, kaikkiTranslations("dog, hound (animal).", false))
+	assert.Equal(t, []string{"run", "jog"}, kaikkiTranslations("to run; to jog", true))
+	assert.Empty(t, kaikkiTranslations("a person who is not very nice at all", false))
+}
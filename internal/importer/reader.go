@@ -0,0 +1,212 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+)
+
+// Format is the layout of an imported file, named like the GraphQL enum.
+type Format string
+
+const (
+	FormatCSV Format = "CSV"
+	FormatTSV Format = "TSV"
+)
+
+// ExampleSeparator separates the examples listed in one cell. A backslash
+// escapes it, or another backslash, inside an example.
+const ExampleSeparator = "|"
+
+var exampleEscaper = strings.NewReplacer(`\`, `\\`, ExampleSeparator, `\`+ExampleSeparator)
+
+// JoinExamples lists texts in one cell, so that they are read back as they
+// are.
+func JoinExamples(texts []string) string {
+	escaped := make([]string, len(texts))
+	for i, text := range texts {
+		escaped[i] = exampleEscaper.Replace(text)
+	}
+	return strings.Join(escaped, " "+ExampleSeparator+" ")
+}
+
+// Columns of an imported file in their default order. A header row naming
+// them may reorder them or add the grammatical columns, "examples" is
+// accepted for polish_examples. Unknown columns are ignored.
+const (
+	columnPolish          = "polish"
+	columnEnglish         = "english"
+	columnPolishExamples  = "polish_examples"
+	columnEnglishExamples = "english_examples"
+	columnPartOfSpeech    = "part_of_speech"
+	columnPolishGender    = "polish_gender"
+	columnPolishAspect    = "polish_aspect"
+)
+
+var defaultColumns = []string{columnPolish, columnEnglish, columnPolishExamples, columnEnglishExamples}
+
+// Row is a translation read from an imported file. Line is where it starts
+// in the file, Err is set when the row cannot be imported.
+type Row struct {
+	Line  int
+	Input model.TranslationInput
+	Err   error
+}
+
+// FormatFromFilename picks TSV for .tsv and .tab files and CSV otherwise.
+func FormatFromFilename(name string) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tsv", ".tab":
+		return FormatTSV
+	default:
+		return FormatCSV
+	}
+}
+
+// ReadRows parses a whole CSV or TSV file. Malformed files fail as a whole,
+// rows missing a word are returned with Err set.
+func ReadRows(r io.Reader, format Format) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	switch format {
+	case FormatCSV:
+		reader.TrimLeadingSpace = true
+	case FormatTSV:
+		reader.Comma = '\t'
+		// Spreadsheets do not quote tab separated values. Leading space is
+		// not trimmed by the reader, it would swallow empty fields.
+		reader.LazyQuotes = true
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+
+	columns := defaultColumns
+	rows := []Row{}
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if header, ok := parseHeader(record); ok {
+				columns = header
+				continue
+			}
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+		rows = append(rows, parseRow(line, columns, record))
+	}
+}
+
+func parseHeader(record []string) ([]string, bool) {
+	columns := make([]string, len(record))
+	known := false
+	for i, cell := range record {
+		column := strings.ToLower(strings.TrimSpace(cell))
+		if column == "examples" {
+			column = columnPolishExamples
+		}
+		columns[i] = column
+		known = known || column == columnPolish || column == columnEnglish
+	}
+	return columns, known
+}
+
+func parseRow(line int, columns []string, record []string) Row {
+	row := Row{Line: line}
+	for i, cell := range record {
+		if i >= len(columns) {
+			break
+		}
+		cell = strings.TrimSpace(cell)
+		switch columns[i] {
+		case columnPolish:
+			row.Input.PolishWord = cell
+		case columnEnglish:
+			row.Input.EnglishWord = cell
+		case columnPolishExamples:
+			row.Input.Examples = append(row.Input.Examples, splitExamples(cell, true)...)
+		case columnEnglishExamples:
+			row.Input.Examples = append(row.Input.Examples, splitExamples(cell, false)...)
+		case columnPartOfSpeech:
+			row.Input.PartOfSpeech = parseEnum[model.PartOfSpeech](cell, "part of speech", &row.Err)
+		case columnPolishGender:
+			row.Input.PolishGender = parseEnum[model.Gender](cell, "gender", &row.Err)
+		case columnPolishAspect:
+			row.Input.PolishAspect = parseEnum[model.Aspect](cell, "aspect", &row.Err)
+		}
+	}
+	switch {
+	case row.Err != nil:
+		return row
+	case row.Input.PolishWord == "":
+		row.Err = errors.New("polish word is empty")
+	case row.Input.EnglishWord == "":
+		row.Err = errors.New("english word is empty")
+	}
+	return row
+}
+
+// parseEnum reads an optional grammatical value, written like the GraphQL
+// enum in any case. Invalid values are reported through err.
+func parseEnum[T interface {
+	~string
+	IsValid() bool
+}](cell string, name string, err *error) *T {
+	if cell == "" {
+		return nil
+	}
+	value := T(strings.ToUpper(cell))
+	if !value.IsValid() {
+		*err = fmt.Errorf("unknown %s %q", name, cell)
+		return nil
+	}
+	return &value
+}
+
+func splitExamples(cell string, inPolish bool) []*model.ExampleInput {
+	var texts []string
+	var text strings.Builder
+	for i := 0; i < len(cell); i++ {
+		switch {
+		case cell[i] == '\\' && i+1 < len(cell) && (cell[i+1] == '\\' || cell[i+1] == ExampleSeparator[0]):
+			i++
+			text.WriteByte(cell[i])
+		case cell[i] == ExampleSeparator[0]:
+			texts = append(texts, text.String())
+			text.Reset()
+		default:
+			text.WriteByte(cell[i])
+		}
+	}
+	texts = append(texts, text.String())
+
+	var examples []*model.ExampleInput
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			examples = append(examples, &model.ExampleInput{Text: text, InPolish: inPolish})
+		}
+	}
+	return examples
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,1553 @@
+package memstore
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"github.com/realagmag/dictionaryGO/internal/srs"
+	"gorm.io/gorm"
+)
+
+// Store is an in-memory DictionaryStore. It mirrors the constraints the
+// Postgres schema enforces for DBManager, so it can stand in for it in tests
+// and in demo mode. Records are handed out as copies, never as references to
+// the internal state.
+type Store struct {
+	mu sync.RWMutex
+
+	polishWords  map[uint]dbModels.PolishWord
+	englishWords map[uint]dbModels.EnglishWord
+	translations map[uint]dbModels.Translation
+	examples     map[uint]dbModels.Example
+	wordForms    map[uint]dbModels.WordForm
+	reviewCards  map[uint]dbModels.ReviewCard
+	tags         map[uint]dbModels.TranslationTag
+	users        map[uint]dbModels.User
+	apiKeys      map[uint]dbModels.APIKey
+	auditEntries map[uint]dbModels.AuditEntry
+
+	polishWordRevisions  map[uint]dbModels.PolishWordRevision
+	englishWordRevisions map[uint]dbModels.EnglishWordRevision
+	translationRevisions map[uint]dbModels.TranslationRevision
+	exampleRevisions     map[uint]dbModels.ExampleRevision
+
+	// Deleted records wait in the trash with their DeletedAt set, the forms,
+	// cards and tags beneath them stay where they are.
+	trashedPolishWords  map[uint]dbModels.PolishWord
+	trashedEnglishWords map[uint]dbModels.EnglishWord
+	trashedTranslations map[uint]dbModels.Translation
+	trashedExamples     map[uint]dbModels.Example
+
+	lastPolishWordID  uint
+	lastEnglishWordID uint
+	lastTranslationID uint
+	lastExampleID     uint
+	lastWordFormID    uint
+	lastReviewCardID  uint
+	lastTagID         uint
+	lastUserID        uint
+	lastAPIKeyID      uint
+	lastAuditEntryID  uint
+	lastRevisionID    uint
+}
+
+var _ database.DictionaryStore = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{
+		polishWords:  make(map[uint]dbModels.PolishWord),
+		englishWords: make(map[uint]dbModels.EnglishWord),
+		translations: make(map[uint]dbModels.Translation),
+		examples:     make(map[uint]dbModels.Example),
+		wordForms:    make(map[uint]dbModels.WordForm),
+		reviewCards:  make(map[uint]dbModels.ReviewCard),
+		tags:         make(map[uint]dbModels.TranslationTag),
+		users:        make(map[uint]dbModels.User),
+		apiKeys:      make(map[uint]dbModels.APIKey),
+		auditEntries: make(map[uint]dbModels.AuditEntry),
+
+		polishWordRevisions:  make(map[uint]dbModels.PolishWordRevision),
+		englishWordRevisions: make(map[uint]dbModels.EnglishWordRevision),
+		translationRevisions: make(map[uint]dbModels.TranslationRevision),
+		exampleRevisions:     make(map[uint]dbModels.ExampleRevision),
+
+		trashedPolishWords:  make(map[uint]dbModels.PolishWord),
+		trashedEnglishWords: make(map[uint]dbModels.EnglishWord),
+		trashedTranslations: make(map[uint]dbModels.Translation),
+		trashedExamples:     make(map[uint]dbModels.Example),
+	}
+}
+
+func (s *Store) AddPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	polishWord, err := s.addPolishWord(word)
+	if err != nil {
+		return nil, err
+	}
+	return &polishWord, nil
+}
+
+func (s *Store) AddEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	englishWord := s.addEnglishWord(word)
+	return &englishWord, nil
+}
+
+func (s *Store) GetPolishWords() ([]*dbModels.PolishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	words := make([]*dbModels.PolishWord, 0, len(s.polishWords))
+	for _, id := range sortedKeys(s.polishWords) {
+		word := s.polishWords[id]
+		words = append(words, &word)
+	}
+	return words, nil
+}
+
+func (s *Store) GetEnglishWords() ([]*dbModels.EnglishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	words := make([]*dbModels.EnglishWord, 0, len(s.englishWords))
+	for _, id := range sortedKeys(s.englishWords) {
+		word := s.englishWords[id]
+		words = append(words, &word)
+	}
+	return words, nil
+}
+
+func (s *Store) GetPolishWordsPage(page database.PageRequest) (*database.Page[dbModels.PolishWord], error) {
+	words, _ := s.GetPolishWords()
+	return database.PaginateSlice(words, func(word *dbModels.PolishWord) uint { return word.ID }, page)
+}
+
+func (s *Store) GetEnglishWordsPage(page database.PageRequest) (*database.Page[dbModels.EnglishWord], error) {
+	words, _ := s.GetEnglishWords()
+	return database.PaginateSlice(words, func(word *dbModels.EnglishWord) uint { return word.ID }, page)
+}
+
+func (s *Store) SetPolishWordForms(polishWordID uint, forms []dbModels.WordForm, replace bool) ([]*dbModels.WordForm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.polishWords[polishWordID]; !ok {
+		return nil, customErrors.ErrPolishWordNotFound
+	}
+	if replace {
+		s.deleteWordFormsOf(polishWordID)
+	}
+	for _, form := range forms {
+		form.PolishWordID = polishWordID
+		if _, found := s.findWordForm(form); found {
+			continue
+		}
+		s.lastWordFormID++
+		form.ID = s.lastWordFormID
+		form.UpdateSearchKeys()
+		s.wordForms[form.ID] = form
+	}
+	return s.wordFormsOf([]uint{polishWordID}), nil
+}
+
+func (s *Store) AddTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	polishWordInput, englishWordInput := database.TranslationWords(translationInput)
+	polishWord, err := s.addPolishWord(polishWordInput)
+	if err != nil {
+		return nil, err
+	}
+	englishWord := s.addEnglishWord(englishWordInput)
+
+	translation, found := s.findTranslation(polishWord.ID, englishWord.ID)
+	if !found {
+		s.lastTranslationID++
+		translation = dbModels.Translation{
+			ID:            s.lastTranslationID,
+			PolishWordID:  polishWord.ID,
+			EnglishWordID: englishWord.ID,
+		}
+		s.translations[translation.ID] = translation
+	}
+	for _, example := range translationInput.Examples {
+		if _, err := s.addExample(example, translation.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &translation, nil
+}
+
+func (s *Store) FindPolishWord(word dbModels.PolishWord) (*dbModels.PolishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	polishWord, found := s.findPolishWord(word.Text, word.PartOfSpeech)
+	if !found {
+		return nil, customErrors.ErrPolishWordNotFound
+	}
+	return &polishWord, nil
+}
+
+func (s *Store) FindEnglishWord(word dbModels.EnglishWord) (*dbModels.EnglishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	englishWord, found := s.findEnglishWord(word.Text, word.PartOfSpeech)
+	if !found {
+		return nil, customErrors.ErrEnglishWordNotFound
+	}
+	return &englishWord, nil
+}
+
+func (s *Store) FindExample(translationID uint, text string) (*dbModels.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	example, found := s.findExample(translationID, text)
+	if !found {
+		return nil, customErrors.ErrExampleNotFound
+	}
+	return &example, nil
+}
+
+func (s *Store) FindTranslation(translationInput model.TranslationInput) (*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	polishWordInput, englishWordInput := database.TranslationWords(translationInput)
+	polishWord, foundPolish := s.findPolishWord(polishWordInput.Text, polishWordInput.PartOfSpeech)
+	englishWord, foundEnglish := s.findEnglishWord(englishWordInput.Text, englishWordInput.PartOfSpeech)
+	if !foundPolish || !foundEnglish {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+	translation, found := s.findTranslation(polishWord.ID, englishWord.ID)
+	if !found {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+	return &translation, nil
+}
+
+func (s *Store) AddExampleToTranslation(example *model.ExampleInput, translationID uint) (*dbModels.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dbExample, err := s.addExample(example, translationID)
+	if err != nil {
+		return nil, err
+	}
+	return &dbExample, nil
+}
+
+func (s *Store) PopulateTranslationWithAssociations(translation *dbModels.Translation) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.populateTranslation(translation, s.examplesByTranslation())
+}
+
+func (s *Store) PopulateTranslationsWithAssociations(translations []*dbModels.Translation) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	examples := s.examplesByTranslation()
+	for _, translation := range translations {
+		if err := s.populateTranslation(translation, examples); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetTranslations() ([]*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filterTranslations(func(dbModels.Translation) bool { return true }), nil
+}
+
+func (s *Store) GetTranslationsPage(page database.PageRequest) (*database.Page[dbModels.Translation], error) {
+	translations, _ := s.GetTranslations()
+	return database.PaginateSlice(translations, func(translation *dbModels.Translation) uint { return translation.ID }, page)
+}
+
+func (s *Store) SetTranslationTags(translationID uint, tags []string) ([]*dbModels.TranslationTag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.translations[translationID]; !ok {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+	for id, tag := range s.tags {
+		if tag.TranslationID == translationID {
+			delete(s.tags, id)
+		}
+	}
+	for _, name := range database.TagNames(tags) {
+		s.lastTagID++
+		s.tags[s.lastTagID] = dbModels.TranslationTag{ID: s.lastTagID, TranslationID: translationID, Name: name}
+	}
+	return s.tagsOf([]uint{translationID}), nil
+}
+
+func (s *Store) GetTranslationsByTag(tag string) ([]*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	name := normalize.Tag(tag)
+	tagged := make(map[uint]bool)
+	for _, tag := range s.tags {
+		if tag.Name == name {
+			tagged[tag.TranslationID] = true
+		}
+	}
+	return s.filterTranslations(func(translation dbModels.Translation) bool { return tagged[translation.ID] }), nil
+}
+
+func (s *Store) SampleTranslations(tag string, limit int, seed uint64) ([]*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tagged := make(map[uint]bool)
+	for _, t := range s.tags {
+		if t.Name == normalize.Tag(tag) {
+			tagged[t.TranslationID] = true
+		}
+	}
+	translations := s.filterTranslations(func(translation dbModels.Translation) bool {
+		return tag == "" || tagged[translation.ID]
+	})
+	sort.SliceStable(translations, func(i, j int) bool {
+		return database.SampleKey(translations[i].ID, seed) < database.SampleKey(translations[j].ID, seed)
+	})
+	return translations[:min(limit, len(translations))], nil
+}
+
+func (s *Store) GetQuizWords(language model.Language, seed uint64, limit int) ([]*database.QuizWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	words := []*database.QuizWord{}
+	switch language {
+	case model.LanguagePolish:
+		for _, word := range s.polishWords {
+			words = append(words, &database.QuizWord{ID: word.ID, Text: word.Text, PartOfSpeech: word.PartOfSpeech})
+		}
+	case model.LanguageEnglish:
+		for _, word := range s.englishWords {
+			words = append(words, &database.QuizWord{ID: word.ID, Text: word.Text, PartOfSpeech: word.PartOfSpeech})
+		}
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if a, b := database.SampleKey(words[i].ID, seed), database.SampleKey(words[j].ID, seed); a != b {
+			return a < b
+		}
+		return words[i].ID < words[j].ID
+	})
+	return words[:min(limit, len(words))], nil
+}
+
+func (s *Store) GetTranslationsToEnglish(wordInPolish string, options database.LookupOptions) ([]*dbModels.Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matched := make(map[uint]bool)
+	for id, word := range s.polishWords {
+		if options.MatchesPolishWord(word, wordInPolish) {
+			matched[id] = true
+		}
+	}
+	for _, form := range s.wordForms {
+		if word, ok := s.polishWords[form.PolishWordID]; ok && options.MatchesPolishForm(word, form, wordInPolish) {
+			matched[word.ID] = true
+		}
+	}
+	for id := range matched {
+		word := s.polishWords[id]
+		word.LookupCount++
+		s.polishWords[id] = word
+	}
+	return s.filterTranslations(func(translation dbModels.Translation) bool {
+		return matched[translation.PolishWordID]
+	}), nil
+}
+
+func (s *Store) GetTranslationsToPolish(wordInEnglish string, options database.LookupOptions) ([]*dbModels.Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, word := range s.englishWords {
+		if options.MatchesEnglishWord(word, wordInEnglish) {
+			word.LookupCount++
+			s.englishWords[id] = word
+		}
+	}
+	return s.filterTranslations(func(translation dbModels.Translation) bool {
+		return options.MatchesEnglishWord(s.englishWords[translation.EnglishWordID], wordInEnglish)
+	}), nil
+}
+
+func (s *Store) GetSuggestions(word string, language model.Language, limit int) ([]*database.Suggestion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var candidates []database.Suggestion
+	switch language {
+	case model.LanguagePolish:
+		for _, word := range s.polishWords {
+			candidates = append(candidates, database.Suggestion{ID: word.ID, Text: word.Text})
+		}
+	case model.LanguageEnglish:
+		for _, word := range s.englishWords {
+			candidates = append(candidates, database.Suggestion{ID: word.ID, Text: word.Text})
+		}
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+	return database.RankSuggestions(word, candidates, limit)
+}
+
+func (s *Store) Autocomplete(prefix string, language model.Language, ranking model.AutocompleteRanking, limit int) ([]*database.WordEntry, error) {
+	if err := database.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	translationCounts := make(map[uint]int64)
+	var entries []*database.WordEntry
+	switch language {
+	case model.LanguagePolish:
+		for _, translation := range s.translations {
+			translationCounts[translation.PolishWordID]++
+		}
+		for _, word := range s.polishWords {
+			entries = append(entries, &database.WordEntry{ID: word.ID, Text: word.Text, FoldedKey: word.FoldedKey, LookupCount: int64(word.LookupCount)})
+		}
+	case model.LanguageEnglish:
+		for _, translation := range s.translations {
+			translationCounts[translation.EnglishWordID]++
+		}
+		for _, word := range s.englishWords {
+			entries = append(entries, &database.WordEntry{ID: word.ID, Text: word.Text, FoldedKey: word.FoldedKey, LookupCount: int64(word.LookupCount)})
+		}
+	default:
+		return nil, customErrors.ErrUnknownLanguage
+	}
+
+	matching := []*database.WordEntry{}
+	for _, entry := range entries {
+		if database.HasPrefix(entry.Text, prefix) {
+			entry.TranslationCount = translationCounts[entry.ID]
+			matching = append(matching, entry)
+		}
+	}
+	database.SortWordEntries(matching, ranking)
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	return matching, nil
+}
+
+func (s *Store) SearchExamples(query string, language *model.Language, page database.PageRequest) (*database.Page[database.ExampleMatch], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	examples := make([]*database.ExampleMatch, 0, len(s.examples))
+	for _, id := range sortedKeys(s.examples) {
+		example := s.examples[id]
+		examples = append(examples, &database.ExampleMatch{
+			ID:            example.ID,
+			TranslationID: example.TranslationID,
+			Text:          example.Text,
+			InPolish:      example.InPolish,
+			Version:       example.Version,
+		})
+	}
+	return database.SearchExampleSlice(examples, query, language, page)
+}
+
+func (s *Store) GetDueCards(learner string, directions []string, now time.Time, limit int) ([]*dbModels.ReviewCard, error) {
+	if err := database.ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	if err := database.ValidateStudy(learner, directions); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now = now.UTC()
+	cards := []*dbModels.ReviewCard{}
+	reviewed := make(map[string]map[uint]bool, len(directions))
+	for _, direction := range directions {
+		reviewed[direction] = make(map[uint]bool)
+	}
+	for _, id := range sortedKeys(s.reviewCards) {
+		card := s.reviewCards[id]
+		if _, ok := s.translations[card.TranslationID]; !ok || card.Learner != learner || reviewed[card.Direction] == nil {
+			continue
+		}
+		reviewed[card.Direction][card.TranslationID] = true
+		if !card.DueAt.After(now) {
+			cards = append(cards, &card)
+		}
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].DueAt.Before(cards[j].DueAt) })
+	if len(cards) > limit {
+		cards = cards[:limit]
+	}
+
+	unreviewed := make(map[string][]uint, len(directions))
+	for _, id := range sortedKeys(s.translations) {
+		for _, direction := range directions {
+			if !reviewed[direction][id] {
+				unreviewed[direction] = append(unreviewed[direction], id)
+			}
+		}
+	}
+	cards = append(cards, database.NewCards(learner, directions, unreviewed, now, limit-len(cards))...)
+	for _, card := range cards {
+		card.Translation = s.translations[card.TranslationID]
+	}
+	return cards, nil
+}
+
+func (s *Store) ReviewCard(learner string, translationID uint, direction string, grade int, now time.Time) (*dbModels.ReviewCard, error) {
+	if err := database.ValidateStudy(learner, []string{direction}); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	translation, ok := s.translations[translationID]
+	if !ok {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+
+	card, found := s.findReviewCard(learner, translationID, direction)
+	if !found {
+		card = srs.NewCard(learner, translationID, direction, now)
+	}
+	if err := srs.Review(&card, grade, now); err != nil {
+		return nil, err
+	}
+	if !found {
+		s.lastReviewCardID++
+		card.ID = s.lastReviewCardID
+	}
+	s.reviewCards[card.ID] = card
+	card.Translation = translation
+	return &card, nil
+}
+
+func (s *Store) AddUser(user dbModels.User) (*dbModels.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.findUser(user.Username); found {
+		return nil, customErrors.ErrUserAlreadyExists
+	}
+	s.lastUserID++
+	user.ID = s.lastUserID
+	if user.Role == "" {
+		user.Role = "READER"
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *Store) GetUserById(id uint) (*dbModels.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, customErrors.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (s *Store) GetUserByUsername(username string) (*dbModels.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, found := s.findUser(username)
+	if !found {
+		return nil, customErrors.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (s *Store) SetUserRole(id uint, role string) (*dbModels.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, customErrors.ErrUserNotFound
+	}
+	user.Role = role
+	s.users[id] = user
+	return &user, nil
+}
+
+func (s *Store) AddAPIKey(key dbModels.APIKey) (*dbModels.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[key.UserID]; !ok {
+		return nil, customErrors.ErrUserNotFound
+	}
+	s.lastAPIKeyID++
+	key.ID = s.lastAPIKeyID
+	key.User = dbModels.User{}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	s.apiKeys[key.ID] = key
+	return &key, nil
+}
+
+func (s *Store) GetAPIKeyByHash(hash string) (*dbModels.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range s.apiKeys {
+		if key.Hash == hash {
+			key.User = s.users[key.UserID]
+			return &key, nil
+		}
+	}
+	return nil, customErrors.ErrAPIKeyNotFound
+}
+
+func (s *Store) GetAPIKeysByUserId(userID uint) ([]*dbModels.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := []*dbModels.APIKey{}
+	for _, id := range sortedKeys(s.apiKeys) {
+		if key := s.apiKeys[id]; key.UserID == userID {
+			keys = append(keys, &key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *Store) DeleteAPIKey(userID uint, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.apiKeys[id]; !ok || key.UserID != userID {
+		return customErrors.ErrAPIKeyNotFound
+	}
+	delete(s.apiKeys, id)
+	return nil
+}
+
+func (s *Store) AddAuditEntry(entry dbModels.AuditEntry) (*dbModels.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAuditEntryID++
+	entry.ID = s.lastAuditEntryID
+	s.auditEntries[entry.ID] = entry
+	return &entry, nil
+}
+
+func (s *Store) GetAuditLogPage(filter database.AuditFilter, page database.PageRequest) (*database.Page[dbModels.AuditEntry], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := []*dbModels.AuditEntry{}
+	for _, id := range sortedKeys(s.auditEntries) {
+		if entry := s.auditEntries[id]; filter.Matches(&entry) {
+			entries = append(entries, &entry)
+		}
+	}
+	return database.PaginateSlice(entries, func(entry *dbModels.AuditEntry) uint { return entry.ID }, page)
+}
+
+// DeleteRecordFromTable accepts the same model values as DBManager. Like it,
+// it moves the record to the trash with the translations and examples beneath
+// it, all with the same DeletedAt.
+func (s *Store) DeleteRecordFromTable(table interface{}, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deletedAt := gorm.DeletedAt{Time: time.Now().UTC(), Valid: true}
+	switch table.(type) {
+	case dbModels.PolishWord, *dbModels.PolishWord:
+		if word, ok := s.polishWords[id]; ok {
+			s.trashTranslationsWhere(func(translation dbModels.Translation) bool {
+				return translation.PolishWordID == id
+			}, deletedAt)
+			word.DeletedAt = deletedAt
+			s.trashedPolishWords[id] = word
+			delete(s.polishWords, id)
+		}
+	case dbModels.EnglishWord, *dbModels.EnglishWord:
+		if word, ok := s.englishWords[id]; ok {
+			s.trashTranslationsWhere(func(translation dbModels.Translation) bool {
+				return translation.EnglishWordID == id
+			}, deletedAt)
+			word.DeletedAt = deletedAt
+			s.trashedEnglishWords[id] = word
+			delete(s.englishWords, id)
+		}
+	case dbModels.Translation, *dbModels.Translation:
+		s.trashTranslationsWhere(func(translation dbModels.Translation) bool {
+			return translation.ID == id
+		}, deletedAt)
+	case dbModels.Example, *dbModels.Example:
+		if example, ok := s.examples[id]; ok {
+			example.DeletedAt = deletedAt
+			s.trashedExamples[id] = example
+			delete(s.examples, id)
+		}
+	default:
+		return fmt.Errorf("memstore: unsupported table %T", table)
+	}
+	return nil
+}
+
+func (s *Store) GetTrash() (*database.Trash, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	trash := &database.Trash{
+		PolishWords: trashed(s.trashedPolishWords, func(word dbModels.PolishWord) time.Time {
+			return word.DeletedAt.Time
+		}),
+		EnglishWords: trashed(s.trashedEnglishWords, func(word dbModels.EnglishWord) time.Time {
+			return word.DeletedAt.Time
+		}),
+		Translations: trashed(s.trashedTranslations, func(translation dbModels.Translation) time.Time {
+			return translation.DeletedAt.Time
+		}),
+		Examples: trashed(s.trashedExamples, func(example dbModels.Example) time.Time {
+			return example.DeletedAt.Time
+		}),
+	}
+	for _, translation := range trash.Translations {
+		var ok bool
+		if translation.PolishWord, ok = s.polishWords[translation.PolishWordID]; !ok {
+			translation.PolishWord = s.trashedPolishWords[translation.PolishWordID]
+		}
+		if translation.EnglishWord, ok = s.englishWords[translation.EnglishWordID]; !ok {
+			translation.EnglishWord = s.trashedEnglishWords[translation.EnglishWordID]
+		}
+	}
+	return trash, nil
+}
+
+// RestoreFromTrash checks the references and unique constraints DBManager
+// relies on the schema for.
+func (s *Store) RestoreFromTrash(table interface{}, id uint) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch table.(type) {
+	case dbModels.PolishWord, *dbModels.PolishWord:
+		word, ok := s.trashedPolishWords[id]
+		if !ok {
+			return nil, customErrors.ErrNotInTrash
+		}
+		if _, found := s.findPolishWord(word.Text, word.PartOfSpeech); found {
+			return nil, customErrors.ErrPolishWordAlreadyExists
+		}
+		deletedAt := word.DeletedAt
+		word.DeletedAt = gorm.DeletedAt{}
+		s.polishWords[id] = word
+		delete(s.trashedPolishWords, id)
+		s.restoreTranslationsWhere(func(translation dbModels.Translation) bool {
+			return translation.PolishWordID == id
+		}, deletedAt)
+		return &word, nil
+	case dbModels.EnglishWord, *dbModels.EnglishWord:
+		word, ok := s.trashedEnglishWords[id]
+		if !ok {
+			return nil, customErrors.ErrNotInTrash
+		}
+		if _, found := s.findEnglishWord(word.Text, word.PartOfSpeech); found {
+			return nil, customErrors.ErrEnglishWordAlreadyExists
+		}
+		deletedAt := word.DeletedAt
+		word.DeletedAt = gorm.DeletedAt{}
+		s.englishWords[id] = word
+		delete(s.trashedEnglishWords, id)
+		s.restoreTranslationsWhere(func(translation dbModels.Translation) bool {
+			return translation.EnglishWordID == id
+		}, deletedAt)
+		return &word, nil
+	case dbModels.Translation, *dbModels.Translation:
+		translation, ok := s.trashedTranslations[id]
+		if !ok {
+			return nil, customErrors.ErrNotInTrash
+		}
+		if _, ok := s.polishWords[translation.PolishWordID]; !ok {
+			return nil, customErrors.ErrPolishWordNotFound
+		}
+		if _, ok := s.englishWords[translation.EnglishWordID]; !ok {
+			return nil, customErrors.ErrEnglishWordNotFound
+		}
+		if _, found := s.findTranslation(translation.PolishWordID, translation.EnglishWordID); found {
+			return nil, customErrors.ErrTranslationAlreadyExists
+		}
+		s.restoreTranslationsWhere(func(translation dbModels.Translation) bool {
+			return translation.ID == id
+		}, translation.DeletedAt)
+		translation = s.translations[id]
+		return &translation, nil
+	case dbModels.Example, *dbModels.Example:
+		example, ok := s.trashedExamples[id]
+		if !ok {
+			return nil, customErrors.ErrNotInTrash
+		}
+		if _, ok := s.translations[example.TranslationID]; !ok {
+			return nil, customErrors.ErrTranslationNotFound
+		}
+		if _, found := s.findExample(example.TranslationID, example.Text); found {
+			return nil, customErrors.ErrExampleAlreadyExists
+		}
+		example.DeletedAt = gorm.DeletedAt{}
+		s.examples[id] = example
+		delete(s.trashedExamples, id)
+		return &example, nil
+	default:
+		return nil, fmt.Errorf("memstore: unsupported table %T", table)
+	}
+}
+
+// PurgeTrash applies the ON DELETE CASCADE rules of the relational schema by
+// hand.
+func (s *Store) PurgeTrash(olderThan *time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := func(deletedAt gorm.DeletedAt) bool {
+		return olderThan == nil || deletedAt.Time.Before(*olderThan)
+	}
+	var purged int64
+	for id, example := range s.trashedExamples {
+		if expired(example.DeletedAt) {
+			delete(s.trashedExamples, id)
+			purged++
+		}
+	}
+	for id, translation := range s.trashedTranslations {
+		if expired(translation.DeletedAt) {
+			s.purgeTranslationsWhere(func(translation dbModels.Translation) bool {
+				return translation.ID == id
+			})
+			purged++
+		}
+	}
+	for id, word := range s.trashedEnglishWords {
+		if expired(word.DeletedAt) {
+			delete(s.trashedEnglishWords, id)
+			s.purgeTranslationsWhere(func(translation dbModels.Translation) bool {
+				return translation.EnglishWordID == id
+			})
+			purged++
+		}
+	}
+	for id, word := range s.trashedPolishWords {
+		if expired(word.DeletedAt) {
+			delete(s.trashedPolishWords, id)
+			s.deleteWordFormsOf(id)
+			s.purgeTranslationsWhere(func(translation dbModels.Translation) bool {
+				return translation.PolishWordID == id
+			})
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *Store) AddRevision(revision interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch revision := revision.(type) {
+	case *dbModels.PolishWordRevision:
+		revision.Version = nextVersion(s.polishWordRevisions, func(stored dbModels.PolishWordRevision) bool {
+			return stored.PolishWordID == revision.PolishWordID
+		})
+		revision.ID = s.nextRevisionID()
+		s.polishWordRevisions[revision.ID] = *revision
+	case *dbModels.EnglishWordRevision:
+		revision.Version = nextVersion(s.englishWordRevisions, func(stored dbModels.EnglishWordRevision) bool {
+			return stored.EnglishWordID == revision.EnglishWordID
+		})
+		revision.ID = s.nextRevisionID()
+		s.englishWordRevisions[revision.ID] = *revision
+	case *dbModels.TranslationRevision:
+		revision.Version = nextVersion(s.translationRevisions, func(stored dbModels.TranslationRevision) bool {
+			return stored.TranslationID == revision.TranslationID
+		})
+		revision.ID = s.nextRevisionID()
+		s.translationRevisions[revision.ID] = *revision
+	case *dbModels.ExampleRevision:
+		revision.Version = nextVersion(s.exampleRevisions, func(stored dbModels.ExampleRevision) bool {
+			return stored.ExampleID == revision.ExampleID
+		})
+		revision.ID = s.nextRevisionID()
+		s.exampleRevisions[revision.ID] = *revision
+	default:
+		return fmt.Errorf("memstore: unsupported revision %T", revision)
+	}
+	return nil
+}
+
+func (s *Store) GetPolishWordRevisions(polishWordID uint) ([]*dbModels.PolishWordRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.polishWordRevisions, func(revision dbModels.PolishWordRevision) bool {
+		return revision.PolishWordID == polishWordID
+	}), nil
+}
+
+func (s *Store) GetEnglishWordRevisions(englishWordID uint) ([]*dbModels.EnglishWordRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.englishWordRevisions, func(revision dbModels.EnglishWordRevision) bool {
+		return revision.EnglishWordID == englishWordID
+	}), nil
+}
+
+func (s *Store) GetTranslationRevisions(translationID uint) ([]*dbModels.TranslationRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.translationRevisions, func(revision dbModels.TranslationRevision) bool {
+		return revision.TranslationID == translationID
+	}), nil
+}
+
+func (s *Store) GetExampleRevisions(exampleID uint) ([]*dbModels.ExampleRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.exampleRevisions, func(revision dbModels.ExampleRevision) bool {
+		return revision.ExampleID == exampleID
+	}), nil
+}
+
+func (s *Store) GetTranslationRevisionsByDeletion(deletedWith string) ([]*dbModels.TranslationRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.translationRevisions, func(revision dbModels.TranslationRevision) bool {
+		return revision.DeletedWith == deletedWith
+	}), nil
+}
+
+func (s *Store) GetExampleRevisionsByDeletion(deletedWith string) ([]*dbModels.ExampleRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterRevisions(s.exampleRevisions, func(revision dbModels.ExampleRevision) bool {
+		return revision.DeletedWith == deletedWith
+	}), nil
+}
+
+// RestoreRecord checks the references and unique constraints DBManager
+// relies on the schema for.
+func (s *Store) RestoreRecord(record interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch record := record.(type) {
+	case *dbModels.PolishWord:
+		if existing, found := s.findPolishWord(record.Text, record.PartOfSpeech); found && existing.ID != record.ID {
+			return customErrors.ErrPolishWordAlreadyExists
+		}
+		stored, ok := s.polishWords[record.ID]
+		if !ok {
+			stored = s.trashedPolishWords[record.ID]
+		}
+		record.LookupCount = stored.LookupCount
+		record.Version = stored.Version + 1
+		record.UpdateSearchKeys()
+		s.polishWords[record.ID] = *record
+		delete(s.trashedPolishWords, record.ID)
+	case *dbModels.EnglishWord:
+		if existing, found := s.findEnglishWord(record.Text, record.PartOfSpeech); found && existing.ID != record.ID {
+			return customErrors.ErrEnglishWordAlreadyExists
+		}
+		stored, ok := s.englishWords[record.ID]
+		if !ok {
+			stored = s.trashedEnglishWords[record.ID]
+		}
+		record.LookupCount = stored.LookupCount
+		record.Version = stored.Version + 1
+		record.UpdateSearchKeys()
+		s.englishWords[record.ID] = *record
+		delete(s.trashedEnglishWords, record.ID)
+	case *dbModels.Translation:
+		if _, ok := s.polishWords[record.PolishWordID]; !ok {
+			return customErrors.ErrPolishWordNotFound
+		}
+		if _, ok := s.englishWords[record.EnglishWordID]; !ok {
+			return customErrors.ErrEnglishWordNotFound
+		}
+		if existing, found := s.findTranslation(record.PolishWordID, record.EnglishWordID); found && existing.ID != record.ID {
+			return customErrors.ErrTranslationAlreadyExists
+		}
+		s.translations[record.ID] = dbModels.Translation{
+			ID:            record.ID,
+			PolishWordID:  record.PolishWordID,
+			EnglishWordID: record.EnglishWordID,
+		}
+		delete(s.trashedTranslations, record.ID)
+	case *dbModels.Example:
+		if _, ok := s.translations[record.TranslationID]; !ok {
+			return customErrors.ErrTranslationNotFound
+		}
+		if existing, found := s.findExample(record.TranslationID, record.Text); found && existing.ID != record.ID {
+			return customErrors.ErrExampleAlreadyExists
+		}
+		stored, ok := s.examples[record.ID]
+		if !ok {
+			stored = s.trashedExamples[record.ID]
+		}
+		record.Version = stored.Version + 1
+		record.UpdateSearchWords()
+		s.examples[record.ID] = dbModels.Example{
+			ID:            record.ID,
+			TranslationID: record.TranslationID,
+			Text:          record.Text,
+			InPolish:      record.InPolish,
+			SearchWords:   record.SearchWords,
+			Version:       record.Version,
+		}
+		delete(s.trashedExamples, record.ID)
+	default:
+		return fmt.Errorf("memstore: unsupported record %T", record)
+	}
+	return nil
+}
+
+// LockRecord has nothing to do, WithinTransaction locks the whole store.
+func (s *Store) LockRecord(table interface{}, id uint) error {
+	return nil
+}
+
+// WithinTransaction runs fn against a copy of the store and adopts its state
+// when fn succeeds. The store is locked meanwhile, so no write can get lost.
+func (s *Store) WithinTransaction(fn func(store database.DictionaryStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := s.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.polishWords, s.englishWords = tx.polishWords, tx.englishWords
+	s.translations, s.examples, s.wordForms = tx.translations, tx.examples, tx.wordForms
+	s.reviewCards, s.tags, s.users, s.apiKeys = tx.reviewCards, tx.tags, tx.users, tx.apiKeys
+	s.lastPolishWordID, s.lastEnglishWordID = tx.lastPolishWordID, tx.lastEnglishWordID
+	s.lastTranslationID, s.lastExampleID, s.lastWordFormID = tx.lastTranslationID, tx.lastExampleID, tx.lastWordFormID
+	s.lastReviewCardID, s.lastTagID = tx.lastReviewCardID, tx.lastTagID
+	s.lastUserID, s.lastAPIKeyID = tx.lastUserID, tx.lastAPIKeyID
+	s.auditEntries, s.lastAuditEntryID = tx.auditEntries, tx.lastAuditEntryID
+	s.polishWordRevisions, s.englishWordRevisions = tx.polishWordRevisions, tx.englishWordRevisions
+	s.translationRevisions, s.exampleRevisions = tx.translationRevisions, tx.exampleRevisions
+	s.lastRevisionID = tx.lastRevisionID
+	s.trashedPolishWords, s.trashedEnglishWords = tx.trashedPolishWords, tx.trashedEnglishWords
+	s.trashedTranslations, s.trashedExamples = tx.trashedTranslations, tx.trashedExamples
+	return nil
+}
+
+func (s *Store) ChangeExampleText(id uint, text string, expectedVersion *uint) (*dbModels.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	example, ok := s.examples[id]
+	if !ok {
+		return nil, customErrors.ErrExampleNotFound
+	}
+	if expectedVersion != nil && *expectedVersion != example.Version {
+		return nil, customErrors.ErrVersionConflict
+	}
+	if existing, found := s.findExample(example.TranslationID, text); found && existing.ID != id {
+		return nil, customErrors.ErrExampleAlreadyExists
+	}
+	example.Text = text
+	example.UpdateSearchWords()
+	example.Version++
+	s.examples[id] = example
+	return &example, nil
+}
+
+func (s *Store) ChangePolishWordText(id uint, text string, expectedVersion *uint) (*dbModels.PolishWord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	polishWord, ok := s.polishWords[id]
+	if !ok {
+		return nil, customErrors.ErrPolishWordNotFound
+	}
+	if expectedVersion != nil && *expectedVersion != polishWord.Version {
+		return nil, customErrors.ErrVersionConflict
+	}
+	if existing, found := s.findPolishWord(text, polishWord.PartOfSpeech); found && existing.ID != id {
+		return nil, customErrors.ErrPolishWordAlreadyExists
+	}
+	polishWord.Text = text
+	polishWord.UpdateSearchKeys()
+	polishWord.Version++
+	s.polishWords[id] = polishWord
+	return &polishWord, nil
+}
+
+func (s *Store) ChangeEnglishWordText(id uint, text string, expectedVersion *uint) (*dbModels.EnglishWord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	englishWord, ok := s.englishWords[id]
+	if !ok {
+		return nil, customErrors.ErrEnglishWordNotFound
+	}
+	if expectedVersion != nil && *expectedVersion != englishWord.Version {
+		return nil, customErrors.ErrVersionConflict
+	}
+	if existing, found := s.findEnglishWord(text, englishWord.PartOfSpeech); found && existing.ID != id {
+		return nil, customErrors.ErrEnglishWordAlreadyExists
+	}
+	englishWord.Text = text
+	englishWord.UpdateSearchKeys()
+	englishWord.Version++
+	s.englishWords[id] = englishWord
+	return &englishWord, nil
+}
+
+func (s *Store) GetPolishWordById(id uint) (*dbModels.PolishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	polishWord, ok := s.polishWords[id]
+	if !ok {
+		return nil, customErrors.ErrPolishWordNotFound
+	}
+	return &polishWord, nil
+}
+
+func (s *Store) GetEnglishWordById(id uint) (*dbModels.EnglishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	englishWord, ok := s.englishWords[id]
+	if !ok {
+		return nil, customErrors.ErrEnglishWordNotFound
+	}
+	return &englishWord, nil
+}
+
+func (s *Store) GetExampleById(id uint) (*dbModels.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	example, ok := s.examples[id]
+	if !ok {
+		return nil, customErrors.ErrExampleNotFound
+	}
+	return &example, nil
+}
+
+func (s *Store) GetTranslationById(id uint) (*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	translation, ok := s.translations[id]
+	if !ok {
+		return nil, customErrors.ErrTranslationNotFound
+	}
+	return &translation, nil
+}
+
+func (s *Store) GetPolishWordsByIds(ids []uint) ([]*dbModels.PolishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	words := []*dbModels.PolishWord{}
+	for _, id := range ids {
+		if word, ok := s.polishWords[id]; ok {
+			words = append(words, &word)
+		}
+	}
+	return words, nil
+}
+
+func (s *Store) GetEnglishWordsByIds(ids []uint) ([]*dbModels.EnglishWord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	words := []*dbModels.EnglishWord{}
+	for _, id := range ids {
+		if word, ok := s.englishWords[id]; ok {
+			words = append(words, &word)
+		}
+	}
+	return words, nil
+}
+
+func (s *Store) GetExamplesByTranslationIds(ids []uint) ([]*dbModels.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	examples := s.examplesByTranslation()
+	result := []*dbModels.Example{}
+	for _, id := range ids {
+		for _, example := range examples[id] {
+			result = append(result, &example)
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) GetTranslationsByPolishWordIds(ids []uint) ([]*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filterTranslations(func(translation dbModels.Translation) bool {
+		return slices.Contains(ids, translation.PolishWordID)
+	}), nil
+}
+
+func (s *Store) GetTranslationsByEnglishWordIds(ids []uint) ([]*dbModels.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filterTranslations(func(translation dbModels.Translation) bool {
+		return slices.Contains(ids, translation.EnglishWordID)
+	}), nil
+}
+
+func (s *Store) GetWordFormsByPolishWordIds(ids []uint) ([]*dbModels.WordForm, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.wordFormsOf(ids), nil
+}
+
+func (s *Store) GetTagsByTranslationIds(ids []uint) ([]*dbModels.TranslationTag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tagsOf(ids), nil
+}
+
+// The helpers below expect s.mu to be held by the caller.
+
+func (s *Store) addPolishWord(word dbModels.PolishWord) (dbModels.PolishWord, error) {
+	if err := database.ValidateGrammar(word); err != nil {
+		return dbModels.PolishWord{}, err
+	}
+	if polishWord, found := s.findPolishWord(word.Text, word.PartOfSpeech); found {
+		return polishWord, database.MatchGrammar(polishWord, word)
+	}
+	s.lastPolishWordID++
+	polishWord := dbModels.PolishWord{
+		ID:           s.lastPolishWordID,
+		Text:         word.Text,
+		PartOfSpeech: word.PartOfSpeech,
+		Gender:       word.Gender,
+		Aspect:       word.Aspect,
+		Version:      1,
+	}
+	polishWord.UpdateSearchKeys()
+	s.polishWords[polishWord.ID] = polishWord
+	return polishWord, nil
+}
+
+func (s *Store) addEnglishWord(word dbModels.EnglishWord) dbModels.EnglishWord {
+	if englishWord, found := s.findEnglishWord(word.Text, word.PartOfSpeech); found {
+		return englishWord
+	}
+	s.lastEnglishWordID++
+	englishWord := dbModels.EnglishWord{ID: s.lastEnglishWordID, Text: word.Text, PartOfSpeech: word.PartOfSpeech, Version: 1}
+	englishWord.UpdateSearchKeys()
+	s.englishWords[englishWord.ID] = englishWord
+	return englishWord
+}
+
+func (s *Store) addExample(example *model.ExampleInput, translationID uint) (dbModels.Example, error) {
+	if example == nil {
+		return dbModels.Example{}, errors.New("memstore: example is nil")
+	}
+	if _, ok := s.translations[translationID]; !ok {
+		return dbModels.Example{}, customErrors.ErrTranslationNotFound
+	}
+	if existing, found := s.findExample(translationID, example.Text); found {
+		return existing, nil
+	}
+	s.lastExampleID++
+	dbExample := dbModels.Example{
+		ID:            s.lastExampleID,
+		TranslationID: translationID,
+		Text:          example.Text,
+		InPolish:      example.InPolish,
+		Version:       1,
+	}
+	dbExample.UpdateSearchWords()
+	s.examples[dbExample.ID] = dbExample
+	return dbExample, nil
+}
+
+func (s *Store) findPolishWord(text, partOfSpeech string) (dbModels.PolishWord, bool) {
+	for _, word := range s.polishWords {
+		if word.Text == text && word.PartOfSpeech == partOfSpeech {
+			return word, true
+		}
+	}
+	return dbModels.PolishWord{}, false
+}
+
+func (s *Store) findEnglishWord(text, partOfSpeech string) (dbModels.EnglishWord, bool) {
+	for _, word := range s.englishWords {
+		if word.Text == text && word.PartOfSpeech == partOfSpeech {
+			return word, true
+		}
+	}
+	return dbModels.EnglishWord{}, false
+}
+
+func (s *Store) findWordForm(form dbModels.WordForm) (dbModels.WordForm, bool) {
+	for _, stored := range s.wordForms {
+		if stored.PolishWordID == form.PolishWordID && stored.Text == form.Text &&
+			stored.GrammaticalCase == form.GrammaticalCase && stored.Number == form.Number && stored.Person == form.Person {
+			return stored, true
+		}
+	}
+	return dbModels.WordForm{}, false
+}
+
+// wordFormsOf returns the forms of the given Polish words ordered by ID,
+// leaving out the words in the trash.
+func (s *Store) wordFormsOf(polishWordIDs []uint) []*dbModels.WordForm {
+	wanted := make(map[uint]bool, len(polishWordIDs))
+	for _, id := range polishWordIDs {
+		wanted[id] = true
+	}
+	forms := []*dbModels.WordForm{}
+	for _, id := range sortedKeys(s.wordForms) {
+		form := s.wordForms[id]
+		if _, ok := s.polishWords[form.PolishWordID]; ok && wanted[form.PolishWordID] {
+			forms = append(forms, &form)
+		}
+	}
+	return forms
+}
+
+func (s *Store) deleteWordFormsOf(polishWordID uint) {
+	for id, form := range s.wordForms {
+		if form.PolishWordID == polishWordID {
+			delete(s.wordForms, id)
+		}
+	}
+}
+
+// tagsOf returns the tags of the translations ordered by name, leaving out
+// the translations in the trash.
+func (s *Store) tagsOf(translationIDs []uint) []*dbModels.TranslationTag {
+	tags := []*dbModels.TranslationTag{}
+	for _, id := range sortedKeys(s.tags) {
+		tag := s.tags[id]
+		if _, ok := s.translations[tag.TranslationID]; ok && slices.Contains(translationIDs, tag.TranslationID) {
+			tags = append(tags, &tag)
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags
+}
+
+func (s *Store) findUser(username string) (dbModels.User, bool) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, true
+		}
+	}
+	return dbModels.User{}, false
+}
+
+func (s *Store) findReviewCard(learner string, translationID uint, direction string) (dbModels.ReviewCard, bool) {
+	for _, card := range s.reviewCards {
+		if card.Learner == learner && card.TranslationID == translationID && card.Direction == direction {
+			return card, true
+		}
+	}
+	return dbModels.ReviewCard{}, false
+}
+
+func (s *Store) findTranslation(polishWordID, englishWordID uint) (dbModels.Translation, bool) {
+	for _, translation := range s.translations {
+		if translation.PolishWordID == polishWordID && translation.EnglishWordID == englishWordID {
+			return translation, true
+		}
+	}
+	return dbModels.Translation{}, false
+}
+
+func (s *Store) findExample(translationID uint, text string) (dbModels.Example, bool) {
+	for _, example := range s.examples {
+		if example.TranslationID == translationID && example.Text == text {
+			return example, true
+		}
+	}
+	return dbModels.Example{}, false
+}
+
+func (s *Store) populateTranslation(translation *dbModels.Translation, examples map[uint][]dbModels.Example) error {
+	stored, ok := s.translations[translation.ID]
+	if !ok {
+		return customErrors.ErrTranslationNotFound
+	}
+	*translation = stored
+	translation.PolishWord = s.polishWords[stored.PolishWordID]
+	translation.EnglishWord = s.englishWords[stored.EnglishWordID]
+	translation.Examples = append([]dbModels.Example{}, examples[stored.ID]...)
+	return nil
+}
+
+func (s *Store) examplesByTranslation() map[uint][]dbModels.Example {
+	examples := make(map[uint][]dbModels.Example)
+	for _, id := range sortedKeys(s.examples) {
+		example := s.examples[id]
+		examples[example.TranslationID] = append(examples[example.TranslationID], example)
+	}
+	return examples
+}
+
+func (s *Store) filterTranslations(keep func(dbModels.Translation) bool) []*dbModels.Translation {
+	translations := []*dbModels.Translation{}
+	for _, id := range sortedKeys(s.translations) {
+		if translation := s.translations[id]; keep(translation) {
+			translations = append(translations, &translation)
+		}
+	}
+	return translations
+}
+
+// trashTranslationsWhere moves the matching translations to the trash with
+// their examples.
+func (s *Store) trashTranslationsWhere(match func(dbModels.Translation) bool, deletedAt gorm.DeletedAt) {
+	for id, translation := range s.translations {
+		if !match(translation) {
+			continue
+		}
+		for exampleID, example := range s.examples {
+			if example.TranslationID == id {
+				example.DeletedAt = deletedAt
+				s.trashedExamples[exampleID] = example
+				delete(s.examples, exampleID)
+			}
+		}
+		translation.DeletedAt = deletedAt
+		s.trashedTranslations[id] = translation
+		delete(s.translations, id)
+	}
+}
+
+// restoreTranslationsWhere takes the matching translations deleted at
+// deletedAt out of the trash, with the examples deleted along, unless one of
+// their words is still deleted.
+func (s *Store) restoreTranslationsWhere(match func(dbModels.Translation) bool, deletedAt gorm.DeletedAt) {
+	for id, translation := range s.trashedTranslations {
+		_, polish := s.polishWords[translation.PolishWordID]
+		_, english := s.englishWords[translation.EnglishWordID]
+		if !polish || !english || !translation.DeletedAt.Time.Equal(deletedAt.Time) || !match(translation) {
+			continue
+		}
+		for exampleID, example := range s.trashedExamples {
+			if example.TranslationID == id && example.DeletedAt.Time.Equal(deletedAt.Time) {
+				example.DeletedAt = gorm.DeletedAt{}
+				s.examples[exampleID] = example
+				delete(s.trashedExamples, exampleID)
+			}
+		}
+		translation.DeletedAt = gorm.DeletedAt{}
+		s.translations[id] = translation
+		delete(s.trashedTranslations, id)
+	}
+}
+
+// purgeTranslationsWhere removes the matching translations from the trash
+// with everything beneath them.
+func (s *Store) purgeTranslationsWhere(match func(dbModels.Translation) bool) {
+	for id, translation := range s.trashedTranslations {
+		if !match(translation) {
+			continue
+		}
+		delete(s.trashedTranslations, id)
+		for exampleID, example := range s.trashedExamples {
+			if example.TranslationID == id {
+				delete(s.trashedExamples, exampleID)
+			}
+		}
+		for cardID, card := range s.reviewCards {
+			if card.TranslationID == id {
+				delete(s.reviewCards, cardID)
+			}
+		}
+		for tagID, tag := range s.tags {
+			if tag.TranslationID == id {
+				delete(s.tags, tagID)
+			}
+		}
+	}
+}
+
+func (s *Store) nextRevisionID() uint {
+	s.lastRevisionID++
+	return s.lastRevisionID
+}
+
+func (s *Store) clone() *Store {
+	return &Store{
+		polishWords:       maps.Clone(s.polishWords),
+		englishWords:      maps.Clone(s.englishWords),
+		translations:      maps.Clone(s.translations),
+		examples:          maps.Clone(s.examples),
+		wordForms:         maps.Clone(s.wordForms),
+		reviewCards:       maps.Clone(s.reviewCards),
+		tags:              maps.Clone(s.tags),
+		users:             maps.Clone(s.users),
+		apiKeys:           maps.Clone(s.apiKeys),
+		auditEntries:      maps.Clone(s.auditEntries),
+		lastPolishWordID:  s.lastPolishWordID,
+		lastEnglishWordID: s.lastEnglishWordID,
+		lastTranslationID: s.lastTranslationID,
+		lastExampleID:     s.lastExampleID,
+		lastWordFormID:    s.lastWordFormID,
+		lastReviewCardID:  s.lastReviewCardID,
+		lastTagID:         s.lastTagID,
+		lastUserID:        s.lastUserID,
+		lastAPIKeyID:      s.lastAPIKeyID,
+		lastAuditEntryID:  s.lastAuditEntryID,
+
+		polishWordRevisions:  maps.Clone(s.polishWordRevisions),
+		englishWordRevisions: maps.Clone(s.englishWordRevisions),
+		translationRevisions: maps.Clone(s.translationRevisions),
+		exampleRevisions:     maps.Clone(s.exampleRevisions),
+		lastRevisionID:       s.lastRevisionID,
+
+		trashedPolishWords:  maps.Clone(s.trashedPolishWords),
+		trashedEnglishWords: maps.Clone(s.trashedEnglishWords),
+		trashedTranslations: maps.Clone(s.trashedTranslations),
+		trashedExamples:     maps.Clone(s.trashedExamples),
+	}
+}
+
+func sortedKeys[T any](records map[uint]T) []uint {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// trashed returns the records of a trash map, the most recently deleted
+// first.
+func trashed[T any](records map[uint]T, deletedAt func(T) time.Time) []*T {
+	result := make([]*T, 0, len(records))
+	for _, id := range sortedKeys(records) {
+		record := records[id]
+		result = append(result, &record)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return deletedAt(*result[i]).After(deletedAt(*result[j])) })
+	return result
+}
+
+// filterRevisions returns the revisions kept by keep in the order they were
+// added.
+func filterRevisions[T any](revisions map[uint]T, keep func(T) bool) []*T {
+	result := []*T{}
+	for _, id := range sortedKeys(revisions) {
+		if revision := revisions[id]; keep(revision) {
+			result = append(result, &revision)
+		}
+	}
+	return result
+}
+
+// nextVersion returns the version after the revisions of an entity, which
+// count from 1 without gaps.
+func nextVersion[T any](revisions map[uint]T, ofEntity func(T) bool) uint {
+	var count uint
+	for _, revision := range revisions {
+		if ofEntity(revision) {
+			count++
+		}
+	}
+	return count + 1
+}
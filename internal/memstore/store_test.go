@@ -0,0 +1,560 @@
+package memstore
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSamePolishWord(t *testing.T) {
+	store := NewStore()
+
+	polishWord, err := store.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+	assert.NoError(t, err)
+	polishWord2, err := store.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+	assert.NoError(t, err)
+	assert.Equal(t, polishWord.ID, polishWord2.ID)
+
+	polishWords, _ := store.GetPolishWords()
+	assert.Len(t, polishWords, 1)
+}
+
+func TestAddTranslationReusesWordsAndExamples(t *testing.T) {
+	store := NewStore()
+
+	input := model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples: []*model.ExampleInput{
+			{Text: "Ala ma kota", InPolish: true},
+			{Text: "Ala ma kota", InPolish: true},
+		},
+	}
+	translation, err := store.AddTranslation(input)
+	assert.NoError(t, err)
+	translation2, err := store.AddTranslation(input)
+	assert.NoError(t, err)
+	assert.Equal(t, translation.ID, translation2.ID)
+
+	err = store.PopulateTranslationWithAssociations(translation)
+	assert.NoError(t, err)
+	assert.Equal(t, "kot", translation.PolishWord.Text)
+	assert.Equal(t, "cat", translation.EnglishWord.Text)
+	assert.Len(t, translation.Examples, 1)
+}
+
+func TestAddExampleToNotExistingTranslation(t *testing.T) {
+	store := NewStore()
+
+	example, err := store.AddExampleToTranslation(&model.ExampleInput{Text: "jeden", InPolish: true}, 6)
+	assert.Nil(t, example)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
+
+func TestGetTranslationsToEnglishAndPolish(t *testing.T) {
+	store := NewStore()
+	store.AddTranslation(model.TranslationInput{PolishWord: "wieża", EnglishWord: "tower"})
+	store.AddTranslation(model.TranslationInput{PolishWord: "wieża", EnglishWord: "rook"})
+	store.AddTranslation(model.TranslationInput{PolishWord: "koń", EnglishWord: "horse"})
+
+	translations, err := store.GetTranslationsToEnglish("wieża", database.LookupOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 2)
+	store.PopulateTranslationWithAssociations(translations[1])
+	assert.Equal(t, "rook", translations[1].EnglishWord.Text)
+
+	translations, err = store.GetTranslationsToPolish("horse", database.LookupOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1)
+	store.PopulateTranslationWithAssociations(translations[0])
+	assert.Equal(t, "koń", translations[0].PolishWord.Text)
+}
+
+func TestGetTranslationsWithLookupOptions(t *testing.T) {
+	store := NewStore()
+	store.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "turtle"})
+	store.AddTranslation(model.TranslationInput{PolishWord: "Żółw", EnglishWord: "Turtle"})
+
+	translations, _ := store.GetTranslationsToEnglish("zolw", database.LookupOptions{})
+	assert.Len(t, translations, 0)
+	translations, _ = store.GetTranslationsToEnglish("zolw", database.LookupOptions{FoldDiacritics: true})
+	assert.Len(t, translations, 1)
+	translations, _ = store.GetTranslationsToEnglish("ZOLW", database.LookupOptions{IgnoreCase: true, FoldDiacritics: true})
+	assert.Len(t, translations, 2)
+	translations, _ = store.GetTranslationsToPolish("TURTLE", database.LookupOptions{IgnoreCase: true})
+	assert.Len(t, translations, 2)
+}
+
+func TestGetTranslationsFilteredByGrammar(t *testing.T) {
+	store := NewStore()
+	noun := model.PartOfSpeechNoun
+	verb := model.PartOfSpeechVerb
+	feminine := model.GenderFeminine
+	store.AddTranslation(model.TranslationInput{PolishWord: "praca", EnglishWord: "work", PartOfSpeech: &noun, PolishGender: &feminine})
+	store.AddTranslation(model.TranslationInput{PolishWord: "pracować", EnglishWord: "work", PartOfSpeech: &verb})
+
+	englishWords, _ := store.GetEnglishWords()
+	assert.Len(t, englishWords, 2)
+
+	translations, _ := store.GetTranslationsToPolish("work", database.LookupOptions{PartOfSpeech: "NOUN"})
+	assert.Len(t, translations, 1)
+	store.PopulateTranslationWithAssociations(translations[0])
+	assert.Equal(t, "praca", translations[0].PolishWord.Text)
+	assert.Equal(t, "FEMININE", translations[0].PolishWord.Gender)
+
+	translations, _ = store.GetTranslationsToEnglish("praca", database.LookupOptions{Gender: "NEUTER"})
+	assert.Len(t, translations, 0)
+}
+
+func TestAddPolishWordChecksGrammar(t *testing.T) {
+	store := NewStore()
+	noun := model.PartOfSpeechNoun
+	feminine, neuter := model.GenderFeminine, model.GenderNeuter
+
+	_, err := store.AddTranslation(model.TranslationInput{PolishWord: "praca", EnglishWord: "work", PolishGender: &feminine})
+	assert.Equal(t, customErrors.ErrGenderWithoutNoun, err)
+	_, err = store.AddPolishWord(dbModels.PolishWord{Text: "praca", PartOfSpeech: "NOUN", Aspect: "PERFECTIVE"})
+	assert.Equal(t, customErrors.ErrAspectWithoutVerb, err)
+
+	_, err = store.AddTranslation(model.TranslationInput{PolishWord: "praca", EnglishWord: "work", PartOfSpeech: &noun, PolishGender: &feminine})
+	assert.NoError(t, err)
+	_, err = store.AddTranslation(model.TranslationInput{PolishWord: "praca", EnglishWord: "job", PartOfSpeech: &noun, PolishGender: &neuter})
+	assert.Equal(t, customErrors.ErrGrammarMismatch, err)
+	englishWords, _ := store.GetEnglishWords()
+	assert.Len(t, englishWords, 1)
+}
+
+func TestGetTranslationsToEnglishByForm(t *testing.T) {
+	store := NewStore()
+	translation, _ := store.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+
+	forms, err := store.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{
+		{Text: "psa", GrammaticalCase: "GENITIVE"},
+		{Text: "psa", GrammaticalCase: "GENITIVE"},
+		{Text: "psy", Number: "PLURAL"},
+	}, false)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 2)
+
+	translations, _ := store.GetTranslationsToEnglish("psa", database.LookupOptions{IncludeForms: true})
+	assert.Len(t, translations, 1)
+	translations, _ = store.GetTranslationsToEnglish("psa", database.LookupOptions{})
+	assert.Len(t, translations, 0)
+
+	forms, _ = store.SetPolishWordForms(translation.PolishWordID, []dbModels.WordForm{{Text: "psem"}}, true)
+	assert.Len(t, forms, 1)
+	assert.Equal(t, "psem", forms[0].Text)
+
+	_, err = store.SetPolishWordForms(translation.PolishWordID+1, nil, false)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+
+	store.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+	forms, _ = store.GetWordFormsByPolishWordIds([]uint{translation.PolishWordID})
+	assert.Len(t, forms, 0)
+}
+
+func TestDeletePolishWordCascade(t *testing.T) {
+	store := NewStore()
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "książka",
+		EnglishWord: "book",
+		Examples:    []*model.ExampleInput{{Text: "Czytam książkę", InPolish: true}},
+	})
+	store.PopulateTranslationWithAssociations(translation)
+
+	err := store.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.NoError(t, err)
+	translations, _ := store.GetTranslations()
+	assert.Len(t, translations, 0)
+	englishWords, _ := store.GetEnglishWords()
+	assert.Len(t, englishWords, 1)
+	_, err = store.GetExampleById(translation.Examples[0].ID)
+	assert.Equal(t, customErrors.ErrExampleNotFound, err)
+}
+
+func TestDeleteTranslationKeepsWords(t *testing.T) {
+	store := NewStore()
+	translation, _ := store.AddTranslation(model.TranslationInput{PolishWord: "dziecko", EnglishWord: "child"})
+
+	err := store.DeleteRecordFromTable(dbModels.Translation{}, translation.ID)
+	assert.NoError(t, err)
+	_, err = store.GetTranslationById(translation.ID)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+	polishWords, _ := store.GetPolishWords()
+	assert.Len(t, polishWords, 1)
+}
+
+func TestChangeTextViolatesUniqueConstraint(t *testing.T) {
+	store := NewStore()
+	store.AddPolishWord(dbModels.PolishWord{Text: "książka"})
+	miecz, _ := store.AddPolishWord(dbModels.PolishWord{Text: "miecz"})
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "dziecko",
+		EnglishWord: "child",
+		Examples: []*model.ExampleInput{
+			{Text: "Dziecko je cukierka", InPolish: true},
+			{Text: "Dziecko chodzi do przedszkola", InPolish: true},
+		},
+	})
+	store.PopulateTranslationWithAssociations(translation)
+
+	word, err := store.ChangePolishWordText(miecz.ID, "książka", nil)
+	assert.Nil(t, word)
+	assert.Equal(t, customErrors.ErrPolishWordAlreadyExists, err)
+
+	example, err := store.ChangeExampleText(translation.Examples[1].ID, "Dziecko je cukierka", nil)
+	assert.Nil(t, example)
+	assert.Equal(t, customErrors.ErrExampleAlreadyExists, err)
+
+	_, err = store.ChangeEnglishWordText(555, "example", nil)
+	assert.Equal(t, customErrors.ErrEnglishWordNotFound, err)
+}
+
+func TestReturnedRecordsAreCopies(t *testing.T) {
+	store := NewStore()
+	polishWord, _ := store.AddPolishWord(dbModels.PolishWord{Text: "słowo"})
+	polishWord.Text = "zmienione"
+
+	stored, err := store.GetPolishWordById(polishWord.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "słowo", stored.Text)
+}
+
+func TestAddEveryExampleToTranslationWhileConcurentRequests(t *testing.T) {
+	store := NewStore()
+
+	var wg sync.WaitGroup
+	concurrency := 100
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _ = store.AddTranslation(model.TranslationInput{
+				PolishWord:  "chleb",
+				EnglishWord: "bread",
+				Examples: []*model.ExampleInput{
+					{Text: fmt.Sprintf("test %v", i), InPolish: false},
+				},
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	translations, err := store.GetTranslations()
+	assert.NoError(t, err)
+	assert.Len(t, translations, 1, "Only one record should exist in the store")
+	translation := translations[0]
+	store.PopulateTranslationWithAssociations(translation)
+	assert.Len(t, translation.Examples, 100, "Each goroutine adds its unique example to translation")
+}
+
+func TestChangeEnglishWordTextWhileConcurentRequests(t *testing.T) {
+	store := NewStore()
+	word, _ := store.AddEnglishWord(dbModels.EnglishWord{Text: "cat"})
+	read := word.Version
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	concurrency := 100
+	changed, conflicts := 0, 0
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.ChangeEnglishWordText(word.ID, fmt.Sprintf("cat %v", i), &read)
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				changed++
+			case customErrors.ErrVersionConflict:
+				conflicts++
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, changed, "Only one goroutine can change the version it read")
+	assert.Equal(t, concurrency-1, conflicts)
+	word, _ = store.GetEnglishWordById(word.ID)
+	assert.Equal(t, uint(2), word.Version)
+}
+
+func TestGetPolishWordsPageMatchesKeysetSemantics(t *testing.T) {
+	store := NewStore()
+	for _, word := range []string{"jeden", "dwa", "trzy", "cztery", "pięć"} {
+		store.AddPolishWord(dbModels.PolishWord{Text: word})
+	}
+	first, last := 2, 2
+
+	page, err := store.GetPolishWordsPage(database.PageRequest{First: &first})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), page.TotalCount)
+	assert.Equal(t, "jeden", page.Items[0].Text)
+	assert.True(t, page.HasNextPage)
+	assert.False(t, page.HasPreviousPage)
+
+	page, err = store.GetPolishWordsPage(database.PageRequest{First: &first, After: &page.Items[1].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "trzy", page.Items[0].Text)
+	assert.True(t, page.HasNextPage)
+	assert.True(t, page.HasPreviousPage)
+
+	before := uint(5)
+	page, err = store.GetPolishWordsPage(database.PageRequest{Last: &last, Before: &before})
+	assert.NoError(t, err)
+	assert.Equal(t, "trzy", page.Items[0].Text)
+	assert.Equal(t, "cztery", page.Items[1].Text)
+	assert.True(t, page.HasPreviousPage)
+	assert.True(t, page.HasNextPage)
+}
+
+func TestGetSuggestions(t *testing.T) {
+	store := NewStore()
+	store.AddEnglishWord(dbModels.EnglishWord{Text: "house"})
+	store.AddEnglishWord(dbModels.EnglishWord{Text: "horse"})
+	store.AddEnglishWord(dbModels.EnglishWord{Text: "mouse"})
+	store.AddEnglishWord(dbModels.EnglishWord{Text: "bread"})
+
+	suggestions, err := store.GetSuggestions("hosue", model.LanguageEnglish, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 3)
+	assert.Equal(t, "house", suggestions[0].Text)
+	assert.Equal(t, 1, suggestions[0].Distance)
+	assert.Equal(t, "horse", suggestions[1].Text)
+
+	_, err = store.GetSuggestions("hosue", model.Language("GERMAN"), 10)
+	assert.Equal(t, customErrors.ErrUnknownLanguage, err)
+}
+
+func TestAutocomplete(t *testing.T) {
+	store := NewStore()
+	store.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "turtle"})
+	store.AddTranslation(model.TranslationInput{PolishWord: "żółw", EnglishWord: "tortoise"})
+	store.AddTranslation(model.TranslationInput{PolishWord: "zostać", EnglishWord: "stay"})
+	store.AddPolishWord(dbModels.PolishWord{Text: "kot"})
+
+	entries, err := store.Autocomplete("zo", model.LanguagePolish, model.AutocompleteRankingAlphabetical, 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "żółw", entries[0].Text)
+	assert.Equal(t, "zostać", entries[1].Text)
+
+	entries, _ = store.Autocomplete("ZO", model.LanguagePolish, model.AutocompleteRankingTranslationCount, 10)
+	assert.Equal(t, "żółw", entries[0].Text)
+	assert.Equal(t, int64(2), entries[0].TranslationCount)
+
+	store.GetTranslationsToPolish("stay", database.LookupOptions{})
+	entries, _ = store.Autocomplete("", model.LanguageEnglish, model.AutocompleteRankingPopularity, 10)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "stay", entries[0].Text)
+	assert.Equal(t, int64(1), entries[0].LookupCount)
+}
+
+func TestSearchExamples(t *testing.T) {
+	store := NewStore()
+	store.AddTranslation(model.TranslationInput{
+		PolishWord:  "przykład",
+		EnglishWord: "example",
+		Examples: []*model.ExampleInput{
+			{Text: "Na przykład kot.", InPolish: true},
+			{Text: "For example, a cat.", InPolish: false},
+			{Text: "Daj mi przykłady, na które czekam.", InPolish: true},
+		},
+	})
+
+	page, err := store.SearchExamples("na przykład", nil, database.PageRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.TotalCount)
+	assert.Equal(t, "<mark>Na</mark> <mark>przykład</mark> kot.", page.Items[0].Snippet)
+	assert.Equal(t, "Daj mi <mark>przykłady</mark>, <mark>na</mark> które czekam.", page.Items[1].Snippet)
+	assert.Greater(t, page.Items[0].Rank, page.Items[1].Rank)
+
+	english := model.LanguageEnglish
+	page, err = store.SearchExamples("CAT", &english, database.PageRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "For example, a <mark>cat</mark>.", page.Items[0].Snippet)
+}
+
+func TestDueCardsAndReviews(t *testing.T) {
+	store := NewStore()
+	cat, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	dog, _ := store.AddTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog"})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	directions := []string{string(model.StudyDirectionPolishToEnglish), string(model.StudyDirectionEnglishToPolish)}
+
+	cards, err := store.GetDueCards("ala", directions, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 4)
+	assert.Zero(t, cards[0].ID)
+	assert.Equal(t, cat.ID, cards[1].TranslationID)
+	assert.Equal(t, directions[1], cards[1].Direction)
+	assert.Equal(t, cat.PolishWordID, cards[0].Translation.PolishWordID)
+
+	card, err := store.ReviewCard("ala", cat.ID, directions[0], 4, now)
+	assert.NoError(t, err)
+	assert.NotZero(t, card.ID)
+	assert.Equal(t, now.Add(24*time.Hour), card.DueAt)
+	store.ReviewCard("ala", dog.ID, directions[0], 1, now.Add(-48*time.Hour))
+
+	cards, _ = store.GetDueCards("ala", directions[:1], now, 10)
+	assert.Len(t, cards, 1)
+	assert.Equal(t, dog.ID, cards[0].TranslationID)
+	assert.NotZero(t, cards[0].ID)
+
+	cards, _ = store.GetDueCards("ala", directions[:1], now.Add(48*time.Hour), 10)
+	assert.Len(t, cards, 2)
+	assert.Equal(t, dog.ID, cards[0].TranslationID)
+
+	cards, _ = store.GetDueCards("ola", directions[:1], now, 1)
+	assert.Len(t, cards, 1)
+	assert.Zero(t, cards[0].ID)
+
+	_, err = store.ReviewCard("ala", 99, directions[0], 4, now)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+	_, err = store.ReviewCard("ala", cat.ID, directions[0], 9, now)
+	assert.Equal(t, customErrors.ErrInvalidGrade, err)
+	_, err = store.GetDueCards("", directions, now, 10)
+	assert.Equal(t, customErrors.ErrLearnerRequired, err)
+
+	store.DeleteRecordFromTable(dbModels.PolishWord{}, cat.PolishWordID)
+	cards, _ = store.GetDueCards("ala", directions[:1], now.Add(48*time.Hour), 10)
+	assert.Len(t, cards, 1)
+}
+
+func TestSetTranslationTags(t *testing.T) {
+	store := NewStore()
+	cat, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+
+	tags, err := store.SetTranslationTags(cat.ID, []string{"Zwierzęta", "lekcja 1", "zwierzęta"})
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "lekcja 1", tags[0].Name)
+
+	translations, _ := store.GetTranslationsByTag(" Zwierzęta")
+	assert.Len(t, translations, 1)
+
+	store.DeleteRecordFromTable(dbModels.Translation{}, cat.ID)
+	tags, _ = store.GetTagsByTranslationIds([]uint{cat.ID})
+	assert.Empty(t, tags)
+}
+
+func TestUsersAndAPIKeys(t *testing.T) {
+	store := NewStore()
+
+	user, err := store.AddUser(dbModels.User{Username: "ala", PasswordHash: "hash"})
+	assert.NoError(t, err)
+	_, err = store.AddUser(dbModels.User{Username: "ala"})
+	assert.Equal(t, customErrors.ErrUserAlreadyExists, err)
+	assert.Equal(t, "READER", user.Role)
+	store.SetUserRole(user.ID, "EDITOR")
+	found, _ := store.GetUserById(user.ID)
+	assert.Equal(t, "EDITOR", found.Role)
+
+	key, err := store.AddAPIKey(dbModels.APIKey{UserID: user.ID, Name: "script", Hash: "123"})
+	assert.NoError(t, err)
+	byHash, err := store.GetAPIKeyByHash("123")
+	assert.NoError(t, err)
+	assert.Equal(t, "ala", byHash.User.Username)
+
+	assert.Equal(t, customErrors.ErrAPIKeyNotFound, store.DeleteAPIKey(user.ID+1, key.ID))
+	assert.NoError(t, store.DeleteAPIKey(user.ID, key.ID))
+	keys, _ := store.GetAPIKeysByUserId(user.ID)
+	assert.Empty(t, keys)
+}
+
+func TestAuditLogFilter(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, entityType := range []string{"POLISH_WORD", "EXAMPLE", "POLISH_WORD"} {
+		store.AddAuditEntry(dbModels.AuditEntry{OccurredAt: start.Add(time.Duration(i) * time.Hour), EntityType: entityType, EntityID: uint(i)})
+	}
+
+	page, err := store.GetAuditLogPage(database.AuditFilter{EntityType: "POLISH_WORD"}, database.PageRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.TotalCount)
+	since := start.Add(time.Hour)
+	page, _ = store.GetAuditLogPage(database.AuditFilter{Since: &since}, database.PageRequest{})
+	assert.Equal(t, []uint{2, 3}, []uint{page.Items[0].ID, page.Items[1].ID})
+}
+
+func TestRevisionsAreNumberedPerEntity(t *testing.T) {
+	store := NewStore()
+	for _, id := range []uint{1, 2, 1} {
+		assert.NoError(t, store.AddRevision(&dbModels.TranslationRevision{TranslationID: id}))
+	}
+
+	revisions, err := store.GetTranslationRevisions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, []uint{revisions[0].Version, revisions[1].Version})
+	revisions, _ = store.GetTranslationRevisions(2)
+	assert.Equal(t, uint(1), revisions[0].Version)
+}
+
+func TestRestoreRecordChecksConstraints(t *testing.T) {
+	store := NewStore()
+	translation, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+	store.DeleteRecordFromTable(dbModels.Translation{}, translation.ID)
+	store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat"})
+
+	err := store.RestoreRecord(&dbModels.Translation{ID: translation.ID, PolishWordID: translation.PolishWordID, EnglishWordID: translation.EnglishWordID})
+	assert.Equal(t, customErrors.ErrTranslationAlreadyExists, err)
+	err = store.RestoreRecord(&dbModels.Example{ID: 1, TranslationID: translation.ID, Text: "Ala ma kota"})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+
+	assert.NoError(t, store.RestoreRecord(&dbModels.EnglishWord{ID: translation.EnglishWordID, Text: "Cat"}))
+	word, _ := store.GetEnglishWordById(translation.EnglishWordID)
+	assert.Equal(t, "cat", word.SearchKey)
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	store := NewStore()
+	translation, _ := store.AddTranslation(model.TranslationInput{
+		PolishWord:  "kot",
+		EnglishWord: "cat",
+		Examples:    []*model.ExampleInput{{Text: "Ala ma kota", InPolish: true}},
+	})
+	kitten, _ := store.AddTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "kitten"})
+	store.DeleteRecordFromTable(dbModels.Translation{}, kitten.ID)
+	store.DeleteRecordFromTable(dbModels.PolishWord{}, translation.PolishWordID)
+
+	trash, err := store.GetTrash()
+	assert.NoError(t, err)
+	assert.Len(t, trash.PolishWords, 1)
+	assert.Len(t, trash.Translations, 2)
+	assert.Equal(t, "kot", trash.Translations[0].PolishWord.Text)
+	assert.Len(t, trash.Examples, 1)
+
+	_, err = store.RestoreFromTrash(dbModels.Translation{}, translation.ID)
+	assert.Equal(t, customErrors.ErrPolishWordNotFound, err)
+	restored, err := store.RestoreFromTrash(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.NoError(t, err)
+	assert.Equal(t, "kot", restored.(*dbModels.PolishWord).Text)
+	examples, _ := store.GetExamplesByTranslationIds([]uint{translation.ID})
+	assert.Len(t, examples, 1)
+	_, err = store.GetTranslationById(kitten.ID)
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+	_, err = store.RestoreFromTrash(dbModels.PolishWord{}, translation.PolishWordID)
+	assert.Equal(t, customErrors.ErrNotInTrash, err)
+
+	past := time.Now().Add(-time.Hour)
+	purged, _ := store.PurgeTrash(&past)
+	assert.Equal(t, int64(0), purged)
+	purged, err = store.PurgeTrash(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	trash, _ = store.GetTrash()
+	assert.Empty(t, trash.Translations)
+}
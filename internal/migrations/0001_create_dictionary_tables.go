@@ -0,0 +1,71 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// The models as they were when this migration was written. Migrations keep
+// their own copies so later changes to internal/models do not rewrite history.
+
+type polishWord0001 struct {
+	ID   uint   `gorm:"primaryKey"`
+	Text string `gorm:"unique;not null"`
+}
+
+func (polishWord0001) TableName() string { return "polish_words" }
+
+type englishWord0001 struct {
+	ID   uint   `gorm:"primaryKey"`
+	Text string `gorm:"unique;not null"`
+}
+
+func (englishWord0001) TableName() string { return "english_words" }
+
+type translation0001 struct {
+	ID            uint            `gorm:"primaryKey"`
+	PolishWordID  uint            `gorm:"not null;index;uniqueIndex:idx_polish_english"`
+	EnglishWordID uint            `gorm:"not null;index;uniqueIndex:idx_polish_english"`
+	PolishWord    polishWord0001  `gorm:"foreignKey:PolishWordID;constraint:OnDelete:CASCADE"`
+	EnglishWord   englishWord0001 `gorm:"foreignKey:EnglishWordID;constraint:OnDelete:CASCADE"`
+	Examples      []example0001   `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+}
+
+func (translation0001) TableName() string { return "translations" }
+
+type example0001 struct {
+	ID            uint   `gorm:"primaryKey"`
+	TranslationID uint   `gorm:"not null;index;uniqueIndex:idx_translation_text"`
+	Text          string `gorm:"not null;uniqueIndex:idx_translation_text"`
+	InPolish      bool   `gorm:"not null"`
+}
+
+func (example0001) TableName() string { return "examples" }
+
+// createDictionaryTables is the baseline schema. It is idempotent so that
+// databases created by the former AutoMigrate on startup can adopt it.
+var createDictionaryTables = Migration{
+	Version: 1,
+	Name:    "create_dictionary_tables",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&polishWord0001{}, &englishWord0001{}, &translation0001{}, &example0001{}); err != nil {
+			return err
+		}
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		// Tables created by older releases lack ON DELETE CASCADE on examples
+		return tx.Exec(`ALTER TABLE examples
+			DROP CONSTRAINT IF EXISTS fk_translations_examples;
+			ALTER TABLE examples
+			ADD CONSTRAINT fk_translations_examples
+			FOREIGN KEY (translation_id)
+			REFERENCES translations(id)
+			ON DELETE CASCADE;`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&example0001{}, &translation0001{}, &englishWord0001{}, &polishWord0001{}} {
+			if err := tx.Migrator().DropTable(table); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
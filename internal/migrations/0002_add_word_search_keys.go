@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+type polishWord0002 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Text      string `gorm:"unique;not null"`
+	SearchKey string `gorm:"not null;default:'';index"`
+	FoldedKey string `gorm:"not null;default:'';index"`
+}
+
+func (polishWord0002) TableName() string { return "polish_words" }
+
+type englishWord0002 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Text      string `gorm:"unique;not null"`
+	SearchKey string `gorm:"not null;default:'';index"`
+	FoldedKey string `gorm:"not null;default:'';index"`
+}
+
+func (englishWord0002) TableName() string { return "english_words" }
+
+// addWordSearchKeys stores the normalized keys case- and accent-insensitive
+// lookups compare against. The keys are computed in Go, so existing rows are
+// backfilled here rather than in SQL.
+var addWordSearchKeys = Migration{
+	Version: 2,
+	Name:    "add_word_search_keys",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&polishWord0002{}, &englishWord0002{}); err != nil {
+			return err
+		}
+		for _, table := range []string{"polish_words", "english_words"} {
+			if err := backfillSearchKeys(tx, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&polishWord0002{}, &englishWord0002{}} {
+			for _, column := range []string{"SearchKey", "FoldedKey"} {
+				if err := tx.Migrator().DropColumn(table, column); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func backfillSearchKeys(tx *gorm.DB, table string) error {
+	type word struct {
+		ID   uint
+		Text string
+	}
+	var words []word
+	return tx.Table(table).Select("id", "text").FindInBatches(&words, 1000, func(batch *gorm.DB, _ int) error {
+		for _, w := range words {
+			if err := tx.Table(table).Where("id = ?", w.ID).UpdateColumns(map[string]interface{}{
+				"search_key": normalize.Key(w.Text),
+				"folded_key": normalize.FoldedKey(w.Text),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// addWordTrigramIndexes lets suggestions find similar words on Postgres
+// without scanning the whole table. Other databases have no trigram support
+// and compare every word of a similar length instead.
+var addWordTrigramIndexes = Migration{
+	Version: 3,
+	Name:    "add_word_trigram_indexes",
+	Up: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		return tx.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm;
+			CREATE INDEX IF NOT EXISTS idx_polish_words_folded_key_trgm
+			ON polish_words USING gin (folded_key gin_trgm_ops);
+			CREATE INDEX IF NOT EXISTS idx_english_words_folded_key_trgm
+			ON english_words USING gin (folded_key gin_trgm_ops);`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		return tx.Exec(`DROP INDEX IF EXISTS idx_polish_words_folded_key_trgm;
+			DROP INDEX IF EXISTS idx_english_words_folded_key_trgm;`).Error
+	},
+}
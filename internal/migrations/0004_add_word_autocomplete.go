@@ -0,0 +1,54 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type polishWord0004 struct {
+	ID          uint `gorm:"primaryKey"`
+	LookupCount uint `gorm:"not null;default:0"`
+}
+
+func (polishWord0004) TableName() string { return "polish_words" }
+
+type englishWord0004 struct {
+	ID          uint `gorm:"primaryKey"`
+	LookupCount uint `gorm:"not null;default:0"`
+}
+
+func (englishWord0004) TableName() string { return "english_words" }
+
+// addWordAutocomplete counts how often words are looked up, to rank them by
+// popularity. Prefix matches on Postgres need an operator class that ignores
+// the collation to use an index, elsewhere the plain folded_key index serves
+// the range query autocomplete runs.
+var addWordAutocomplete = Migration{
+	Version: 4,
+	Name:    "add_word_autocomplete",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&polishWord0004{}, &englishWord0004{}} {
+			if err := tx.Migrator().AddColumn(table, "LookupCount"); err != nil {
+				return err
+			}
+		}
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_polish_words_folded_key_prefix
+			ON polish_words (folded_key text_pattern_ops);
+			CREATE INDEX IF NOT EXISTS idx_english_words_folded_key_prefix
+			ON english_words (folded_key text_pattern_ops);`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec(`DROP INDEX IF EXISTS idx_polish_words_folded_key_prefix;
+				DROP INDEX IF EXISTS idx_english_words_folded_key_prefix;`).Error; err != nil {
+				return err
+			}
+		}
+		for _, table := range []interface{}{&polishWord0004{}, &englishWord0004{}} {
+			if err := tx.Migrator().DropColumn(table, "LookupCount"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// addExampleSearch indexes the examples for full-text search on Postgres.
+// Postgres ships no Polish configuration, unless one was installed with an
+// ispell dictionary a copy of simple is created, which lowercases the words
+// without stemming them. Other databases search the examples in Go.
+var addExampleSearch = Migration{
+	Version: 5,
+	Name:    "add_example_search",
+	Up: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		return tx.Exec(`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'polish') THEN
+					CREATE TEXT SEARCH CONFIGURATION polish (COPY = simple);
+				END IF;
+			END
+			$$;
+			ALTER TABLE examples ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				to_tsvector(CASE WHEN in_polish THEN 'polish'::regconfig ELSE 'english'::regconfig END, text)
+			) STORED;
+			CREATE INDEX IF NOT EXISTS idx_examples_search_vector
+			ON examples USING gin (search_vector);`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		// The text search configuration is kept, it may predate the migration
+		return tx.Exec(`DROP INDEX IF EXISTS idx_examples_search_vector;
+			ALTER TABLE examples DROP COLUMN IF EXISTS search_vector;`).Error
+	},
+}
@@ -0,0 +1,111 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type polishWord0006 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Text         string `gorm:"not null;uniqueIndex:idx_polish_words_text_part_of_speech"`
+	PartOfSpeech string `gorm:"not null;default:'';uniqueIndex:idx_polish_words_text_part_of_speech"`
+	Gender       string `gorm:"not null;default:''"`
+	Aspect       string `gorm:"not null;default:''"`
+	SearchKey    string `gorm:"not null;default:'';index"`
+	FoldedKey    string `gorm:"not null;default:'';index"`
+}
+
+func (polishWord0006) TableName() string { return "polish_words" }
+
+type englishWord0006 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Text         string `gorm:"not null;uniqueIndex:idx_english_words_text_part_of_speech"`
+	PartOfSpeech string `gorm:"not null;default:'';uniqueIndex:idx_english_words_text_part_of_speech"`
+	SearchKey    string `gorm:"not null;default:'';index"`
+	FoldedKey    string `gorm:"not null;default:'';index"`
+}
+
+func (englishWord0006) TableName() string { return "english_words" }
+
+// addWordGrammar stores the part of speech of words, and the gender and
+// aspect of Polish ones, making a word unique by its text and part of speech
+// instead of its text alone. Existing words get an unspecified part of
+// speech.
+var addWordGrammar = Migration{
+	Version:            6,
+	Name:               "add_word_grammar",
+	DisableForeignKeys: true,
+	Up: func(tx *gorm.DB) error {
+		words := []struct {
+			table   string
+			model   interface{}
+			columns []string
+			unique  string
+		}{
+			{"polish_words", &polishWord0006{}, []string{"PartOfSpeech", "Gender", "Aspect"}, "idx_polish_words_text_part_of_speech"},
+			{"english_words", &englishWord0006{}, []string{"PartOfSpeech"}, "idx_english_words_text_part_of_speech"},
+		}
+		for _, word := range words {
+			for _, column := range word.columns {
+				if err := tx.Migrator().AddColumn(word.model, column); err != nil {
+					return err
+				}
+			}
+			if err := dropTextUnique(tx, word.table, word.model); err != nil {
+				return err
+			}
+			// Rebuilding the table on SQLite loses its indexes
+			for _, index := range []string{"idx_" + word.table + "_search_key", "idx_" + word.table + "_folded_key", word.unique} {
+				if tx.Migrator().HasIndex(word.model, index) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(word.model, index); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		words := []struct {
+			table    string
+			model    interface{}
+			previous interface{}
+			columns  []string
+			unique   string
+		}{
+			{"polish_words", &polishWord0006{}, &polishWord0002{}, []string{"PartOfSpeech", "Gender", "Aspect"}, "idx_polish_words_text_part_of_speech"},
+			{"english_words", &englishWord0006{}, &englishWord0002{}, []string{"PartOfSpeech"}, "idx_english_words_text_part_of_speech"},
+		}
+		for _, word := range words {
+			if err := tx.Migrator().DropIndex(word.model, word.unique); err != nil {
+				return err
+			}
+			for _, column := range word.columns {
+				if err := tx.Migrator().DropColumn(word.model, column); err != nil {
+					return err
+				}
+			}
+			if err := tx.Migrator().CreateConstraint(word.previous, "uni_"+word.table+"_text"); err != nil {
+				return err
+			}
+			for _, index := range []string{"idx_" + word.table + "_search_key", "idx_" + word.table + "_folded_key"} {
+				if tx.Migrator().HasIndex(word.previous, index) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(word.previous, index); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// dropTextUnique removes the unique constraint on the text of a word table.
+// Postgres databases created before GORM named it uni_<table>_text still
+// carry the default name <table>_text_key.
+func dropTextUnique(tx *gorm.DB, table string, model interface{}) error {
+	if tx.Dialector.Name() != "postgres" {
+		return tx.Migrator().DropConstraint(model, "uni_"+table+"_text")
+	}
+	return tx.Exec(`ALTER TABLE ` + table + ` DROP CONSTRAINT IF EXISTS uni_` + table + `_text;
+		ALTER TABLE ` + table + ` DROP CONSTRAINT IF EXISTS ` + table + `_text_key;`).Error
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type wordForm0007 struct {
+	ID              uint           `gorm:"primaryKey"`
+	PolishWordID    uint           `gorm:"not null;index;uniqueIndex:idx_word_forms_unique"`
+	Text            string         `gorm:"not null;uniqueIndex:idx_word_forms_unique"`
+	GrammaticalCase string         `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	Number          string         `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	Person          string         `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	SearchKey       string         `gorm:"not null;index"`
+	FoldedKey       string         `gorm:"not null;index"`
+	PolishWord      polishWord0006 `gorm:"foreignKey:PolishWordID;constraint:OnDelete:CASCADE"`
+}
+
+func (wordForm0007) TableName() string { return "word_forms" }
+
+// createWordForms stores the inflected forms of Polish words, so lookups of
+// "psa" can find the translations of "pies".
+var createWordForms = Migration{
+	Version: 7,
+	Name:    "create_word_forms",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&wordForm0007{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&wordForm0007{})
+	},
+}
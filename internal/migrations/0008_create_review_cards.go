@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reviewCard0008 struct {
+	ID             uint      `gorm:"primaryKey"`
+	Learner        string    `gorm:"not null;uniqueIndex:idx_review_cards_unique;index:idx_review_cards_due,priority:1"`
+	TranslationID  uint      `gorm:"not null;index;uniqueIndex:idx_review_cards_unique"`
+	Direction      string    `gorm:"not null;uniqueIndex:idx_review_cards_unique"`
+	EaseFactor     float64   `gorm:"not null"`
+	IntervalDays   int       `gorm:"not null"`
+	Repetitions    int       `gorm:"not null"`
+	Lapses         int       `gorm:"not null"`
+	DueAt          time.Time `gorm:"not null;index:idx_review_cards_due,priority:2"`
+	LastReviewedAt *time.Time
+	Translation    translation0001 `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+}
+
+func (reviewCard0008) TableName() string { return "review_cards" }
+
+// createReviewCards keeps the spaced repetition state of every learner.
+var createReviewCards = Migration{
+	Version: 8,
+	Name:    "create_review_cards",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&reviewCard0008{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&reviewCard0008{})
+	},
+}
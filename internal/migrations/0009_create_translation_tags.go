@@ -0,0 +1,24 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type translationTag0009 struct {
+	ID            uint            `gorm:"primaryKey"`
+	TranslationID uint            `gorm:"not null;index;uniqueIndex:idx_translation_tags_unique"`
+	Name          string          `gorm:"not null;index;uniqueIndex:idx_translation_tags_unique"`
+	Translation   translation0001 `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+}
+
+func (translationTag0009) TableName() string { return "translation_tags" }
+
+// createTranslationTags lets translations be grouped, e.g. into lessons.
+var createTranslationTags = Migration{
+	Version: 9,
+	Name:    "create_translation_tags",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&translationTag0009{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&translationTag0009{})
+	},
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0010 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Username     string `gorm:"not null;uniqueIndex"`
+	PasswordHash string `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (user0010) TableName() string { return "users" }
+
+type apiKey0010 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Name      string `gorm:"not null"`
+	Prefix    string `gorm:"not null"`
+	Hash      string `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time
+	User      user0010 `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (apiKey0010) TableName() string { return "api_keys" }
+
+// createUsers adds the accounts allowed to change the dictionary and the API
+// keys of their scripts.
+var createUsers = Migration{
+	Version: 10,
+	Name:    "create_users",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&user0010{}, &apiKey0010{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&apiKey0010{}, &user0010{})
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0011 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Username     string `gorm:"not null;uniqueIndex"`
+	PasswordHash string `gorm:"not null"`
+	Role         string `gorm:"not null;default:'READER'"`
+	CreatedAt    time.Time
+}
+
+func (user0011) TableName() string { return "users" }
+
+// addUserRoles stores what every user may do. Existing users become admins,
+// so the accounts created before roles keep every permission.
+var addUserRoles = Migration{
+	Version: 11,
+	Name:    "add_user_roles",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&user0011{}, "Role"); err != nil {
+			return err
+		}
+		return tx.Model(&user0011{}).Where("1 = 1").Update("role", "ADMIN").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropColumn(&user0011{}, "Role")
+	},
+}
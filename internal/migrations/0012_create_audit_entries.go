@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type auditEntry0012 struct {
+	ID         uint      `gorm:"primaryKey"`
+	Actor      string    `gorm:"not null"`
+	OccurredAt time.Time `gorm:"not null;index"`
+	Operation  string    `gorm:"not null"`
+	EntityType string    `gorm:"not null;index:idx_audit_entries_entity,priority:1"`
+	EntityID   uint      `gorm:"not null;index:idx_audit_entries_entity,priority:2"`
+	Before     *string
+	After      *string
+}
+
+func (auditEntry0012) TableName() string { return "audit_entries" }
+
+// createAuditEntries keeps who changed what. Entries outlive the entities and
+// users they name, so they have no foreign keys.
+var createAuditEntries = Migration{
+	Version: 12,
+	Name:    "create_audit_entries",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&auditEntry0012{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&auditEntry0012{})
+	},
+}
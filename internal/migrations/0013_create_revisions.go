@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type revision0013 struct {
+	Version     uint      `gorm:"not null;uniqueIndex:,composite:version,priority:2"`
+	Deleted     bool      `gorm:"not null;default:false"`
+	DeletedWith string    `gorm:"not null;default:'';index"`
+	Actor       string    `gorm:"not null"`
+	RecordedAt  time.Time `gorm:"not null"`
+}
+
+type polishWordRevision0013 struct {
+	ID           uint         `gorm:"primaryKey"`
+	PolishWordID uint         `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	Text         string       `gorm:"not null"`
+	PartOfSpeech string       `gorm:"not null;default:''"`
+	Gender       string       `gorm:"not null;default:''"`
+	Aspect       string       `gorm:"not null;default:''"`
+	Revision     revision0013 `gorm:"embedded"`
+}
+
+func (polishWordRevision0013) TableName() string { return "polish_word_revisions" }
+
+type englishWordRevision0013 struct {
+	ID            uint         `gorm:"primaryKey"`
+	EnglishWordID uint         `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	Text          string       `gorm:"not null"`
+	PartOfSpeech  string       `gorm:"not null;default:''"`
+	Revision      revision0013 `gorm:"embedded"`
+}
+
+func (englishWordRevision0013) TableName() string { return "english_word_revisions" }
+
+type translationRevision0013 struct {
+	ID            uint         `gorm:"primaryKey"`
+	TranslationID uint         `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	PolishWordID  uint         `gorm:"not null"`
+	EnglishWordID uint         `gorm:"not null"`
+	Revision      revision0013 `gorm:"embedded"`
+}
+
+func (translationRevision0013) TableName() string { return "translation_revisions" }
+
+type exampleRevision0013 struct {
+	ID            uint         `gorm:"primaryKey"`
+	ExampleID     uint         `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	TranslationID uint         `gorm:"not null"`
+	Text          string       `gorm:"not null"`
+	InPolish      bool         `gorm:"not null"`
+	Revision      revision0013 `gorm:"embedded"`
+}
+
+func (exampleRevision0013) TableName() string { return "example_revisions" }
+
+// createRevisions keeps the versions of words, translations and examples.
+// Like audit entries, revisions outlive their entities and have no foreign
+// keys. Existing entities get their first revision on their next change.
+var createRevisions = Migration{
+	Version: 13,
+	Name:    "create_revisions",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(
+			&polishWordRevision0013{},
+			&englishWordRevision0013{},
+			&translationRevision0013{},
+			&exampleRevision0013{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&polishWordRevision0013{},
+			&englishWordRevision0013{},
+			&translationRevision0013{},
+			&exampleRevision0013{},
+		)
+	},
+}
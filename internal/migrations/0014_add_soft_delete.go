@@ -0,0 +1,113 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type polishWord0014 struct {
+	ID           uint           `gorm:"primaryKey"`
+	Text         string         `gorm:"not null;uniqueIndex:idx_polish_words_text_part_of_speech,where:deleted_at IS NULL"`
+	PartOfSpeech string         `gorm:"not null;default:'';uniqueIndex:idx_polish_words_text_part_of_speech"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (polishWord0014) TableName() string { return "polish_words" }
+
+type englishWord0014 struct {
+	ID           uint           `gorm:"primaryKey"`
+	Text         string         `gorm:"not null;uniqueIndex:idx_english_words_text_part_of_speech,where:deleted_at IS NULL"`
+	PartOfSpeech string         `gorm:"not null;default:'';uniqueIndex:idx_english_words_text_part_of_speech"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (englishWord0014) TableName() string { return "english_words" }
+
+type translation0014 struct {
+	ID            uint           `gorm:"primaryKey"`
+	PolishWordID  uint           `gorm:"not null;index;uniqueIndex:idx_polish_english,where:deleted_at IS NULL"`
+	EnglishWordID uint           `gorm:"not null;index;uniqueIndex:idx_polish_english"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+func (translation0014) TableName() string { return "translations" }
+
+type example0014 struct {
+	ID            uint           `gorm:"primaryKey"`
+	TranslationID uint           `gorm:"not null;index;uniqueIndex:idx_translation_text,where:deleted_at IS NULL"`
+	Text          string         `gorm:"not null;uniqueIndex:idx_translation_text"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+func (example0014) TableName() string { return "examples" }
+
+// softDeleteTables are the tables addSoftDelete changes, children first, with
+// their unique index, their model before the migration and its indexes.
+var softDeleteTables = []struct {
+	table    string
+	model    interface{}
+	unique   string
+	previous interface{}
+	indexes  []string
+}{
+	{"examples", &example0014{}, "idx_translation_text", &example0001{}, []string{"idx_examples_translation_id"}},
+	{"translations", &translation0014{}, "idx_polish_english", &translation0001{}, []string{
+		"idx_translations_polish_word_id", "idx_translations_english_word_id",
+	}},
+	{"english_words", &englishWord0014{}, "idx_english_words_text_part_of_speech", &englishWord0006{}, []string{
+		"idx_english_words_search_key", "idx_english_words_folded_key",
+	}},
+	{"polish_words", &polishWord0014{}, "idx_polish_words_text_part_of_speech", &polishWord0006{}, []string{
+		"idx_polish_words_search_key", "idx_polish_words_folded_key",
+	}},
+}
+
+// addSoftDelete moves deleted words, translations and examples to the trash
+// instead of removing them. Their unique indexes only cover the entries not
+// deleted, so a deleted entry can be added again. Rolling back purges the
+// trash.
+var addSoftDelete = Migration{
+	Version:            14,
+	Name:               "add_soft_delete",
+	DisableForeignKeys: true,
+	Up: func(tx *gorm.DB) error {
+		for _, table := range softDeleteTables {
+			if err := tx.Migrator().AddColumn(table.model, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(table.model, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(table.model, table.unique); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(table.model, table.unique); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range softDeleteTables {
+			if err := tx.Exec("DELETE FROM " + table.table + " WHERE deleted_at IS NOT NULL").Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(table.model, table.unique); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(table.model, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(table.model, "DeletedAt"); err != nil {
+				return err
+			}
+			// Rebuilding the table on SQLite loses its indexes
+			for _, index := range append(table.indexes, table.unique) {
+				if tx.Migrator().HasIndex(table.previous, index) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(table.previous, index); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
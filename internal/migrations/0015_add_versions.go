@@ -0,0 +1,88 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type polishWord0015 struct {
+	ID      uint `gorm:"primaryKey"`
+	Version uint `gorm:"not null;default:1"`
+}
+
+func (polishWord0015) TableName() string { return "polish_words" }
+
+type englishWord0015 struct {
+	ID      uint `gorm:"primaryKey"`
+	Version uint `gorm:"not null;default:1"`
+}
+
+func (englishWord0015) TableName() string { return "english_words" }
+
+type example0015 struct {
+	ID      uint `gorm:"primaryKey"`
+	Version uint `gorm:"not null;default:1"`
+}
+
+func (example0015) TableName() string { return "examples" }
+
+type versionIndex struct {
+	model interface{}
+	name  string
+}
+
+// versionTables are the tables addVersions changes, with the indexes they
+// have before the migration and the models declaring them.
+var versionTables = []struct {
+	model   interface{}
+	indexes []versionIndex
+}{
+	{&example0015{}, []versionIndex{
+		{&example0014{}, "idx_translation_text"},
+		{&example0014{}, "idx_examples_translation_id"},
+		{&example0014{}, "idx_examples_deleted_at"},
+	}},
+	{&englishWord0015{}, []versionIndex{
+		{&englishWord0014{}, "idx_english_words_text_part_of_speech"},
+		{&englishWord0014{}, "idx_english_words_deleted_at"},
+		{&englishWord0006{}, "idx_english_words_search_key"},
+		{&englishWord0006{}, "idx_english_words_folded_key"},
+	}},
+	{&polishWord0015{}, []versionIndex{
+		{&polishWord0014{}, "idx_polish_words_text_part_of_speech"},
+		{&polishWord0014{}, "idx_polish_words_deleted_at"},
+		{&polishWord0006{}, "idx_polish_words_search_key"},
+		{&polishWord0006{}, "idx_polish_words_folded_key"},
+	}},
+}
+
+// addVersions counts the changes of words and examples, so an update can
+// require the entry to be unchanged since it was read. Existing entries start
+// at version 1.
+var addVersions = Migration{
+	Version:            15,
+	Name:               "add_versions",
+	DisableForeignKeys: true,
+	Up: func(tx *gorm.DB) error {
+		for _, table := range versionTables {
+			if err := tx.Migrator().AddColumn(table.model, "Version"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range versionTables {
+			if err := tx.Migrator().DropColumn(table.model, "Version"); err != nil {
+				return err
+			}
+			// Rebuilding the table on SQLite loses its indexes
+			for _, index := range table.indexes {
+				if tx.Migrator().HasIndex(index.model, index.name) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(index.model, index.name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
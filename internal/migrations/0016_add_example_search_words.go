@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+type example0016 struct {
+	ID          uint   `gorm:"primaryKey"`
+	SearchWords string `gorm:"not null;default:''"`
+}
+
+func (example0016) TableName() string { return "examples" }
+
+// exampleIndexes0016 are the indexes of examples before addExampleSearchWords.
+var exampleIndexes0016 = []string{"idx_translation_text", "idx_examples_translation_id", "idx_examples_deleted_at"}
+
+// addExampleSearchWords stores the keys of the words of examples, so that
+// databases without full-text search match them in SQL. The keys are computed
+// in Go, so existing rows are backfilled here rather than in SQL.
+var addExampleSearchWords = Migration{
+	Version:            16,
+	Name:               "add_example_search_words",
+	DisableForeignKeys: true,
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&example0016{}, "SearchWords"); err != nil {
+			return err
+		}
+		type example struct {
+			ID   uint
+			Text string
+		}
+		var examples []example
+		return tx.Table("examples").Select("id", "text").FindInBatches(&examples, 1000, func(batch *gorm.DB, _ int) error {
+			for _, e := range examples {
+				if err := tx.Table("examples").Where("id = ?", e.ID).
+					UpdateColumn("search_words", normalize.WordKeys(e.Text)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&example0016{}, "SearchWords"); err != nil {
+			return err
+		}
+		// Rebuilding the table on SQLite loses its indexes
+		for _, index := range exampleIndexes0016 {
+			if tx.Migrator().HasIndex(&example0014{}, index) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(&example0014{}, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
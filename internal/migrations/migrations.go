@@ -0,0 +1,23 @@
+package migrations
+
+// all lists the migrations of the application in the order they are applied.
+// Versions must be unique and increasing, and a released migration must never
+// change, add a new one instead.
+var all = []Migration{
+	createDictionaryTables,
+	addWordSearchKeys,
+	addWordTrigramIndexes,
+	addWordAutocomplete,
+	addExampleSearch,
+	addWordGrammar,
+	createWordForms,
+	createReviewCards,
+	createTranslationTags,
+	createUsers,
+	addUserRoles,
+	createAuditEntries,
+	createRevisions,
+	addSoftDelete,
+	addVersions,
+	addExampleSearchWords,
+}
@@ -0,0 +1,166 @@
+package migrations
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	path := filepath.Join(t.TempDir(), "migrations.db")
+	db, err := gorm.Open(sqlite.Open("file:"+path+"?_foreign_keys=1"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database: %v", err)
+	}
+	return db
+}
+
+func TestUpAppliesEveryMigrationOnce(t *testing.T) {
+	migrator := NewMigrator(openTestDB(t))
+
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	applied, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	pending, err := migrator.Pending()
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestDownRollsBackLatestMigration(t *testing.T) {
+	db := openTestDB(t)
+	migrator := NewMigrator(db)
+	migrator.Up()
+
+	rolledBack, err := migrator.Down()
+	assert.NoError(t, err)
+	assert.Equal(t, all[len(all)-1].Version, rolledBack.Version)
+
+	pending, err := migrator.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, rolledBack.Version, pending[0].Version)
+}
+
+func TestDownEveryMigrationDropsTables(t *testing.T) {
+	db := openTestDB(t)
+	migrator := NewMigrator(db)
+	migrator.Up()
+
+	for range all {
+		_, err := migrator.Down()
+		assert.NoError(t, err)
+	}
+	_, err := migrator.Down()
+	assert.Equal(t, ErrNothingToRollBack, err)
+	assert.False(t, db.Migrator().HasTable("polish_words"))
+	assert.False(t, db.Migrator().HasTable("examples"))
+}
+
+func TestStatusReportsAppliedAndPending(t *testing.T) {
+	db := openTestDB(t)
+	migrator := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "first", Up: noop, Down: noop},
+		{Version: 2, Name: "second", Up: noop, Down: noop},
+	}}
+	migrator.db.AutoMigrate(&SchemaMigration{})
+	db.Create(&SchemaMigration{Version: 1, Name: "first"})
+
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
+
+func TestFailedMigrationIsNotRecorded(t *testing.T) {
+	db := openTestDB(t)
+	migrator := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "first", Up: noop, Down: noop},
+		{Version: 2, Name: "broken", Up: func(tx *gorm.DB) error { return errors.New("boom") }, Down: noop},
+	}}
+
+	applied, err := migrator.Up()
+	assert.ErrorContains(t, err, "0002_broken: boom")
+	assert.Len(t, applied, 1)
+	pending, _ := migrator.Pending()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, uint(2), pending[0].Version)
+}
+
+func noop(*gorm.DB) error { return nil }
+
+func TestAddWordSearchKeysBackfillsExistingWords(t *testing.T) {
+	db := openTestDB(t)
+	_, err := (&Migrator{db: db, migrations: all[:1]}).Up()
+	assert.NoError(t, err)
+	db.Create(&polishWord0001{Text: "Żółw"})
+	db.Create(&englishWord0001{Text: "Turtle"})
+
+	_, err = NewMigrator(db).Up()
+	assert.NoError(t, err)
+
+	var polishWord polishWord0002
+	db.First(&polishWord)
+	assert.Equal(t, "żółw", polishWord.SearchKey)
+	assert.Equal(t, "zolw", polishWord.FoldedKey)
+	var englishWord englishWord0002
+	db.First(&englishWord)
+	assert.Equal(t, "turtle", englishWord.SearchKey)
+}
+
+func TestAddExampleSearchWordsBackfillsExistingExamples(t *testing.T) {
+	db := openTestDB(t)
+	_, err := (&Migrator{db: db, migrations: all[:len(all)-1]}).Up()
+	assert.NoError(t, err)
+	db.Exec("INSERT INTO polish_words (text) VALUES ('kot')")
+	db.Exec("INSERT INTO english_words (text) VALUES ('cat')")
+	db.Exec("INSERT INTO translations (polish_word_id, english_word_id) VALUES (1, 1)")
+	db.Exec("INSERT INTO examples (translation_id, text, in_polish) VALUES (1, 'Mój Kot, śpi.', true)")
+
+	_, err = NewMigrator(db).Up()
+	assert.NoError(t, err)
+
+	var example example0016
+	db.First(&example)
+	assert.Equal(t, " mój kot śpi", example.SearchWords)
+	assert.True(t, db.Migrator().HasIndex(&example0014{}, "idx_translation_text"))
+}
+
+func TestAddWordGrammarKeepsTranslations(t *testing.T) {
+	db := openTestDB(t)
+	_, err := (&Migrator{db: db, migrations: all[:5]}).Up()
+	assert.NoError(t, err)
+	polishWord := polishWord0001{Text: "biec"}
+	englishWord := englishWord0001{Text: "run"}
+	db.Create(&polishWord)
+	db.Create(&englishWord)
+	db.Create(&translation0001{PolishWordID: polishWord.ID, EnglishWordID: englishWord.ID})
+
+	migrator := &Migrator{db: db, migrations: all[:6]}
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	var translations int64
+	db.Model(&translation0001{}).Count(&translations)
+	assert.Equal(t, int64(1), translations)
+	assert.NoError(t, db.Create(&englishWord0006{Text: "run", PartOfSpeech: "NOUN"}).Error)
+	assert.Error(t, db.Create(&englishWord0006{Text: "run", PartOfSpeech: "NOUN"}).Error)
+	assert.True(t, db.Migrator().HasIndex(&polishWord0006{}, "idx_polish_words_folded_key"))
+
+	// Words differing only in the part of speech would violate the old constraint
+	db.Where("part_of_speech = ?", "NOUN").Delete(&englishWord0006{})
+	_, err = migrator.Down()
+	assert.NoError(t, err)
+	db.Model(&translation0001{}).Count(&translations)
+	assert.Equal(t, int64(1), translations)
+	assert.False(t, db.Migrator().HasColumn(&polishWord0006{}, "Aspect"))
+	assert.True(t, db.Migrator().HasIndex(&polishWord0006{}, "idx_polish_words_folded_key"))
+}
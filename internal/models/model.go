@@ -1,28 +1,239 @@
 package dbModels
 
+import (
+	"time"
+
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+	"gorm.io/gorm"
+)
+
+// PolishWord is unique by its text and part of speech. Grammatical values
+// hold the names of the GraphQL enums, an empty one is unspecified.
+//
+// Deleted words, translations and examples stay in their tables with their
+// DeletedAt set until the trash is purged, GORM leaves them out of queries.
+// Unique indexes only cover the ones not deleted.
+//
+// Version of words and examples counts from 1 and grows with every change of
+// the entry, so an update can require it to be unchanged since it was read.
 type PolishWord struct {
-	ID   uint   `gorm:"primaryKey"`
-	Text string `gorm:"unique;not null"`
+	ID           uint           `gorm:"primaryKey"`
+	Text         string         `gorm:"not null;uniqueIndex:idx_polish_words_text_part_of_speech,where:deleted_at IS NULL"`
+	PartOfSpeech string         `gorm:"not null;default:'';uniqueIndex:idx_polish_words_text_part_of_speech"`
+	Gender       string         `gorm:"not null;default:''"`
+	Aspect       string         `gorm:"not null;default:''"`
+	SearchKey    string         `gorm:"not null;index"`
+	FoldedKey    string         `gorm:"not null;index"`
+	LookupCount  uint           `gorm:"not null;default:0"`
+	Version      uint           `gorm:"not null;default:1"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
 
 type EnglishWord struct {
-	ID   uint   `gorm:"primaryKey"`
-	Text string `gorm:"unique;not null"`
+	ID           uint           `gorm:"primaryKey"`
+	Text         string         `gorm:"not null;uniqueIndex:idx_english_words_text_part_of_speech,where:deleted_at IS NULL"`
+	PartOfSpeech string         `gorm:"not null;default:'';uniqueIndex:idx_english_words_text_part_of_speech"`
+	SearchKey    string         `gorm:"not null;index"`
+	FoldedKey    string         `gorm:"not null;index"`
+	LookupCount  uint           `gorm:"not null;default:0"`
+	Version      uint           `gorm:"not null;default:1"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
 
 type Translation struct {
-	ID            uint        `gorm:"primaryKey"`
-	PolishWordID  uint        `gorm:"not null;index;uniqueIndex:idx_polish_english"`
-	EnglishWordID uint        `gorm:"not null;index;uniqueIndex:idx_polish_english"`
-	PolishWord    PolishWord  `gorm:"foreignKey:PolishWordID;constraint:OnDelete:CASCADE"`
-	EnglishWord   EnglishWord `gorm:"foreignKey:EnglishWordID;constraint:OnDelete:CASCADE"`
-	Examples      []Example   `gorm:"foreignKey:TranslationID"`
+	ID            uint           `gorm:"primaryKey"`
+	PolishWordID  uint           `gorm:"not null;index;uniqueIndex:idx_polish_english,where:deleted_at IS NULL"`
+	EnglishWordID uint           `gorm:"not null;index;uniqueIndex:idx_polish_english"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	PolishWord    PolishWord     `gorm:"foreignKey:PolishWordID;constraint:OnDelete:CASCADE"`
+	EnglishWord   EnglishWord    `gorm:"foreignKey:EnglishWordID;constraint:OnDelete:CASCADE"`
+	Examples      []Example      `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
 }
 
 type Example struct {
+	ID            uint           `gorm:"primaryKey"`
+	TranslationID uint           `gorm:"not null;index;uniqueIndex:idx_translation_text,where:deleted_at IS NULL"`
+	Text          string         `gorm:"not null;uniqueIndex:idx_translation_text"`
+	InPolish      bool           `gorm:"not null"`
+	SearchWords   string         `gorm:"not null;default:''"`
+	Version       uint           `gorm:"not null;default:1"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	Translation   Translation    `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+}
+
+// UpdateSearchWords derives the keys of the words of Text, which the search
+// of databases without full-text search matches.
+func (example *Example) UpdateSearchWords() {
+	example.SearchWords = normalize.WordKeys(example.Text)
+}
+
+func (example *Example) BeforeSave(tx *gorm.DB) error {
+	example.UpdateSearchWords()
+	return nil
+}
+
+// UpdateSearchKeys derives the normalized lookup keys from Text.
+func (word *PolishWord) UpdateSearchKeys() {
+	word.SearchKey = normalize.Key(word.Text)
+	word.FoldedKey = normalize.FoldedKey(word.Text)
+}
+
+func (word *PolishWord) BeforeSave(tx *gorm.DB) error {
+	word.UpdateSearchKeys()
+	return nil
+}
+
+// UpdateSearchKeys derives the normalized lookup keys from Text.
+func (word *EnglishWord) UpdateSearchKeys() {
+	word.SearchKey = normalize.Key(word.Text)
+	word.FoldedKey = normalize.FoldedKey(word.Text)
+}
+
+func (word *EnglishWord) BeforeSave(tx *gorm.DB) error {
+	word.UpdateSearchKeys()
+	return nil
+}
+
+// WordForm is an inflected form of a Polish word, tagged with the grammatical
+// categories it expresses. Like grammatical values of words, empty tags are
+// unspecified.
+type WordForm struct {
+	ID              uint       `gorm:"primaryKey"`
+	PolishWordID    uint       `gorm:"not null;index;uniqueIndex:idx_word_forms_unique"`
+	Text            string     `gorm:"not null;uniqueIndex:idx_word_forms_unique"`
+	GrammaticalCase string     `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	Number          string     `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	Person          string     `gorm:"not null;default:'';uniqueIndex:idx_word_forms_unique"`
+	SearchKey       string     `gorm:"not null;index"`
+	FoldedKey       string     `gorm:"not null;index"`
+	PolishWord      PolishWord `gorm:"foreignKey:PolishWordID;constraint:OnDelete:CASCADE"`
+}
+
+// UpdateSearchKeys derives the normalized lookup keys from Text.
+func (form *WordForm) UpdateSearchKeys() {
+	form.SearchKey = normalize.Key(form.Text)
+	form.FoldedKey = normalize.FoldedKey(form.Text)
+}
+
+func (form *WordForm) BeforeSave(tx *gorm.DB) error {
+	form.UpdateSearchKeys()
+	return nil
+}
+
+// ReviewCard is the spaced repetition state of a learner for a translation
+// asked in one direction, named like the GraphQL enum. Learner is an opaque
+// identifier of whoever studies. A card without ID has never been reviewed.
+type ReviewCard struct {
+	ID             uint      `gorm:"primaryKey"`
+	Learner        string    `gorm:"not null;uniqueIndex:idx_review_cards_unique;index:idx_review_cards_due,priority:1"`
+	TranslationID  uint      `gorm:"not null;index;uniqueIndex:idx_review_cards_unique"`
+	Direction      string    `gorm:"not null;uniqueIndex:idx_review_cards_unique"`
+	EaseFactor     float64   `gorm:"not null"`
+	IntervalDays   int       `gorm:"not null"`
+	Repetitions    int       `gorm:"not null"`
+	Lapses         int       `gorm:"not null"`
+	DueAt          time.Time `gorm:"not null;index:idx_review_cards_due,priority:2"`
+	LastReviewedAt *time.Time
+	Translation    Translation `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+}
+
+// TranslationTag groups translations, e.g. by lesson. Names are stored
+// normalized by normalize.Tag.
+type TranslationTag struct {
 	ID            uint        `gorm:"primaryKey"`
-	TranslationID uint        `gorm:"not null;index;uniqueIndex:idx_translation_text"`
-	Text          string      `gorm:"not null;uniqueIndex:idx_translation_text"`
-	InPolish      bool        `gorm:"not null"`
+	TranslationID uint        `gorm:"not null;index;uniqueIndex:idx_translation_tags_unique"`
+	Name          string      `gorm:"not null;index;uniqueIndex:idx_translation_tags_unique"`
 	Translation   Translation `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
 }
+
+// User can sign in to change the dictionary. PasswordHash is a bcrypt hash,
+// the password itself is never stored. Role is READER, EDITOR or ADMIN.
+type User struct {
+	ID           uint   `gorm:"primaryKey"`
+	Username     string `gorm:"not null;uniqueIndex"`
+	PasswordHash string `gorm:"not null"`
+	Role         string `gorm:"not null;default:'READER'"`
+	CreatedAt    time.Time
+}
+
+// APIKey lets scripts act as its user. Only the SHA-256 hash of the key is
+// stored, Prefix is its start shown to tell keys apart.
+type APIKey struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Name      string `gorm:"not null"`
+	Prefix    string `gorm:"not null"`
+	Hash      string `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time
+	User      User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// AuditEntry records a change made by a mutation. EntityType is named like
+// the AuditEntityType enum. Before and After hold the entity as JSON, Before
+// is nil for creations and After for deletions. Actor is the username of who
+// made the change, empty without authentication.
+type AuditEntry struct {
+	ID         uint      `gorm:"primaryKey"`
+	Actor      string    `gorm:"not null"`
+	OccurredAt time.Time `gorm:"not null;index"`
+	Operation  string    `gorm:"not null"`
+	EntityType string    `gorm:"not null;index:idx_audit_entries_entity,priority:1"`
+	EntityID   uint      `gorm:"not null;index:idx_audit_entries_entity,priority:2"`
+	Before     *string
+	After      *string
+}
+
+// Revision is what every revision of a word, translation or example records
+// besides the entity. Versions of an entity count from 1. A deleted revision
+// holds the entity as it was deleted, DeletedWith then names the deletion of
+// another entity that removed it along and is empty for the deleted entity
+// itself. Actor is like in AuditEntry.
+type Revision struct {
+	Version     uint      `gorm:"not null;uniqueIndex:,composite:version,priority:2"`
+	Deleted     bool      `gorm:"not null;default:false"`
+	DeletedWith string    `gorm:"not null;default:'';index"`
+	Actor       string    `gorm:"not null"`
+	RecordedAt  time.Time `gorm:"not null"`
+}
+
+// Meta returns the revision of the revision types embedding it.
+func (revision *Revision) Meta() *Revision {
+	return revision
+}
+
+// PolishWordRevision is a version of a Polish word, with the columns of the
+// word that can change.
+type PolishWordRevision struct {
+	ID           uint   `gorm:"primaryKey"`
+	PolishWordID uint   `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	Text         string `gorm:"not null"`
+	PartOfSpeech string `gorm:"not null;default:''"`
+	Gender       string `gorm:"not null;default:''"`
+	Aspect       string `gorm:"not null;default:''"`
+	Revision     `gorm:"embedded"`
+}
+
+type EnglishWordRevision struct {
+	ID            uint   `gorm:"primaryKey"`
+	EnglishWordID uint   `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	Text          string `gorm:"not null"`
+	PartOfSpeech  string `gorm:"not null;default:''"`
+	Revision      `gorm:"embedded"`
+}
+
+type TranslationRevision struct {
+	ID            uint `gorm:"primaryKey"`
+	TranslationID uint `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	PolishWordID  uint `gorm:"not null"`
+	EnglishWordID uint `gorm:"not null"`
+	Revision      `gorm:"embedded"`
+}
+
+type ExampleRevision struct {
+	ID            uint   `gorm:"primaryKey"`
+	ExampleID     uint   `gorm:"not null;uniqueIndex:,composite:version,priority:1"`
+	TranslationID uint   `gorm:"not null"`
+	Text          string `gorm:"not null"`
+	InPolish      bool   `gorm:"not null"`
+	Revision      `gorm:"embedded"`
+}
@@ -0,0 +1,81 @@
+// Package normalize derives the search keys words are looked up by.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Key returns the case-insensitive search key of text: its NFC form in lower
+// case.
+func Key(text string) string {
+	return strings.ToLower(Text(text))
+}
+
+// FoldedKey returns the search key of text with diacritics removed, so that
+// "Żółw" and "zolw" share the same key.
+func FoldedKey(text string) string {
+	return Key(Fold(text))
+}
+
+// IsWordRune reports whether r belongs to a word: letters, digits and the
+// marks combining with them.
+func IsWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// WordKeys returns the keys of the words of text, each preceded by a space,
+// so that a LIKE pattern can match the start of any of them.
+func WordKeys(text string) string {
+	var keys strings.Builder
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !IsWordRune(r) }) {
+		keys.WriteString(" " + Key(word))
+	}
+	return keys.String()
+}
+
+// Tag returns the name a tag is stored and looked up by, the key of the
+// trimmed name.
+func Tag(name string) string {
+	return Key(strings.TrimSpace(name))
+}
+
+// Fold removes diacritics from text but keeps its case. Letters with a
+// stroke, like the Polish ł, have no decomposition and are mapped by hand.
+func Fold(text string) string {
+	// Transformers keep state between calls, so the chain is built per call.
+	folded, _, _ := transform.String(transform.Chain(
+		norm.NFD,
+		runes.Remove(runes.In(unicode.Mn)),
+		runes.Map(foldStroke),
+		norm.NFC,
+	), text)
+	return folded
+}
+
+func foldStroke(r rune) rune {
+	switch r {
+	case 'ł':
+		return 'l'
+	case 'Ł':
+		return 'L'
+	case 'đ':
+		return 'd'
+	case 'Đ':
+		return 'D'
+	case 'ø':
+		return 'o'
+	case 'Ø':
+		return 'O'
+	}
+	return r
+}
+
+// Text returns the NFC form of text.
+func Text(text string) string {
+	return norm.NFC.String(text)
+}
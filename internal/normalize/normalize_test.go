@@ -0,0 +1,29 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "house", Key("House"))
+	assert.Equal(t, "żółw", Key("Żółw"))
+	// "ż" written as "z" followed by a combining dot above
+	assert.Equal(t, "żółw", Key("Z\u0307ółw"))
+}
+
+func TestFold(t *testing.T) {
+	assert.Equal(t, "Zolw", Fold("Żółw"))
+	assert.Equal(t, "zazolc gesla jazn", Fold("zażółć gęślą jaźń"))
+	assert.Equal(t, "LODZ", Fold("ŁÓDŹ"))
+}
+
+func TestFoldedKey(t *testing.T) {
+	assert.Equal(t, "zolw", FoldedKey("Żółw"))
+	assert.Equal(t, FoldedKey("zolw"), FoldedKey("ŻÓŁW"))
+}
+
+func TestTag(t *testing.T) {
+	assert.Equal(t, "lekcja 1", Tag("  Lekcja 1 "))
+}
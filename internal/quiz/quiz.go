@@ -0,0 +1,346 @@
+// Package quiz builds multiple-choice quizzes from the dictionary and grades
+// the answers given to them.
+package quiz
+
+import (
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/realagmag/dictionaryGO/internal/normalize"
+)
+
+const (
+	DefaultSize    = 10
+	MaxSize        = 100
+	DefaultChoices = 4
+	MinChoices     = 2
+	MaxChoices     = 10
+
+	// Blank replaces the answer in cloze sentences.
+	Blank = "____"
+
+	// candidatePoolSize is how many words of the answers' language a quiz
+	// loads to pick its distractors from.
+	candidatePoolSize = 1000
+)
+
+// Options select the questions of a quiz. Direction is named like the
+// StudyDirection GraphQL enum, an empty Tag takes all translations. Quizzes
+// generated with the same Seed from the same data are identical.
+type Options struct {
+	Size      int
+	Choices   int
+	Direction string
+	Tag       string
+	Seed      uint64
+}
+
+// Question asks for the translation of Prompt, Answer is one of Choices.
+// Cloze is an example of the translation with the answer blanked out, empty
+// when no example contains it.
+type Question struct {
+	TranslationID uint
+	Direction     string
+	Prompt        string
+	Answer        string
+	Choices       []string
+	Cloze         string
+}
+
+type candidate struct {
+	id           uint
+	text         string
+	partOfSpeech string
+}
+
+func (options Options) validate() error {
+	if options.Size < 1 || options.Size > MaxSize {
+		return customErrors.ErrInvalidQuizSize
+	}
+	if options.Choices < MinChoices || options.Choices > MaxChoices {
+		return customErrors.ErrInvalidChoices
+	}
+	if !model.StudyDirection(options.Direction).IsValid() {
+		return customErrors.ErrUnknownDirection
+	}
+	return nil
+}
+
+// Generate picks up to Size random translations and asks for each of them in
+// Direction. Distractors are other words of the answer's language, preferring
+// the answer's part of speech and length among a sample of candidatePoolSize
+// words. Words that also translate the prompt are never offered as
+// distractors.
+func Generate(store database.DictionaryStore, options Options) ([]*Question, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewPCG(options.Seed, options.Seed))
+
+	translations, err := store.SampleTranslations(options.Tag, options.Size, options.Seed)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.PopulateTranslationsWithAssociations(translations); err != nil {
+		return nil, err
+	}
+
+	toEnglish := options.Direction == string(model.StudyDirectionPolishToEnglish)
+	answersOf, err := loadAnswers(store, translations, toEnglish)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := loadForms(store, translations, toEnglish)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := loadPool(store, toEnglish, options.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]*Question, len(translations))
+	for i, translation := range translations {
+		question := &Question{TranslationID: translation.ID, Direction: options.Direction}
+		var promptID uint
+		var answer candidate
+		if toEnglish {
+			promptID, question.Prompt = translation.PolishWordID, translation.PolishWord.Text
+			answer = candidate{translation.EnglishWordID, translation.EnglishWord.Text, translation.EnglishWord.PartOfSpeech}
+		} else {
+			promptID, question.Prompt = translation.EnglishWordID, translation.EnglishWord.Text
+			answer = candidate{translation.PolishWordID, translation.PolishWord.Text, translation.PolishWord.PartOfSpeech}
+		}
+		question.Answer = answer.text
+		candidates := closestCandidates(pool, answer, rng.Uint64(), 2*options.Choices+len(answersOf[promptID]))
+		question.Choices = append(distractors(rng, candidates, answer, answersOf[promptID], options.Choices-1), answer.text)
+		rng.Shuffle(len(question.Choices), func(i, j int) {
+			question.Choices[i], question.Choices[j] = question.Choices[j], question.Choices[i]
+		})
+		question.Cloze = cloze(translation.Examples, !toEnglish, append([]string{answer.text}, forms[answer.id]...))
+		questions[i] = question
+	}
+	return questions, nil
+}
+
+// loadPool returns the words of the answers' language the candidates of
+// every question are taken from.
+func loadPool(store database.DictionaryStore, english bool, seed uint64) ([]*database.QuizWord, error) {
+	language := model.LanguagePolish
+	if english {
+		language = model.LanguageEnglish
+	}
+	return store.GetQuizWords(language, seed, candidatePoolSize)
+}
+
+// closestCandidates returns up to limit words of pool that are closest to
+// answer, the ones distractors are picked from.
+func closestCandidates(pool []*database.QuizWord, answer candidate, seed uint64, limit int) []candidate {
+	words := slices.Clone(pool)
+	database.SortQuizWords(words, answer.partOfSpeech, utf8.RuneCountInString(answer.text), seed)
+	words = words[:min(limit, len(words))]
+	candidates := make([]candidate, len(words))
+	for i, word := range words {
+		candidates[i] = candidate{word.ID, word.Text, word.PartOfSpeech}
+	}
+	return candidates
+}
+
+// loadAnswers maps the ID of every prompt word of translations to the IDs of
+// the words that translate it.
+func loadAnswers(store database.DictionaryStore, translations []*dbModels.Translation, toEnglish bool) (map[uint][]uint, error) {
+	answers := make(map[uint][]uint)
+	if len(translations) == 0 {
+		return answers, nil
+	}
+	ids := make([]uint, len(translations))
+	for i, translation := range translations {
+		if toEnglish {
+			ids[i] = translation.PolishWordID
+		} else {
+			ids[i] = translation.EnglishWordID
+		}
+	}
+	var all []*dbModels.Translation
+	var err error
+	if toEnglish {
+		all, err = store.GetTranslationsByPolishWordIds(ids)
+	} else {
+		all, err = store.GetTranslationsByEnglishWordIds(ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, translation := range all {
+		if toEnglish {
+			answers[translation.PolishWordID] = append(answers[translation.PolishWordID], translation.EnglishWordID)
+		} else {
+			answers[translation.EnglishWordID] = append(answers[translation.EnglishWordID], translation.PolishWordID)
+		}
+	}
+	return answers, nil
+}
+
+// loadForms returns the inflected forms of the Polish answers, so they can
+// be blanked out in cloze sentences too.
+func loadForms(store database.DictionaryStore, translations []*dbModels.Translation, toEnglish bool) (map[uint][]string, error) {
+	forms := make(map[uint][]string)
+	if toEnglish || len(translations) == 0 {
+		return forms, nil
+	}
+	ids := make([]uint, len(translations))
+	for i, translation := range translations {
+		ids[i] = translation.PolishWordID
+	}
+	wordForms, err := store.GetWordFormsByPolishWordIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, form := range wordForms {
+		forms[form.PolishWordID] = append(forms[form.PolishWordID], form.Text)
+	}
+	return forms, nil
+}
+
+type ranked struct {
+	candidate
+	mismatch   int
+	lengthDiff int
+	tiebreak   uint64
+}
+
+func (r ranked) less(other ranked) bool {
+	if r.mismatch != other.mismatch {
+		return r.mismatch < other.mismatch
+	}
+	if r.lengthDiff != other.lengthDiff {
+		return r.lengthDiff < other.lengthDiff
+	}
+	return r.tiebreak < other.tiebreak
+}
+
+// distractors picks the count candidates closest to answer: first the ones
+// sharing its part of speech, then the ones closest in length, ties broken
+// at random. Candidates in excluded or spelled like a picked one are skipped.
+func distractors(rng *rand.Rand, candidates []candidate, answer candidate, excluded []uint, count int) []string {
+	answerLength := utf8.RuneCountInString(answer.text)
+	picked := make([]ranked, 0, count+1)
+	for _, c := range candidates {
+		if c.id == answer.id || slices.Contains(excluded, c.id) || normalize.Key(c.text) == normalize.Key(answer.text) {
+			continue
+		}
+		r := ranked{candidate: c, tiebreak: rng.Uint64()}
+		if c.partOfSpeech != answer.partOfSpeech {
+			r.mismatch = 1
+		}
+		r.lengthDiff = utf8.RuneCountInString(c.text) - answerLength
+		if r.lengthDiff < 0 {
+			r.lengthDiff = -r.lengthDiff
+		}
+
+		same := slices.IndexFunc(picked, func(p ranked) bool { return normalize.Key(p.text) == normalize.Key(c.text) })
+		if same >= 0 {
+			if !r.less(picked[same]) {
+				continue
+			}
+			picked = slices.Delete(picked, same, same+1)
+		}
+		at, _ := slices.BinarySearchFunc(picked, r, func(p, r ranked) int {
+			if p.less(r) {
+				return -1
+			}
+			return 1
+		})
+		picked = slices.Insert(picked, at, r)
+		if len(picked) > count {
+			picked = picked[:count]
+		}
+	}
+
+	texts := make([]string, len(picked))
+	for i, p := range picked {
+		texts[i] = p.text
+	}
+	return texts
+}
+
+// cloze blanks out the first of words found in an example of the language,
+// trying longer words first so a form is not blanked out only in part.
+func cloze(examples []dbModels.Example, inPolish bool, words []string) string {
+	words = slices.Clone(words)
+	slices.SortStableFunc(words, func(a, b string) int { return len(b) - len(a) })
+	for _, example := range examples {
+		if example.InPolish != inPolish {
+			continue
+		}
+		for _, word := range words {
+			pattern := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(word) + `($|[^\p{L}\p{N}])`)
+			if pattern.MatchString(example.Text) {
+				return pattern.ReplaceAllString(example.Text, "${1}"+Blank+"${2}")
+			}
+		}
+	}
+	return ""
+}
+
+// Answer is the choice made for the question about a translation.
+type Answer struct {
+	TranslationID uint
+	Direction     string
+	Answer        string
+}
+
+type GradedAnswer struct {
+	Answer
+	Correct       bool
+	CorrectAnswer string
+}
+
+type Result struct {
+	Correct int
+	Answers []GradedAnswer
+}
+
+// Score is the share of correct answers, zero for no answers.
+func (result *Result) Score() float64 {
+	if len(result.Answers) == 0 {
+		return 0
+	}
+	return float64(result.Correct) / float64(len(result.Answers))
+}
+
+// Grade checks answers against the translations they are about. Answers
+// match regardless of case and surrounding space.
+func Grade(store database.DictionaryStore, answers []Answer) (*Result, error) {
+	translations := make([]*dbModels.Translation, len(answers))
+	for i, answer := range answers {
+		if !model.StudyDirection(answer.Direction).IsValid() {
+			return nil, customErrors.ErrUnknownDirection
+		}
+		translations[i] = &dbModels.Translation{ID: answer.TranslationID}
+	}
+	if err := store.PopulateTranslationsWithAssociations(translations); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Answers: make([]GradedAnswer, len(answers))}
+	for i, answer := range answers {
+		graded := GradedAnswer{Answer: answer, CorrectAnswer: translations[i].PolishWord.Text}
+		if answer.Direction == string(model.StudyDirectionPolishToEnglish) {
+			graded.CorrectAnswer = translations[i].EnglishWord.Text
+		}
+		graded.Correct = normalize.Key(strings.TrimSpace(answer.Answer)) == normalize.Key(graded.CorrectAnswer)
+		if graded.Correct {
+			result.Correct++
+		}
+		result.Answers[i] = graded
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package quiz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noun = model.PartOfSpeechNoun
+	verb = model.PartOfSpeechVerb
+)
+
+func newTestStore() *memstore.Store {
+	store := memstore.NewStore()
+	for _, input := range []model.TranslationInput{
+		{PolishWord: "pies", EnglishWord: "dog", PartOfSpeech: &noun, Examples: []*model.ExampleInput{
+			{Text: "Mam psa.", InPolish: true},
+			{Text: "The dog barks.", InPolish: false},
+		}},
+		{PolishWord: "kot", EnglishWord: "cat", PartOfSpeech: &noun},
+		{PolishWord: "zamek", EnglishWord: "castle", PartOfSpeech: &noun},
+		{PolishWord: "zamek", EnglishWord: "lock", PartOfSpeech: &noun},
+		{PolishWord: "biegać", EnglishWord: "run", PartOfSpeech: &verb},
+		{PolishWord: "koń", EnglishWord: "horse", PartOfSpeech: &noun},
+	} {
+		store.AddTranslation(input)
+	}
+	return store
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	store := newTestStore()
+	options := Options{Size: 4, Choices: 3, Direction: string(model.StudyDirectionPolishToEnglish), Seed: 7}
+
+	questions, err := Generate(store, options)
+	assert.NoError(t, err)
+	assert.Len(t, questions, 4)
+	again, _ := Generate(store, options)
+	assert.Equal(t, questions, again)
+
+	for _, question := range questions {
+		assert.Len(t, question.Choices, 3)
+		assert.Contains(t, question.Choices, question.Answer)
+		if question.Answer != "run" {
+			assert.NotContains(t, question.Choices, "run", "the only verb is never closest to a noun")
+		}
+		if question.Prompt == "zamek" {
+			assert.NotContains(t, question.Choices, map[string]string{"castle": "lock", "lock": "castle"}[question.Answer])
+		}
+	}
+}
+
+func TestGenerateCloze(t *testing.T) {
+	store := newTestStore()
+	dog, _ := store.FindTranslation(model.TranslationInput{PolishWord: "pies", EnglishWord: "dog", PartOfSpeech: &noun})
+	store.SetPolishWordForms(dog.PolishWordID, []dbModels.WordForm{{Text: "psa"}}, false)
+	store.SetTranslationTags(dog.ID, []string{"Animals"})
+
+	questions, err := Generate(store, Options{Size: 10, Choices: 2, Direction: string(model.StudyDirectionPolishToEnglish), Tag: "animals"})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1)
+	assert.Equal(t, "pies", questions[0].Prompt)
+	assert.Equal(t, "The ____ barks.", questions[0].Cloze)
+
+	questions, _ = Generate(store, Options{Size: 10, Choices: 2, Direction: string(model.StudyDirectionEnglishToPolish), Tag: "animals"})
+	assert.Equal(t, "Mam ____.", questions[0].Cloze)
+	assert.Len(t, questions[0].Choices, 2)
+}
+
+// wholeTableStore fails the calls loading whole tables.
+type wholeTableStore struct {
+	database.DictionaryStore
+}
+
+func (wholeTableStore) GetTranslations() ([]*dbModels.Translation, error) {
+	return nil, errors.New("loaded all translations")
+}
+
+func (wholeTableStore) GetPolishWords() ([]*dbModels.PolishWord, error) {
+	return nil, errors.New("loaded all Polish words")
+}
+
+func (wholeTableStore) GetEnglishWords() ([]*dbModels.EnglishWord, error) {
+	return nil, errors.New("loaded all English words")
+}
+
+func TestGenerateLoadsOnlyWhatItAsks(t *testing.T) {
+	store := wholeTableStore{newTestStore()}
+	for _, direction := range []model.StudyDirection{model.StudyDirectionPolishToEnglish, model.StudyDirectionEnglishToPolish} {
+		questions, err := Generate(store, Options{Size: 2, Choices: 3, Direction: string(direction), Seed: 3})
+		assert.NoError(t, err)
+		assert.Len(t, questions, 2)
+		for _, question := range questions {
+			assert.Len(t, question.Choices, 3)
+		}
+	}
+}
+
+// poolCountingStore counts the calls loading the words choices are picked from.
+type poolCountingStore struct {
+	database.DictionaryStore
+	calls *int
+}
+
+func (s poolCountingStore) GetQuizWords(language model.Language, seed uint64, limit int) ([]*database.QuizWord, error) {
+	*s.calls++
+	return s.DictionaryStore.GetQuizWords(language, seed, limit)
+}
+
+func TestGenerateLoadsCandidatesOncePerQuiz(t *testing.T) {
+	calls := 0
+	questions, err := Generate(poolCountingStore{newTestStore(), &calls}, Options{Size: 5, Choices: 3, Direction: string(model.StudyDirectionPolishToEnglish), Seed: 3})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 5)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGenerateValidatesOptions(t *testing.T) {
+	store := newTestStore()
+	direction := string(model.StudyDirectionPolishToEnglish)
+
+	_, err := Generate(store, Options{Size: 0, Choices: 4, Direction: direction})
+	assert.Equal(t, customErrors.ErrInvalidQuizSize, err)
+	_, err = Generate(store, Options{Size: 5, Choices: 1, Direction: direction})
+	assert.Equal(t, customErrors.ErrInvalidChoices, err)
+	_, err = Generate(store, Options{Size: 5, Choices: 4, Direction: "SIDEWAYS"})
+	assert.Equal(t, customErrors.ErrUnknownDirection, err)
+}
+
+func TestGrade(t *testing.T) {
+	store := newTestStore()
+	cat, _ := store.FindTranslation(model.TranslationInput{PolishWord: "kot", EnglishWord: "cat", PartOfSpeech: &noun})
+
+	result, err := Grade(store, []Answer{
+		{TranslationID: cat.ID, Direction: string(model.StudyDirectionPolishToEnglish), Answer: " Cat "},
+		{TranslationID: cat.ID, Direction: string(model.StudyDirectionEnglishToPolish), Answer: "pies"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Correct)
+	assert.True(t, result.Answers[0].Correct)
+	assert.False(t, result.Answers[1].Correct)
+	assert.Equal(t, "kot", result.Answers[1].CorrectAnswer)
+	assert.Equal(t, 0.5, result.Score())
+
+	_, err = Grade(store, []Answer{{TranslationID: 99, Direction: string(model.StudyDirectionPolishToEnglish)}})
+	assert.Equal(t, customErrors.ErrTranslationNotFound, err)
+}
@@ -0,0 +1,89 @@
+// Package srs schedules reviews of translations with the SM-2 spaced
+// repetition algorithm.
+package srs
+
+import (
+	"math"
+	"time"
+
+	"github.com/realagmag/dictionaryGO/graph/model"
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	dbModels "github.com/realagmag/dictionaryGO/internal/models"
+)
+
+// Clock tells the current time. Production code uses time.Now, tests a
+// fixed time so schedules are deterministic.
+type Clock func() time.Time
+
+// Grades rate how well an answer was recalled, from MinGrade (blackout) to
+// MaxGrade (perfect). Grades below PassingGrade count as forgotten.
+const (
+	MinGrade     = 0
+	PassingGrade = 3
+	MaxGrade     = 5
+
+	InitialEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+)
+
+// Day is the unit of review intervals.
+const Day = 24 * time.Hour
+
+func ValidateGrade(grade int) error {
+	if grade < MinGrade || grade > MaxGrade {
+		return customErrors.ErrInvalidGrade
+	}
+	return nil
+}
+
+func ValidateDirection(direction string) error {
+	if !model.StudyDirection(direction).IsValid() {
+		return customErrors.ErrUnknownDirection
+	}
+	return nil
+}
+
+// NewCard is the state of a card never reviewed, due right away. Times are
+// kept in UTC, so stores compare them the same way.
+func NewCard(learner string, translationID uint, direction string, now time.Time) dbModels.ReviewCard {
+	now = now.UTC()
+	return dbModels.ReviewCard{
+		Learner:       learner,
+		TranslationID: translationID,
+		Direction:     direction,
+		EaseFactor:    InitialEaseFactor,
+		DueAt:         now,
+	}
+}
+
+// Review schedules the next review of card answered with grade at now. A
+// passed card is asked again after 1 day, then 6 days, then the previous
+// interval times its ease factor. A forgotten card starts over at 1 day.
+func Review(card *dbModels.ReviewCard, grade int, now time.Time) error {
+	if err := ValidateGrade(grade); err != nil {
+		return err
+	}
+	if grade >= PassingGrade {
+		switch card.Repetitions {
+		case 0:
+			card.IntervalDays = 1
+		case 1:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+		card.Repetitions++
+	} else {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+		card.Lapses++
+	}
+
+	now = now.UTC()
+	miss := float64(MaxGrade - grade)
+	card.EaseFactor = math.Max(MinEaseFactor, card.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	reviewedAt := now
+	card.LastReviewedAt = &reviewedAt
+	card.DueAt = now.Add(time.Duration(card.IntervalDays) * Day)
+	return nil
+}
@@ -0,0 +1,63 @@
+package srs
+
+import (
+	"testing"
+	"time"
+
+	customErrors "github.com/realagmag/dictionaryGO/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestReviewSchedulesPassedCards(t *testing.T) {
+	card := NewCard("ala", 1, "POLISH_TO_ENGLISH", now)
+	assert.Equal(t, now, card.DueAt)
+	assert.Equal(t, InitialEaseFactor, card.EaseFactor)
+
+	assert.NoError(t, Review(&card, 5, now))
+	assert.Equal(t, 1, card.IntervalDays)
+	assert.Equal(t, 1, card.Repetitions)
+	assert.InDelta(t, 2.6, card.EaseFactor, 1e-9)
+	assert.Equal(t, now.Add(Day), card.DueAt)
+	assert.Equal(t, now, *card.LastReviewedAt)
+
+	assert.NoError(t, Review(&card, 4, card.DueAt))
+	assert.Equal(t, 6, card.IntervalDays)
+	assert.InDelta(t, 2.6, card.EaseFactor, 1e-9)
+
+	assert.NoError(t, Review(&card, 3, card.DueAt))
+	assert.Equal(t, 16, card.IntervalDays)
+	assert.InDelta(t, 2.46, card.EaseFactor, 1e-9)
+	assert.Equal(t, now.Add(23*Day), card.DueAt)
+	assert.Equal(t, 3, card.Repetitions)
+	assert.Zero(t, card.Lapses)
+}
+
+func TestReviewStartsForgottenCardsOver(t *testing.T) {
+	card := NewCard("ala", 1, "POLISH_TO_ENGLISH", now)
+	card.Repetitions, card.IntervalDays = 4, 40
+
+	assert.NoError(t, Review(&card, 1, now))
+	assert.Zero(t, card.Repetitions)
+	assert.Equal(t, 1, card.IntervalDays)
+	assert.Equal(t, 1, card.Lapses)
+	assert.InDelta(t, 1.96, card.EaseFactor, 1e-9)
+
+	for i := 0; i < 5; i++ {
+		Review(&card, 0, now)
+	}
+	assert.Equal(t, MinEaseFactor, card.EaseFactor)
+}
+
+func TestReviewRejectsInvalidGrades(t *testing.T) {
+	card := NewCard("ala", 1, "POLISH_TO_ENGLISH", now)
+	assert.Equal(t, customErrors.ErrInvalidGrade, Review(&card, 6, now))
+	assert.Equal(t, customErrors.ErrInvalidGrade, Review(&card, -1, now))
+	assert.Zero(t, card.Repetitions)
+}
+
+func TestValidateDirection(t *testing.T) {
+	assert.NoError(t, ValidateDirection("ENGLISH_TO_POLISH"))
+	assert.Equal(t, customErrors.ErrUnknownDirection, ValidateDirection("SIDEWAYS"))
+}
@@ -1,11 +1,68 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+
 	"github.com/realagmag/dictionaryGO/config"
 	"github.com/realagmag/dictionaryGO/graph"
+	"github.com/realagmag/dictionaryGO/internal/cli"
+	"github.com/realagmag/dictionaryGO/internal/database"
+	"github.com/realagmag/dictionaryGO/internal/memstore"
 )
 
 func main() {
+	demo := flag.Bool("demo", false, "serve from an in-memory store instead of the database")
+	flag.Parse()
+
+	switch command := flag.Arg(0); command {
+	case "":
+	case "migrate":
+		config.ConnectDB()
+		if err := cli.Migrate(config.DB, flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "import":
+		config.InitDB()
+		if err := cli.Import(database.NewDBManager(config.DB), flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "import-kaikki":
+		config.InitDB()
+		if err := cli.ImportKaikki(database.NewDBManager(config.DB), flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "create-user":
+		config.InitDB()
+		if err := cli.CreateUser(database.NewDBManager(config.DB), flag.Args()[1:], os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "export":
+		config.InitDB()
+		if err := cli.Export(database.NewDBManager(config.DB), flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	default:
+		log.Fatalf("unknown command %q", command)
+	}
+
+	if *demo {
+		log.Println("Running in demo mode, data will be lost on exit")
+		store := memstore.NewStore()
+		key, err := cli.DemoAPIKey(store)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Send the header \"X-API-Key: %s\" to change the dictionary", key)
+		graph.StartServer(store)
+		return
+	}
 	config.InitDB()
-	graph.StartServer()
+	graph.StartServer(database.NewDBManager(config.DB))
 }